@@ -5,6 +5,8 @@ import (
 	"flag"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
 	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
@@ -13,21 +15,33 @@ import (
 
 	"github.com/crudboxin/crudbox/internal/database"
 	"github.com/crudboxin/crudbox/internal/handler"
+	"github.com/crudboxin/crudbox/internal/jwtkeys"
+	"github.com/crudboxin/crudbox/internal/mail"
 	"github.com/crudboxin/crudbox/internal/middleware"
+	"github.com/crudboxin/crudbox/internal/oidc"
 	"github.com/crudboxin/crudbox/internal/repository"
+	"github.com/crudboxin/crudbox/internal/repository/memory"
 	"github.com/crudboxin/crudbox/internal/service"
 	"github.com/crudboxin/crudbox/pkg/config"
 )
 
 func main() {
 	runMigrations := flag.Bool("run-migrations", false, "apply database migrations before starting the server")
+	purgeTrash := flag.Bool("purge-trash", false, "purge expired trash once and exit instead of starting the server")
 	flag.Parse()
 
 	// Load configuration
 	cfg := config.Load()
 
+	// Check the configuration before touching the database or its schema
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Refusing to start: ", err)
+	}
+
 	// Initialize database
 	dbConfig := &database.Config{
+		Driver:   cfg.DB.Driver,
+		Path:     cfg.DB.Path,
 		Host:     cfg.DB.Host,
 		Port:     cfg.DB.Port,
 		User:     cfg.DB.User,
@@ -36,32 +50,74 @@ func main() {
 		SSLMode:  cfg.DB.SSLMode,
 	}
 
-	db, err := database.NewConnection(dbConfig)
+	// Initialize repositories
+	repos, closeDB := openRepositories(dbConfig, *runMigrations)
+	defer closeDB()
+
+	jwtKeys, err := loadJWTKeys(cfg)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		log.Fatal("Failed to load JWT signing keys:", err)
 	}
-	defer db.Close()
 
-	if *runMigrations {
-		if err := database.RunMigrations(db); err != nil {
-			log.Fatal("Failed to run database migrations:", err)
-		}
+	// Set JWT keys for middleware
+	middleware.SetJWTKeys(jwtKeys)
+
+	// Initialize outbound mail
+	mailer, err := mail.NewSender(&mail.Config{
+		Driver:   cfg.Mail.Driver,
+		From:     cfg.Mail.From,
+		SMTPHost: cfg.Mail.SMTPHost,
+		SMTPPort: cfg.Mail.SMTPPort,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		FileDir:  cfg.Mail.FileDir,
+	})
+	if err != nil {
+		log.Fatal("Failed to configure mail:", err)
 	}
 
-	// Set JWT secret for middleware
-	middleware.SetJWTSecret([]byte(cfg.JWTSecret))
-
-	// Initialize repositories
-	repos := repository.NewRepositories(db)
+	// Single sign-on is enabled when an OIDC issuer is configured
+	var oidcProvider *oidc.Provider
+	if cfg.OIDC.IssuerURL != "" {
+		oidcProvider = oidc.NewProvider(&oidc.Config{
+			IssuerURL:    cfg.OIDC.IssuerURL,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       strings.Fields(cfg.OIDC.Scopes),
+		})
+	}
 
 	// Initialize services
-	services := service.NewServices(repos, []byte(cfg.JWTSecret))
+	mockHosts := service.NewMockHosts(cfg.MockBaseDomain, cfg.ReservedHosts()...)
+	mockHosts.CustomHostnames = cfg.CustomHostnames
+	services := service.NewServices(repos, service.AuthConfig{
+		Keys:                     jwtKeys,
+		AccessTokenTTL:           cfg.AccessTokenTTL,
+		RefreshTokenTTL:          cfg.RefreshTokenTTL,
+		RequireEmailVerification: cfg.RequireEmailVerification,
+		AppBaseURL:               cfg.AppBaseURL,
+	}, mockHosts, mailer, oidcProvider)
+
+	if *purgeTrash {
+		if err := purgeExpiredTrash(services.Project, cfg.TrashRetention); err != nil {
+			log.Fatal("Failed to purge trash:", err)
+		}
+		return
+	}
+
+	// Accept API tokens alongside JWTs and reject JWTs of revoked sessions
+	middleware.SetAPITokenAuthenticator(services.APIToken)
+	middleware.SetSessionValidator(services.User)
 
 	// Initialize handlers
 	userHandler := handler.NewUserHandler(services.User)
 	organisationHandler := handler.NewOrganisationHandler(services.Organisation)
 	projectHandler := handler.NewProjectHandler(services.Project)
-	endpointHandler := handler.NewEndpointHandler(services.Endpoint, services.Project)
+	endpointHandler := handler.NewEndpointHandler(services.Endpoint, services.Project, mockHosts)
+	apiTokenHandler := handler.NewAPITokenHandler(services.APIToken)
+	oidcHandler := handler.NewOIDCHandler(services.OIDC, cfg.AppBaseURL)
+	jwksHandler := handler.NewJWKSHandler(jwtKeys)
 
 	// Setup server
 	server := handler.NewServer(
@@ -69,10 +125,19 @@ func main() {
 		organisationHandler,
 		projectHandler,
 		endpointHandler,
+		apiTokenHandler,
+		oidcHandler,
+		jwksHandler,
 	)
 
 	// Setup routes and start server
-	router := server.SetupRoutes()
+	router, err := server.SetupRoutes(handler.ProxyConfig{
+		TrustedProxies:  cfg.TrustedProxies,
+		TrustedPlatform: cfg.TrustedPlatform,
+	})
+	if err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
 
 	if isLambdaRuntime() {
 		adapter := ginadapter.NewV2(router)
@@ -82,12 +147,75 @@ func main() {
 		return
 	}
 
+	go purgeTrashPeriodically(services.Project, cfg.TrashRetention, cfg.TrashPurgeInterval)
+
 	log.Println("Server starting on :8080")
 	if err := router.Run(":8080"); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
 
+// openRepositories connects to the configured database and returns repositories
+// on top of it together with a function that closes the connection.
+func openRepositories(dbConfig *database.Config, runMigrations bool) (*repository.Repositories, func()) {
+	if dbConfig.Driver == database.DriverMemory {
+		log.Println("Using in-memory storage; all data is lost when the server stops")
+		return memory.NewRepositories(), func() {}
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// A SQLite file belongs to this process alone, so it is always brought up to date
+	if runMigrations || dbConfig.Driver == database.DriverSQLite {
+		if err := database.RunMigrations(db); err != nil {
+			log.Fatal("Failed to run database migrations:", err)
+		}
+	}
+
+	return repository.NewRepositories(db), func() { db.Close() }
+}
+
+// loadJWTKeys returns the asymmetric keys from JWT_KEYS or JWT_KEYS_FILE, falling
+// back to HS256 with JWT_SECRET when neither is set.
+func loadJWTKeys(cfg *config.Config) (*jwtkeys.KeySet, error) {
+	switch {
+	case cfg.JWTKeys != "":
+		return jwtkeys.Load([]byte(cfg.JWTKeys), ".")
+	case cfg.JWTKeysFile != "":
+		return jwtkeys.LoadFile(cfg.JWTKeysFile)
+	default:
+		return jwtkeys.NewHMACKeySet([]byte(cfg.JWTSecret)), nil
+	}
+}
+
 func isLambdaRuntime() bool {
 	return os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "" || os.Getenv("LAMBDA_TASK_ROOT") != ""
 }
+
+func purgeExpiredTrash(projects service.ProjectService, retention time.Duration) error {
+	purgedProjects, purgedEndpoints, err := projects.PurgeTrash(retention)
+	if err != nil {
+		return err
+	}
+	if purgedProjects > 0 || purgedEndpoints > 0 {
+		log.Printf("Purged %d projects and %d endpoints from the trash", purgedProjects, purgedEndpoints)
+	}
+	return nil
+}
+
+// purgeTrashPeriodically runs for the lifetime of a long-running server. Lambda
+// deployments have no such process and run the binary with -purge-trash from a
+// scheduled job instead.
+func purgeTrashPeriodically(projects service.ProjectService, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := purgeExpiredTrash(projects, retention); err != nil {
+			log.Printf("Failed to purge trash: %v", err)
+		}
+		<-ticker.C
+	}
+}
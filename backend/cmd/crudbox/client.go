@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+const defaultServerURL = "http://localhost:8080"
+
+// apiClient calls the REST API of a crudbox server.
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// apiFlags registers the flags that select the server and credentials and
+// returns a function creating the client once the flags are parsed. Flags take
+// precedence over the environment, which takes precedence over the server and
+// token saved by crudbox login.
+func apiFlags(flags *flag.FlagSet) func() *apiClient {
+	url := flags.String("url", "", "crudbox server URL, also read from CRUDBOX_URL (default saved by login, or "+defaultServerURL+")")
+	token := flags.String("token", "", "API token, also read from CRUDBOX_TOKEN (default saved by login)")
+
+	return func() *apiClient {
+		saved, err := loadConfig()
+		if err != nil {
+			log.Fatal("Failed to read the saved login: ", err)
+		}
+		serverURL := firstNonEmpty(*url, os.Getenv("CRUDBOX_URL"), saved.URL, defaultServerURL)
+
+		// A saved token only belongs to the server it was created on
+		savedToken := ""
+		if strings.TrimSuffix(serverURL, "/") == saved.URL {
+			savedToken = saved.Token
+		}
+		return newAPIClient(serverURL, firstNonEmpty(*token, os.Getenv("CRUDBOX_TOKEN"), savedToken))
+	}
+}
+
+func newAPIClient(serverURL, token string) *apiClient {
+	return &apiClient{
+		baseURL: strings.TrimSuffix(serverURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// do sends body as JSON and decodes the response into result. Error responses
+// are returned as errors carrying the API's message.
+func (c *apiClient) do(method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	contentType := ""
+	if body != nil {
+		contentType = "application/json"
+	}
+	return c.send(method, path, contentType, reader, result)
+}
+
+// upload sends a file as the multipart form field the API's import endpoints
+// read, and decodes the response into result.
+func (c *apiClient) upload(path, field, name string, content io.Reader, result any) error {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile(field, filepath.Base(name))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return c.send(http.MethodPost, path, writer.FormDataContentType(), &form, result)
+}
+
+func (c *apiClient) send(method, path, contentType string, body io.Reader, result any) error {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		return fmt.Errorf("%s %s: not logged in, run crudbox login or pass -token", method, path)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s %s: %s", method, path, apiErr.Error)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// project finds the project with the given code among those the caller can
+// access.
+func (c *apiClient) project(code string) (*contracts.Project, error) {
+	projects, err := c.projectsByCode()
+	if err != nil {
+		return nil, err
+	}
+	project := projects[code]
+	if project == nil {
+		return nil, fmt.Errorf("project %s does not exist on the server or is not accessible with this token", code)
+	}
+	return project, nil
+}
+
+// projectsByCode returns the projects the caller can access, keyed by code.
+func (c *apiClient) projectsByCode() (map[string]*contracts.Project, error) {
+	var resp struct {
+		Projects []*contracts.Project `json:"projects"`
+	}
+	if err := c.do(http.MethodGet, "/projects", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	projects := make(map[string]*contracts.Project, len(resp.Projects))
+	for _, project := range resp.Projects {
+		projects[project.Code] = project
+	}
+	return projects, nil
+}
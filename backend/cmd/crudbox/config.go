@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// cliConfig is what crudbox login saves so later commands reach the same
+// server without passing -url and -token every time.
+type cliConfig struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+	// CreatedToken is set when login created the token, which logout then
+	// revokes. Tokens saved with login -token belong to the user and are kept.
+	CreatedToken bool `json:"created_token,omitempty"`
+}
+
+// configPath returns where the saved login is kept, which is crudbox/config.json
+// in the user's configuration directory unless CRUDBOX_CONFIG names a file.
+func configPath() (string, error) {
+	if path := os.Getenv("CRUDBOX_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "crudbox", "config.json"), nil
+}
+
+// loadConfig returns the saved login, which is empty when nobody logged in.
+func loadConfig() (*cliConfig, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &cliConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config cliConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// saveConfig writes the login readable by the current user only, since the
+// token grants the same access as the user's password.
+func saveConfig(config *cliConfig) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0o600)
+}
+
+func removeConfig() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/mockfile"
+)
+
+const endpointUsage = `Usage: crudbox endpoint <create|edit> -project <code> -file <file> [flags]
+
+  create   create the endpoints declared in the file
+  edit     update the response of existing endpoints, matched by method and path
+`
+
+// manageEndpoints creates or edits endpoints from a YAML or JSON file holding
+// either one endpoint or a list under endpoints, in the format of mock files.
+func manageEndpoints(args []string) {
+	if len(args) < 1 || (args[0] != "create" && args[0] != "edit") {
+		fmt.Fprint(os.Stderr, endpointUsage)
+		os.Exit(2)
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("endpoint "+action, flag.ExitOnError)
+	code := flags.String("project", "", "code of the project")
+	file := flags.String("file", "", "YAML or JSON file declaring the endpoints")
+	newClient := apiFlags(flags)
+	flags.Parse(args[1:])
+
+	if *code == "" || *file == "" {
+		fmt.Fprintf(os.Stderr, "crudbox endpoint %s: -project and -file are required\n", action)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	declared, err := mockfile.LoadEndpoints(*file)
+	if err != nil {
+		log.Fatal("Failed to load endpoints: ", err)
+	}
+
+	client := newClient()
+	if action == "create" {
+		createEndpoints(client, *code, declared)
+	} else {
+		editEndpoints(client, *code, declared)
+	}
+}
+
+func createEndpoints(client *apiClient, code string, declared []contracts.ProjectBundleEndpoint) {
+	project, err := client.project(code)
+	if err != nil {
+		log.Fatal("Failed to find project: ", err)
+	}
+
+	for _, endpoint := range declared {
+		req := contracts.CreateEndpointRequest{
+			Method:          endpoint.Method,
+			Path:            endpoint.Path,
+			ResponseBody:    endpoint.ResponseBody,
+			ResponseStatus:  endpoint.ResponseStatus,
+			ResponseHeaders: endpoint.ResponseHeaders,
+		}
+		var resp struct {
+			Endpoint contracts.Endpoint `json:"endpoint"`
+		}
+		if err := client.do(http.MethodPost, "/project/"+project.UUID+"/endpoint", req, &resp); err != nil {
+			log.Fatalf("Failed to create %s %s: %v", endpoint.Method, endpoint.Path, err)
+		}
+		fmt.Printf("+ %s %s %s\n", resp.Endpoint.Method, resp.Endpoint.Path, resp.Endpoint.UUID)
+	}
+}
+
+func editEndpoints(client *apiClient, code string, declared []contracts.ProjectBundleEndpoint) {
+	existing, err := projectEndpoints(client, code)
+	if err != nil {
+		log.Fatal("Failed to list endpoints: ", err)
+	}
+	byKey := make(map[string]*contracts.Endpoint, len(existing))
+	for _, endpoint := range existing {
+		byKey[endpoint.Method+" "+endpoint.Path] = endpoint
+	}
+
+	// Check every endpoint exists before changing any of them
+	for _, endpoint := range declared {
+		if byKey[endpoint.Method+" "+endpoint.Path] == nil {
+			log.Fatalf("Endpoint %s %s does not exist in project %s", endpoint.Method, endpoint.Path, code)
+		}
+	}
+
+	for _, endpoint := range declared {
+		current := byKey[endpoint.Method+" "+endpoint.Path]
+		req := contracts.UpdateEndpointRequest{
+			ResponseBody:    &endpoint.ResponseBody,
+			ResponseStatus:  &endpoint.ResponseStatus,
+			ResponseHeaders: &endpoint.ResponseHeaders,
+		}
+		if err := client.do(http.MethodPut, "/endpoint/"+current.UUID, req, nil); err != nil {
+			log.Fatalf("Failed to update %s %s: %v", endpoint.Method, endpoint.Path, err)
+		}
+		fmt.Printf("~ %s %s %s\n", endpoint.Method, endpoint.Path, current.UUID)
+	}
+}
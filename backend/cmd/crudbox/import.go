@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+// importOpenAPI previews the endpoints an OpenAPI document would create and
+// creates the new ones once confirmed, the same two steps as the dashboard.
+func importOpenAPI(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	code := flags.String("project", "", "code of the project to import into")
+	file := flags.String("file", "", "OpenAPI document in YAML or JSON")
+	yes := flags.Bool("yes", false, "create the new endpoints without asking for confirmation")
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	if *code == "" || *file == "" {
+		fmt.Fprintln(os.Stderr, "crudbox import: -project and -file are required")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	document, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("Failed to open OpenAPI document: ", err)
+	}
+	defer document.Close()
+
+	client := newClient()
+	project, err := client.project(*code)
+	if err != nil {
+		log.Fatal("Failed to find project: ", err)
+	}
+
+	var preview struct {
+		Preview contracts.OpenAPIImportPreview `json:"preview"`
+	}
+	if err := client.upload("/project/"+project.UUID+"/upload/openapiyml", "file", *file, document, &preview); err != nil {
+		log.Fatal("Failed to preview import: ", err)
+	}
+
+	var endpoints []contracts.CreateEndpointRequest
+	fmt.Printf("%d operations: %d new, %d existing, %d skipped\n",
+		preview.Preview.TotalOperations, preview.Preview.NewCount, preview.Preview.ExistingCount, preview.Preview.SkippedCount)
+	for _, operation := range preview.Preview.Operations {
+		symbol := " "
+		if operation.Status == "new" {
+			symbol = "+"
+			endpoints = append(endpoints, contracts.CreateEndpointRequest{
+				Method:          operation.Method,
+				Path:            operation.Path,
+				ResponseBody:    operation.ResponseBody,
+				ResponseStatus:  operation.ResponseStatus,
+				ResponseHeaders: operation.ResponseHeaders,
+			})
+		}
+		note := operation.Status
+		if operation.Reason != "" {
+			note += ": " + operation.Reason
+		}
+		fmt.Printf("  %s %s %s %d  (%s)\n", symbol, operation.Method, operation.Path, operation.ResponseStatus, note)
+	}
+
+	if len(endpoints) == 0 {
+		fmt.Println("Nothing to import")
+		return
+	}
+	if !*yes && !confirm(fmt.Sprintf("Create %d endpoints in %s?", len(endpoints), project.Code)) {
+		fmt.Println("Import cancelled")
+		return
+	}
+
+	var resp struct {
+		Result contracts.BulkCreateEndpointsResult `json:"result"`
+	}
+	req := contracts.BulkCreateEndpointsRequest{Endpoints: endpoints}
+	if err := client.do(http.MethodPost, "/project/"+project.UUID+"/endpoints/bulk", req, &resp); err != nil {
+		log.Fatal("Failed to import endpoints: ", err)
+	}
+
+	fmt.Printf("Created %d endpoints\n", len(resp.Result.Created))
+	for _, skipped := range resp.Result.Skipped {
+		fmt.Printf("  skipped %s %s: %s\n", skipped.Method, skipped.Path, skipped.Reason)
+	}
+}
+
+// confirm asks a yes or no question on the terminal and defaults to no.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := readLine(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// exportProject writes the project's export bundle to a file or standard
+// output. The bundle can be imported into an organisation through the API.
+func exportProject(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	code := flags.String("project", "", "code of the project to export")
+	output := flags.String("o", "", "file to write the export to (default standard output)")
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	if *code == "" {
+		fmt.Fprintln(os.Stderr, "crudbox export: -project is required")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	project, err := client.project(*code)
+	if err != nil {
+		log.Fatal("Failed to find project: ", err)
+	}
+
+	var bundle json.RawMessage
+	if err := client.do(http.MethodGet, "/project/"+project.UUID+"/export", nil, &bundle); err != nil {
+		log.Fatal("Failed to export project: ", err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bundle, "", "  "); err != nil {
+		log.Fatal("Failed to export project: ", err)
+	}
+	indented.WriteByte('\n')
+
+	if *output == "" {
+		os.Stdout.Write(indented.Bytes())
+		return
+	}
+	if err := os.WriteFile(*output, indented.Bytes(), 0o644); err != nil {
+		log.Fatal("Failed to write export: ", err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %s to %s\n", project.Code, *output)
+}
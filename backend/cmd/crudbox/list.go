@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+func listOrganisations(args []string) {
+	flags := flag.NewFlagSet("orgs", flag.ExitOnError)
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	var resp struct {
+		Organisations []*contracts.Organisation `json:"organisations"`
+	}
+	if err := newClient().do(http.MethodGet, "/organisations", nil, &resp); err != nil {
+		log.Fatal("Failed to list organisations: ", err)
+	}
+
+	table := newTable()
+	fmt.Fprintln(table, "UUID\tNAME\tROLE")
+	for _, org := range resp.Organisations {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", org.UUID, org.Name, org.Role)
+	}
+	table.Flush()
+}
+
+func listProjects(args []string) {
+	flags := flag.NewFlagSet("projects", flag.ExitOnError)
+	org := flags.String("org", "", "only list the projects of the organisation with this UUID")
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	var resp struct {
+		Projects []*contracts.Project `json:"projects"`
+	}
+	if err := newClient().do(http.MethodGet, "/projects", nil, &resp); err != nil {
+		log.Fatal("Failed to list projects: ", err)
+	}
+
+	table := newTable()
+	fmt.Fprintln(table, "CODE\tNAME\tUUID\tORGANISATION")
+	for _, project := range resp.Projects {
+		if *org != "" && project.OrganisationUUID != *org {
+			continue
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", project.Code, project.Name, project.UUID, project.OrganisationUUID)
+	}
+	table.Flush()
+}
+
+func listEndpoints(args []string) {
+	flags := flag.NewFlagSet("endpoints", flag.ExitOnError)
+	code := flags.String("project", "", "code of the project")
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	if *code == "" {
+		fmt.Fprintln(os.Stderr, "crudbox endpoints: -project is required")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	endpoints, err := projectEndpoints(client, *code)
+	if err != nil {
+		log.Fatal("Failed to list endpoints: ", err)
+	}
+
+	table := newTable()
+	fmt.Fprintln(table, "METHOD\tPATH\tSTATUS\tUUID")
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\n", endpoint.Method, endpoint.Path, endpoint.ResponseStatus, endpoint.UUID)
+	}
+	table.Flush()
+}
+
+// projectEndpoints returns the endpoints of the project with the given code.
+func projectEndpoints(client *apiClient, code string) ([]*contracts.Endpoint, error) {
+	project, err := client.project(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Endpoints []*contracts.Endpoint `json:"endpoints"`
+	}
+	if err := client.do(http.MethodGet, "/project/"+project.UUID+"/endpoints", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Endpoints, nil
+}
+
+func newTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
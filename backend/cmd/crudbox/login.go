@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+// login saves an API token for later commands. Logging in with a password
+// creates a dedicated token and ends the login session straight away, so the
+// CLI never holds on to the user's password or a refresh token.
+func login(args []string) {
+	flags := flag.NewFlagSet("login", flag.ExitOnError)
+	url := flags.String("url", "", "crudbox server URL, also read from CRUDBOX_URL (default "+defaultServerURL+")")
+	email := flags.String("email", "", "email to log in with, prompted for when missing")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from standard input")
+	token := flags.String("token", "", "save an existing API token instead of logging in with a password")
+	flags.Parse(args)
+
+	serverURL := strings.TrimSuffix(firstNonEmpty(*url, os.Getenv("CRUDBOX_URL"), defaultServerURL), "/")
+	input := bufio.NewReader(os.Stdin)
+
+	apiToken := *token
+	if apiToken == "" {
+		if *email == "" {
+			fmt.Fprint(os.Stderr, "Email: ")
+			line, err := readLine(input)
+			if err != nil {
+				log.Fatal("Failed to read email: ", err)
+			}
+			*email = line
+		}
+
+		password, err := readPassword(input, *passwordStdin)
+		if err != nil {
+			log.Fatal("Failed to read password: ", err)
+		}
+
+		apiToken, err = createCLIToken(serverURL, *email, password)
+		if err != nil {
+			log.Fatal("Login failed: ", err)
+		}
+	}
+
+	// Check the token before saving it so a typo does not break later commands
+	var resp struct {
+		User contracts.User `json:"user"`
+	}
+	if err := newAPIClient(serverURL, apiToken).do(http.MethodGet, "/user", nil, &resp); err != nil {
+		log.Fatal("Login failed: ", err)
+	}
+
+	if err := saveConfig(&cliConfig{URL: serverURL, Token: apiToken, CreatedToken: *token == ""}); err != nil {
+		log.Fatal("Failed to save the login: ", err)
+	}
+	fmt.Printf("Logged in to %s as %s\n", serverURL, resp.User.Email)
+}
+
+// createCLIToken logs in with a password, creates an API token for the CLI with
+// the resulting session and then logs the session out again.
+func createCLIToken(serverURL, email, password string) (string, error) {
+	var tokens contracts.AuthTokens
+	anonymous := newAPIClient(serverURL, "")
+	if err := anonymous.do(http.MethodPost, "/login", contracts.LoginRequest{Email: email, Password: password}, &tokens); err != nil {
+		return "", err
+	}
+
+	session := newAPIClient(serverURL, tokens.Token)
+	defer func() {
+		if err := session.do(http.MethodPost, "/logout", nil, nil); err != nil {
+			log.Printf("Failed to end the login session: %v", err)
+		}
+	}()
+
+	name := "crudbox CLI"
+	if hostname, err := os.Hostname(); err == nil {
+		name += " on " + hostname
+	}
+
+	var resp struct {
+		Token contracts.CreatedAPIToken `json:"token"`
+	}
+	req := contracts.CreateAPITokenRequest{Name: name, Scopes: []string{"read", "write"}}
+	if err := session.do(http.MethodPost, "/user/tokens", req, &resp); err != nil {
+		return "", err
+	}
+	return resp.Token.Token, nil
+}
+
+// readPassword prompts for the password without echoing it when standard input
+// is a terminal, and reads a line otherwise.
+func readPassword(input *bufio.Reader, fromStdin bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if fromStdin || !term.IsTerminal(fd) {
+		return readLine(input)
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+func readLine(input *bufio.Reader) (string, error) {
+	line, err := input.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func logout(args []string) {
+	flags := flag.NewFlagSet("logout", flag.ExitOnError)
+	flags.Parse(args)
+
+	config, err := loadConfig()
+	if err != nil {
+		log.Fatal("Failed to read the saved login: ", err)
+	}
+	if config.Token == "" {
+		fmt.Println("Not logged in")
+		return
+	}
+
+	// A token created by login is of no use once the login is gone. Failing to
+	// revoke it, say because the server is unreachable, still logs out locally.
+	revoked := false
+	if config.CreatedToken {
+		if err := newAPIClient(config.URL, config.Token).do(http.MethodDelete, "/user/tokens/self", nil, nil); err != nil {
+			log.Printf("Failed to revoke the API token: %v", err)
+		} else {
+			revoked = true
+		}
+	}
+
+	if err := removeConfig(); err != nil {
+		log.Fatal("Failed to remove the saved login: ", err)
+	}
+	if revoked {
+		fmt.Printf("Logged out of %s and revoked the API token.\n", config.URL)
+	} else {
+		fmt.Printf("Removed the saved login for %s. The API token stays valid until it is revoked.\n", config.URL)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+// showRequestLog prints the latest requests served by a project's mocks. With
+// -f it keeps polling for new requests until interrupted, like tail -f.
+func showRequestLog(args []string) {
+	flags := flag.NewFlagSet("logs", flag.ExitOnError)
+	code := flags.String("project", "", "code of the project")
+	lines := flags.Int("n", 20, "number of recent requests to show, at most 500")
+	follow := flags.Bool("f", false, "keep printing requests as they arrive")
+	interval := flags.Duration("interval", 2*time.Second, "how often to check for new requests with -f")
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	if *code == "" {
+		fmt.Fprintln(os.Stderr, "crudbox logs: -project is required")
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *lines < 1 || *lines > 500 || *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "crudbox logs: -n must be from 1 to 500 and -interval positive")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	project, err := client.project(*code)
+	if err != nil {
+		log.Fatal("Failed to find the project: ", err)
+	}
+
+	page, err := requestLog(client, project.UUID, url.Values{"limit": {strconv.Itoa(*lines)}})
+	if err != nil {
+		log.Fatal("Failed to read the request log: ", err)
+	}
+	printRequests(page.Entries)
+
+	cursor := page.Cursor
+	for *follow {
+		time.Sleep(*interval)
+
+		page, err := requestLog(client, project.UUID, url.Values{"after": {cursor}, "limit": {"500"}})
+		if err != nil {
+			// Keep following through a server restart or a network blip
+			log.Print("Failed to read the request log: ", err)
+			continue
+		}
+		printRequests(page.Entries)
+		cursor = page.Cursor
+	}
+}
+
+func requestLog(client *apiClient, projectUUID string, query url.Values) (*contracts.RequestLogPage, error) {
+	var page contracts.RequestLogPage
+	if err := client.do(http.MethodGet, "/project/"+projectUUID+"/requests?"+query.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// printRequests prints one line per request: time, status, method and path,
+// then where the request was served from and who sent it.
+func printRequests(entries []*contracts.RequestLogEntry) {
+	for _, entry := range entries {
+		target := entry.Path
+		if entry.Query != "" {
+			target += "?" + entry.Query
+		}
+
+		var details []string
+		if entry.Version != "" {
+			details = append(details, "version "+entry.Version)
+		}
+		if entry.Environment != "" {
+			details = append(details, "environment "+entry.Environment)
+		}
+		if !entry.Matched {
+			details = append(details, "no endpoint matched")
+		}
+		if entry.IPAddress != "" {
+			details = append(details, "from "+entry.IPAddress)
+		}
+
+		line := fmt.Sprintf("%s  %d  %s %s", entry.CreatedAt.Local().Format(time.DateTime), entry.ResponseStatus, entry.Method, target)
+		if len(details) > 0 {
+			line += "  (" + strings.Join(details, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,79 @@
+// Command crudbox runs crudbox tools from the command line.
+//
+// crudbox serve serves mocks declared in YAML or JSON files without a
+// database, auth or dashboard, which suits test stacks started with
+// docker-compose. Changes to the files are picked up while it runs.
+//
+// crudbox sync makes projects on a crudbox server match the same files, so
+// mocks can be kept in a repository and reviewed like code. crudbox drift
+// reports endpoints changed on the server since they were last synced.
+//
+// The remaining commands are a client for the REST API. crudbox login saves an
+// API token, and the other commands use it to list organisations, projects and
+// endpoints, create and edit endpoints from files, import OpenAPI documents and
+// export projects. crudbox logs -f follows the requests a project's mocks serve.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: crudbox <command> [flags]
+
+Commands:
+  serve      serve mocks declared in YAML or JSON files
+  sync       make projects on a crudbox server match declared mock files
+  drift      report endpoints changed on the server since the last sync
+
+  login      save an API token for the commands below
+  logout     remove the saved API token
+  orgs       list organisations
+  projects   list projects
+  endpoints  list the endpoints of a project
+  endpoint   create or edit endpoints from a YAML or JSON file
+  import     import endpoints from an OpenAPI document after a preview
+  export     export a project as JSON
+  logs       show requests served by a project's mocks, -f to follow them
+
+Run "crudbox <command> -help" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "serve":
+		serve(os.Args[2:])
+	case "sync":
+		syncProjects(os.Args[2:])
+	case "drift":
+		reportDrift(os.Args[2:])
+	case "login":
+		login(os.Args[2:])
+	case "logout":
+		logout(os.Args[2:])
+	case "orgs":
+		listOrganisations(os.Args[2:])
+	case "projects":
+		listProjects(os.Args[2:])
+	case "endpoints":
+		listEndpoints(os.Args[2:])
+	case "endpoint":
+		manageEndpoints(os.Args[2:])
+	case "import":
+		importOpenAPI(os.Args[2:])
+	case "export":
+		exportProject(os.Args[2:])
+	case "logs":
+		showRequestLog(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "crudbox: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/mockfile"
+)
+
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := flags.String("config", "", "mock file, or directory of .yaml, .yml and .json mock files")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	mockBaseDomain := flags.String("mock-base-domain", os.Getenv("MOCK_BASE_DOMAIN"), "serve projects on subdomains of this domain, as with MOCK_BASE_DOMAIN")
+	reloadInterval := flags.Duration("reload-interval", time.Second, "how often to check the mock files for changes; 0 disables reloading")
+	flags.Parse(args)
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "crudbox serve: -config is required")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	server, err := mockfile.NewServer(*configPath, *mockBaseDomain)
+	if err != nil {
+		log.Fatal("Failed to load mocks: ", err)
+	}
+
+	if *reloadInterval > 0 {
+		go server.Watch(*reloadInterval)
+	}
+
+	log.Printf("Serving mocks from %s on %s", *configPath, *addr)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		log.Fatal("Failed to start server: ", err)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/mockfile"
+)
+
+// exitDrift is the exit status of crudbox drift when it finds drift, so that
+// scheduled jobs can tell drift apart from failures.
+const exitDrift = 3
+
+func syncProjects(args []string) {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	configPath := flags.String("config", "", "mock file, or directory of .yaml, .yml and .json mock files")
+	only := flags.String("project", "", "only sync the project with this code")
+	dryRun := flags.Bool("dry-run", false, "show the plan without applying it")
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "crudbox sync: -config is required")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	declared, remote := loadDeclaredProjects(client, *configPath, *only)
+
+	for _, project := range declared {
+		endpoints, err := project.BundleEndpoints()
+		if err != nil {
+			log.Fatalf("Project %s: %v", project.Code, err)
+		}
+
+		var resp struct {
+			Sync contracts.ProjectSyncPlan `json:"sync"`
+		}
+		req := contracts.SyncProjectRequest{Endpoints: endpoints, DryRun: *dryRun}
+		if err := client.do(http.MethodPost, "/project/"+remote[project.Code].UUID+"/sync", req, &resp); err != nil {
+			log.Fatalf("Project %s: %v", project.Code, err)
+		}
+		printPlan(project.Code, &resp.Sync)
+	}
+}
+
+func reportDrift(args []string) {
+	flags := flag.NewFlagSet("drift", flag.ExitOnError)
+	configPath := flags.String("config", "", "mock file, or directory of .yaml, .yml and .json mock files")
+	only := flags.String("project", "", "only check the project with this code")
+	newClient := apiFlags(flags)
+	flags.Parse(args)
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "crudbox drift: -config is required")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	declared, remote := loadDeclaredProjects(client, *configPath, *only)
+
+	drifted := false
+	for _, project := range declared {
+		var resp struct {
+			Sync contracts.ProjectSyncStatus `json:"sync"`
+		}
+		if err := client.do(http.MethodGet, "/project/"+remote[project.Code].UUID+"/sync", nil, &resp); err != nil {
+			log.Fatalf("Project %s: %v", project.Code, err)
+		}
+
+		status := resp.Sync
+		switch {
+		case status.LastSyncedAt == nil:
+			fmt.Printf("%s: never synced\n", project.Code)
+		case len(status.Drift) == 0:
+			fmt.Printf("%s: no drift since %s\n", project.Code, status.LastSyncedAt.Local().Format("2006-01-02 15:04:05"))
+		default:
+			drifted = true
+			fmt.Printf("%s: %d endpoints changed since %s\n", project.Code, len(status.Drift), status.LastSyncedAt.Local().Format("2006-01-02 15:04:05"))
+			for _, entry := range status.Drift {
+				fmt.Printf("  %s %s %s\n", actionSymbol(entry.Action), entry.Method, entry.Path)
+				if entry.Action == "update" {
+					printChange(entry.Synced, entry.Live, entry.BodyDiff, entry.HeadersDiff)
+				}
+			}
+		}
+	}
+
+	if drifted {
+		os.Exit(exitDrift)
+	}
+}
+
+// loadDeclaredProjects reads the mock files and finds the server's project for
+// each declared code. Projects have to exist on the server before they are
+// synced.
+func loadDeclaredProjects(client *apiClient, configPath, only string) ([]mockfile.Project, map[string]*contracts.Project) {
+	projects, err := mockfile.Load(configPath)
+	if err != nil {
+		log.Fatal("Failed to load mock files: ", err)
+	}
+
+	remote, err := client.projectsByCode()
+	if err != nil {
+		log.Fatal("Failed to list projects: ", err)
+	}
+
+	var declared []mockfile.Project
+	for _, project := range projects {
+		if only != "" && project.Code != only {
+			continue
+		}
+		if remote[project.Code] == nil {
+			log.Fatalf("Project %s does not exist on the server or is not accessible with this token", project.Code)
+		}
+		declared = append(declared, project)
+	}
+	if only != "" && len(declared) == 0 {
+		log.Fatalf("Project %s is not declared in %s", only, configPath)
+	}
+
+	return declared, remote
+}
+
+func printPlan(code string, plan *contracts.ProjectSyncPlan) {
+	format := "%s: %d created, %d updated, %d deleted, %d unchanged\n"
+	if plan.DryRun {
+		format = "%s: %d to create, %d to update, %d to delete, %d unchanged (dry run)\n"
+	}
+	fmt.Printf(format, code, plan.Created, plan.Updated, plan.Deleted, plan.Unchanged)
+
+	for _, entry := range plan.Entries {
+		note := ""
+		if entry.Drifted {
+			note = "  [changed outside sync]"
+		}
+		fmt.Printf("  %s %s %s%s\n", actionSymbol(entry.Action), entry.Method, entry.Path, note)
+		if entry.Action == "update" {
+			printChange(entry.Live, entry.Declared, entry.BodyDiff, entry.HeadersDiff)
+		}
+	}
+
+	if plan.Drifted > 0 {
+		verb := "discards"
+		if plan.DryRun {
+			verb = "would discard"
+		}
+		fmt.Printf("  This sync %s changes made outside sync to %d endpoints.\n", verb, plan.Drifted)
+	}
+}
+
+func actionSymbol(action string) string {
+	switch action {
+	case "create":
+		return "+"
+	case "delete":
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// printChange prints how an endpoint's response status changed and the changed
+// lines of its body and headers.
+func printChange(before, after *contracts.ProjectBundleEndpoint, diffs ...[]contracts.DiffLine) {
+	if before.ResponseStatus != after.ResponseStatus {
+		fmt.Printf("      status %d -> %d\n", before.ResponseStatus, after.ResponseStatus)
+	}
+	for _, diff := range diffs {
+		for _, line := range diff {
+			switch line.Op {
+			case "insert":
+				fmt.Printf("      + %s\n", line.Text)
+			case "delete":
+				fmt.Printf("      - %s\n", line.Text)
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package contracts
+
+import (
+	"time"
+)
+
+// APITokenPrefix marks personal access tokens so they can be told apart from
+// JWTs in the Authorization header.
+const APITokenPrefix = "cbx_"
+
+type APIToken struct {
+	UUID        string     `json:"uuid"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+// CreatedAPIToken is returned once when a token is created; the token itself is
+// not stored and cannot be retrieved again.
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+type CreateAPITokenRequest struct {
+	Name      string     `json:"name" binding:"required,max=255"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=read write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,44 @@
+package contracts
+
+import (
+	"time"
+)
+
+// AuditChange holds the value of a field before and after a change. Before is
+// null for created entities and After is null for deleted ones.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type AuditEntry struct {
+	UUID        string                 `json:"uuid"`
+	Action      string                 `json:"action"`
+	EntityType  string                 `json:"entity_type"`
+	EntityUUID  string                 `json:"entity_uuid"`
+	ProjectUUID string                 `json:"project_uuid,omitempty"`
+	ActorUUID   string                 `json:"actor_uuid"`
+	ActorEmail  string                 `json:"actor_email"`
+	Changes     map[string]AuditChange `json:"changes"`
+	IPAddress   string                 `json:"ip_address,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// AuditLogQuery filters an organisation's audit log. Cursor is the next_cursor
+// of the previous page.
+type AuditLogQuery struct {
+	EntityType  string     `form:"entity_type" binding:"omitempty,oneof=organisation project endpoint version environment member"`
+	EntityUUID  string     `form:"entity_uuid" binding:"omitempty,uuid"`
+	ProjectUUID string     `form:"project_uuid" binding:"omitempty,uuid"`
+	ActorUUID   string     `form:"actor_uuid" binding:"omitempty,uuid"`
+	Action      string     `form:"action" binding:"omitempty,oneof=create update delete restore"`
+	Since       *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until       *time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor      string     `form:"cursor"`
+}
+
+type AuditLogPage struct {
+	Entries    []*AuditEntry `json:"entries"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -17,6 +17,8 @@ type Endpoint struct {
 	UpdatedAt       *time.Time `json:"updated_at"`
 	CreatedBy       string     `json:"created_by,omitempty"`
 	UpdatedBy       string     `json:"updated_by,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy       string     `json:"deleted_by,omitempty"`
 }
 
 type CreateEndpointRequest struct {
@@ -34,3 +36,39 @@ type UpdateEndpointRequest struct {
 	ResponseStatus  *int    `json:"response_status"`
 	ResponseHeaders *string `json:"response_headers"`
 }
+
+// EndpointRevision is a saved state of an endpoint. RestoredFrom names the
+// revision that was restored to create it.
+type EndpointRevision struct {
+	UUID            string     `json:"uuid"`
+	Revision        int        `json:"revision"`
+	Method          string     `json:"method"`
+	Path            string     `json:"path"`
+	ResponseBody    string     `json:"response_body"`
+	ResponseStatus  int        `json:"response_status"`
+	ResponseHeaders string     `json:"response_headers"`
+	RestoredFrom    *int       `json:"restored_from,omitempty"`
+	AuthorUUID      string     `json:"author_uuid,omitempty"`
+	AuthorEmail     string     `json:"author_email,omitempty"`
+	CreatedAt       *time.Time `json:"created_at"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// EndpointRevisionDiff lists the fields that differ between two revisions. The
+// response body and headers are additionally diffed line by line.
+type EndpointRevisionDiff struct {
+	From                int                    `json:"from"`
+	To                  int                    `json:"to"`
+	Changes             map[string]AuditChange `json:"changes"`
+	ResponseBodyDiff    []DiffLine             `json:"response_body_diff,omitempty"`
+	ResponseHeadersDiff []DiffLine             `json:"response_headers_diff,omitempty"`
+}
+
+type EndpointRevisionDiffQuery struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
@@ -9,6 +9,7 @@ type Organisation struct {
 	UUID      string     `json:"uuid"`
 	Name      string     `json:"name"`
 	UserUUID  string     `json:"user_uuid"`
+	Role      string     `json:"role,omitempty"`
 	CreatedAt *time.Time `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
 }
@@ -16,3 +17,49 @@ type Organisation struct {
 type CreateOrganisationRequest struct {
 	Name string `json:"name" binding:"required"`
 }
+
+type UpdateOrganisationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type TransferOwnershipRequest struct {
+	UserUUID string `json:"user_uuid" binding:"required,uuid"`
+}
+
+type Member struct {
+	UserUUID string     `json:"user_uuid"`
+	Email    string     `json:"email"`
+	Role     string     `json:"role"`
+	IsOwner  bool       `json:"is_owner"`
+	JoinedAt *time.Time `json:"joined_at"`
+}
+
+type Invitation struct {
+	UUID             string     `json:"uuid"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role"`
+	OrganisationUUID string     `json:"organisation_uuid"`
+	InvitedByUUID    string     `json:"invited_by_uuid"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	CreatedAt        *time.Time `json:"created_at"`
+}
+
+// CreatedInvitation is returned once when an invitation is created; the token is
+// not stored and cannot be retrieved again.
+type CreatedInvitation struct {
+	Invitation
+	Token string `json:"token"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"omitempty,oneof=admin editor viewer"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin editor viewer"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -9,13 +9,222 @@ type Project struct {
 	UUID             string     `json:"uuid"`
 	Name             string     `json:"name"`
 	Code             string     `json:"code"`
+	Hostname         string     `json:"hostname,omitempty"`
 	UserUUID         string     `json:"user_uuid"`
 	OrganisationUUID string     `json:"organisation_uuid"`
 	CreatedAt        *time.Time `json:"created_at"`
 	UpdatedAt        *time.Time `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy        string     `json:"deleted_by,omitempty"`
+}
+
+// OrganisationTrash lists the deleted projects of an organisation and the
+// deleted endpoints of its remaining projects.
+type OrganisationTrash struct {
+	Projects  []*Project  `json:"projects"`
+	Endpoints []*Endpoint `json:"endpoints"`
 }
 
 type CreateProjectRequest struct {
 	Name             string `json:"name" binding:"required"`
+	Code             string `json:"code"`
 	OrganisationUUID string `json:"organisation_uuid" binding:"required,uuid"`
 }
+
+type UpdateProjectRequest struct {
+	Name             *string `json:"name" binding:"omitempty,min=1"`
+	OrganisationUUID *string `json:"organisation_uuid" binding:"omitempty,uuid"`
+	Code             *string `json:"code"`
+	Hostname         *string `json:"hostname"`
+	RegenerateCode   bool    `json:"regenerate_code"`
+}
+
+type CloneProjectRequest struct {
+	Name             string `json:"name"`
+	OrganisationUUID string `json:"organisation_uuid" binding:"omitempty,uuid"`
+}
+
+// ProjectBundleVersion is the current version of the project export format.
+// Bump it whenever ProjectBundle changes in a way older importers cannot read.
+const ProjectBundleVersion = 1
+
+type ProjectBundle struct {
+	Version    int                     `json:"version" binding:"required"`
+	ExportedAt *time.Time              `json:"exported_at"`
+	Project    ProjectBundleProject    `json:"project" binding:"required"`
+	Endpoints  []ProjectBundleEndpoint `json:"endpoints" binding:"dive"`
+}
+
+type ProjectBundleProject struct {
+	Name string `json:"name" binding:"required"`
+	Code string `json:"code"`
+}
+
+type ProjectBundleEndpoint struct {
+	Method          string `json:"method" binding:"required"`
+	Path            string `json:"path" binding:"required"`
+	ResponseBody    string `json:"response_body"`
+	ResponseStatus  int    `json:"response_status"`
+	ResponseHeaders string `json:"response_headers"`
+}
+
+// ProjectVersion is a named, frozen copy of a project's endpoints. Endpoints are
+// only included when a single version is requested.
+type ProjectVersion struct {
+	UUID          string                  `json:"uuid"`
+	Name          string                  `json:"name"`
+	Description   string                  `json:"description,omitempty"`
+	EndpointCount int                     `json:"endpoint_count"`
+	CreatedAt     *time.Time              `json:"created_at"`
+	CreatedBy     string                  `json:"created_by,omitempty"`
+	Endpoints     []ProjectBundleEndpoint `json:"endpoints,omitempty"`
+}
+
+type CreateProjectVersionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// ProjectVersionRestoreResult counts the changes made to the head endpoints
+// when a version was restored.
+type ProjectVersionRestoreResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+}
+
+// ProjectEnvironment holds variables that are substituted for ${NAME} in mock
+// response bodies and headers.
+type ProjectEnvironment struct {
+	UUID      string            `json:"uuid"`
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+	IsDefault bool              `json:"is_default"`
+	CreatedAt *time.Time        `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at"`
+}
+
+type CreateProjectEnvironmentRequest struct {
+	Name      string            `json:"name" binding:"required"`
+	Variables map[string]string `json:"variables"`
+	IsDefault bool              `json:"is_default"`
+}
+
+// UpdateProjectEnvironmentRequest changes the fields that are set. Variables
+// replace the existing ones as a whole.
+type UpdateProjectEnvironmentRequest struct {
+	Name      *string           `json:"name"`
+	Variables map[string]string `json:"variables"`
+	IsDefault *bool             `json:"is_default"`
+}
+
+type CreateProjectBranchRequest struct {
+	Name string `json:"name"`
+}
+
+// ProjectBranch is a project created from a parent project to be merged back
+// into it.
+type ProjectBranch struct {
+	Project           *Project   `json:"project"`
+	ParentProjectUUID string     `json:"parent_project_uuid"`
+	CreatedAt         *time.Time `json:"created_at"`
+	CreatedBy         string     `json:"created_by,omitempty"`
+	MergedAt          *time.Time `json:"merged_at"`
+	MergedBy          string     `json:"merged_by,omitempty"`
+}
+
+// ProjectMergeEntry compares one method and path between the branch point, the
+// parent and the branch. Base, Parent and Branch are nil where the endpoint does
+// not exist; the diffs show how each side changed the branch point.
+type ProjectMergeEntry struct {
+	Method            string                 `json:"method"`
+	Path              string                 `json:"path"`
+	Status            string                 `json:"status"`
+	Base              *ProjectBundleEndpoint `json:"base"`
+	Parent            *ProjectBundleEndpoint `json:"parent"`
+	Branch            *ProjectBundleEndpoint `json:"branch"`
+	ParentBodyDiff    []DiffLine             `json:"parent_body_diff,omitempty"`
+	BranchBodyDiff    []DiffLine             `json:"branch_body_diff,omitempty"`
+	ParentHeadersDiff []DiffLine             `json:"parent_headers_diff,omitempty"`
+	BranchHeadersDiff []DiffLine             `json:"branch_headers_diff,omitempty"`
+}
+
+// ProjectMergePreview lists the endpoints that changed since the branch point.
+type ProjectMergePreview struct {
+	BranchProjectUUID string              `json:"branch_project_uuid"`
+	ParentProjectUUID string              `json:"parent_project_uuid"`
+	Entries           []ProjectMergeEntry `json:"entries"`
+	Conflicts         int                 `json:"conflicts"`
+}
+
+// MergeProjectBranchRequest resolves conflicts by "METHOD /path" in favour of
+// the "branch" or the "parent".
+type MergeProjectBranchRequest struct {
+	Resolutions map[string]string `json:"resolutions" binding:"omitempty,dive,oneof=branch parent"`
+}
+
+// ProjectMergeResult counts the changes made to the parent's endpoints.
+type ProjectMergeResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+}
+
+// SyncProjectRequest declares the endpoints a project should have. Endpoints
+// that are not declared are deleted. With DryRun the plan is returned without
+// being applied.
+type SyncProjectRequest struct {
+	Endpoints []ProjectBundleEndpoint `json:"endpoints" binding:"dive"`
+	DryRun    bool                    `json:"dry_run"`
+}
+
+// ProjectSyncEntry is the change a sync makes at one method and path. Live and
+// Declared are nil where the endpoint does not exist; the diffs show how the
+// live endpoint changes. Drifted is set when the live endpoint was changed
+// outside sync since the latest sync, a change the sync discards.
+type ProjectSyncEntry struct {
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	Action      string                 `json:"action"`
+	Drifted     bool                   `json:"drifted"`
+	Live        *ProjectBundleEndpoint `json:"live"`
+	Declared    *ProjectBundleEndpoint `json:"declared"`
+	BodyDiff    []DiffLine             `json:"body_diff,omitempty"`
+	HeadersDiff []DiffLine             `json:"headers_diff,omitempty"`
+}
+
+// ProjectSyncPlan lists the changes that make a project's endpoints match the
+// declared ones. LastSyncedAt is the sync before this one, if any.
+type ProjectSyncPlan struct {
+	ProjectUUID  string             `json:"project_uuid"`
+	DryRun       bool               `json:"dry_run"`
+	LastSyncedAt *time.Time         `json:"last_synced_at"`
+	Entries      []ProjectSyncEntry `json:"entries"`
+	Created      int                `json:"created"`
+	Updated      int                `json:"updated"`
+	Deleted      int                `json:"deleted"`
+	Unchanged    int                `json:"unchanged"`
+	Drifted      int                `json:"drifted"`
+}
+
+// ProjectDriftEntry is an endpoint created, updated or deleted outside sync
+// since the latest sync. Synced and Live are nil where the endpoint does not
+// exist; the diffs show how the synced endpoint was changed.
+type ProjectDriftEntry struct {
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	Action      string                 `json:"action"`
+	Synced      *ProjectBundleEndpoint `json:"synced"`
+	Live        *ProjectBundleEndpoint `json:"live"`
+	BodyDiff    []DiffLine             `json:"body_diff,omitempty"`
+	HeadersDiff []DiffLine             `json:"headers_diff,omitempty"`
+}
+
+// ProjectSyncStatus reports the latest sync of a project and the drift since.
+// A project that was never synced has no LastSyncedAt and no drift.
+type ProjectSyncStatus struct {
+	ProjectUUID  string              `json:"project_uuid"`
+	LastSyncedAt *time.Time          `json:"last_synced_at"`
+	LastSyncedBy string              `json:"last_synced_by,omitempty"`
+	Drift        []ProjectDriftEntry `json:"drift"`
+}
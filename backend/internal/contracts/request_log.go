@@ -0,0 +1,49 @@
+package contracts
+
+import (
+	"time"
+)
+
+// MockRequest is a request served by a project's mocks, as recorded in its
+// request log. Matched is set when an endpoint answered; EndpointUUID is empty
+// for endpoints served from a version.
+type MockRequest struct {
+	Method         string
+	Path           string
+	Query          string
+	Version        string
+	Environment    string
+	Matched        bool
+	EndpointUUID   string
+	ResponseStatus int
+}
+
+type RequestLogEntry struct {
+	ID             int       `json:"id"`
+	Method         string    `json:"method"`
+	Path           string    `json:"path"`
+	Query          string    `json:"query,omitempty"`
+	Version        string    `json:"version,omitempty"`
+	Environment    string    `json:"environment,omitempty"`
+	Matched        bool      `json:"matched"`
+	EndpointUUID   string    `json:"endpoint_uuid,omitempty"`
+	ResponseStatus int       `json:"response_status"`
+	IPAddress      string    `json:"ip_address,omitempty"`
+	UserAgent      string    `json:"user_agent,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RequestLogQuery selects part of a project's request log. Without After the
+// newest requests are returned; After is the cursor of a previous page and
+// returns the requests recorded since, which is how the log is followed.
+type RequestLogQuery struct {
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=500"`
+	After string `form:"after"`
+}
+
+// RequestLogPage lists requests oldest first. Cursor is passed as After to get
+// the requests that follow, and is set even when there are none yet.
+type RequestLogPage struct {
+	Entries []*RequestLogEntry `json:"entries"`
+	Cursor  string             `json:"cursor"`
+}
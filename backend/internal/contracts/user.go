@@ -8,6 +8,7 @@ type User struct {
 	ID            int             `json:"-"`
 	UUID          string          `json:"uuid"`
 	Email         string          `json:"email"`
+	EmailVerified bool            `json:"email_verified"`
 	Organisations []*Organisation `json:"organisations"`
 	CreatedAt     *time.Time      `json:"created_at"`
 	UpdatedAt     *time.Time      `json:"updated_at"`
@@ -22,3 +23,46 @@ type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 }
+
+// ClientInfo describes the client a login session was created from.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// AuthTokens is returned on login and refresh. Token is a short-lived access
+// token; RefreshToken is exchanged for a new pair via POST /token/refresh and
+// is only valid once.
+type AuthTokens struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// OIDCLogin starts a single sign-on login. Flow carries the state, nonce and
+// PKCE verifier and must be handed back, unmodified, on the callback.
+type OIDCLogin struct {
+	AuthorizationURL string
+	Flow             string
+}
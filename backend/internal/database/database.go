@@ -3,20 +3,36 @@ package database
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
+// Drivers supported by NewConnection. They double as the sqlx driver names.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// DriverMemory keeps all data in process memory. It has no connection; callers
+// use the repositories of package repository/memory instead.
+const DriverMemory = "memory"
+
 type Config struct {
+	// Driver selects the database: "postgres", "sqlite" or "memory".
+	Driver   string
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
 	SSLMode  string
+	// Path is the database file of the sqlite driver.
+	Path string
 }
 
 func NewConfig() *Config {
@@ -26,6 +42,8 @@ func NewConfig() *Config {
 	}
 
 	return &Config{
+		Driver:   getEnv("DB_DRIVER", DriverPostgres),
+		Path:     getEnv("DB_PATH", "crudbox.db"),
 		Host:     getEnv("DB_HOST", "localhost"),
 		Port:     getEnv("DB_PORT", "5432"),
 		User:     getEnv("DB_USER", "postgres"),
@@ -43,6 +61,17 @@ func getEnv(key, defaultValue string) string {
 }
 
 func NewConnection(config *Config) (*sqlx.DB, error) {
+	switch config.Driver {
+	case DriverPostgres, "":
+		return newPostgresConnection(config)
+	case DriverSQLite:
+		return newSQLiteConnection(config)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", config.Driver)
+	}
+}
+
+func newPostgresConnection(config *Config) (*sqlx.DB, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
 
@@ -58,3 +87,31 @@ func NewConnection(config *Config) (*sqlx.DB, error) {
 	log.Println("Connected to PostgreSQL")
 	return db, nil
 }
+
+// newSQLiteConnection opens the database file at config.Path, creating it if
+// needed. Transactions take the write lock when they begin and waiting writers
+// retry for a few seconds, so concurrent requests queue up instead of failing.
+func newSQLiteConnection(config *Config) (*sqlx.DB, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("sqlite database driver requires a path")
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+
+	db, err := sqlx.Open(DriverSQLite, "file:"+config.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	log.Printf("Opened SQLite database %s", config.Path)
+	return db, nil
+}
@@ -8,10 +8,17 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-//go:embed migrations/*.sql
+// Migrations live in a directory per driver. Every migration added from now on
+// needs a file of the same name in each of them.
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
 var migrationFiles embed.FS
 
+// RunMigrations applies the migrations of the driver db was opened with that
+// have not run yet.
 func RunMigrations(db *sqlx.DB) error {
+	dir := "migrations/" + db.DriverName()
+
 	if _, err := db.Exec(`
         CREATE TABLE IF NOT EXISTS schema_migrations (
             filename TEXT PRIMARY KEY,
@@ -27,7 +34,7 @@ func RunMigrations(db *sqlx.DB) error {
 		return fmt.Errorf("failed to ensure schema_migrations table: %w", err)
 	}
 
-	entries, err := migrationFiles.ReadDir("migrations")
+	entries, err := migrationFiles.ReadDir(dir)
 	if err != nil {
 		return fmt.Errorf("failed to read migrations: %w", err)
 	}
@@ -50,7 +57,7 @@ func RunMigrations(db *sqlx.DB) error {
 			continue
 		}
 
-		contents, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		contents, err := migrationFiles.ReadFile(dir + "/" + entry.Name())
 		if err != nil {
 			return fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
 		}
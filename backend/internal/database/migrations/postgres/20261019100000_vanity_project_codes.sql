@@ -0,0 +1,18 @@
+-- Allow human readable project codes (slugs) instead of the 5 character random ones
+ALTER TABLE projects ALTER COLUMN code TYPE VARCHAR(63);
+
+-- Old codes that should keep redirecting to a project after its slug changes
+CREATE TABLE project_code_redirects (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    code VARCHAR(63) UNIQUE NOT NULL,
+    project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    updated_by VARCHAR DEFAULT NULL,
+    deleted_at TIMESTAMPTZ DEFAULT NULL,
+    deleted_by VARCHAR DEFAULT NULL
+);
+
+CREATE INDEX idx_project_code_redirects_project_id ON project_code_redirects(project_id);
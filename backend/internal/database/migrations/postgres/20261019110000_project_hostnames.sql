@@ -0,0 +1,2 @@
+-- Optional custom hostname a project's mocks are served on, e.g. payments.mocks.local
+ALTER TABLE projects ADD COLUMN hostname VARCHAR(255) UNIQUE DEFAULT NULL;
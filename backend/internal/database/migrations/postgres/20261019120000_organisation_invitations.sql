@@ -0,0 +1,19 @@
+CREATE TABLE organisation_invitations (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    organisation_id INT NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by INT NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ DEFAULT NULL,
+    accepted_by INT DEFAULT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    updated_by VARCHAR DEFAULT NULL,
+    deleted_at TIMESTAMPTZ DEFAULT NULL,
+    deleted_by VARCHAR DEFAULT NULL
+);
+
+CREATE INDEX idx_organisation_invitations_organisation_id ON organisation_invitations(organisation_id);
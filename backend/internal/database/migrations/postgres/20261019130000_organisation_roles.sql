@@ -0,0 +1,9 @@
+-- Roles of members within an organisation: owner, admin, editor or viewer
+ALTER TABLE user_organisation_mapping ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'editor';
+
+UPDATE user_organisation_mapping m
+SET role = 'owner'
+FROM organisations o
+WHERE o.id = m.organisation_id AND o.user_id = m.user_id;
+
+ALTER TABLE organisation_invitations ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'editor';
@@ -0,0 +1,21 @@
+-- Long-lived personal access tokens for automation. Only a SHA-256 hash of the
+-- token is stored; token_prefix keeps the first characters for display.
+CREATE TABLE api_tokens (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ DEFAULT NULL,
+    last_used_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    updated_by VARCHAR DEFAULT NULL,
+    deleted_at TIMESTAMPTZ DEFAULT NULL,
+    deleted_by VARCHAR DEFAULT NULL
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
@@ -0,0 +1,22 @@
+-- Login sessions backing refresh tokens. Access tokens carry the session UUID so
+-- revoking a session (deleted_at) invalidates its access tokens as well.
+CREATE TABLE user_sessions (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash VARCHAR(64) UNIQUE NOT NULL,
+    previous_refresh_token_hash VARCHAR(64) DEFAULT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ DEFAULT NULL,
+    ip_address VARCHAR(64) DEFAULT NULL,
+    user_agent TEXT DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    updated_by VARCHAR DEFAULT NULL,
+    deleted_at TIMESTAMPTZ DEFAULT NULL,
+    deleted_by VARCHAR DEFAULT NULL
+);
+
+CREATE INDEX idx_user_sessions_user_id ON user_sessions(user_id);
+CREATE INDEX idx_user_sessions_previous_refresh_token_hash ON user_sessions(previous_refresh_token_hash);
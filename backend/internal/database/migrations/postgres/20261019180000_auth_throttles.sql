@@ -0,0 +1,10 @@
+-- Failure counters for login and signup throttling, shared by every instance.
+-- Keys look like "login:account:<email>" or "login:ip:<address>".
+CREATE TABLE auth_throttles (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE INDEX idx_auth_throttles_last_failure_at ON auth_throttles(last_failure_at);
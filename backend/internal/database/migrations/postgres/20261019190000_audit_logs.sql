@@ -0,0 +1,21 @@
+-- Append-only record of changes to organisations, projects and endpoints.
+-- Entries are never updated or deleted and outlive the entities they describe,
+-- so organisation_id deliberately carries no foreign key.
+CREATE TABLE audit_logs (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    organisation_id INT NOT NULL,
+    project_uuid UUID DEFAULT NULL,
+    actor_uuid VARCHAR NOT NULL,
+    actor_email VARCHAR(255) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_uuid UUID NOT NULL,
+    changes JSONB NOT NULL,
+    ip_address VARCHAR(64) DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_audit_logs_organisation_id ON audit_logs(organisation_id, id);
+CREATE INDEX idx_audit_logs_entity_uuid ON audit_logs(entity_uuid);
+CREATE INDEX idx_audit_logs_project_uuid ON audit_logs(project_uuid);
@@ -0,0 +1,24 @@
+-- Every saved state of an endpoint, numbered from 1 per endpoint. The newest
+-- revision always matches the endpoint row; restoring an old revision appends
+-- a copy of it instead of rewriting history.
+CREATE TABLE endpoint_revisions (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    endpoint_id INT NOT NULL REFERENCES endpoints(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    response_body TEXT NOT NULL,
+    response_status INTEGER NOT NULL,
+    response_headers TEXT NOT NULL,
+    restored_from INT DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    UNIQUE(endpoint_id, revision)
+);
+
+-- Existing endpoints start their history with their current state
+INSERT INTO endpoint_revisions (endpoint_id, revision, method, path, response_body, response_status, response_headers, created_at, created_by)
+SELECT id, 1, method, path, COALESCE(response_body, ''), response_status, COALESCE(response_headers, ''), COALESCE(updated_at, created_at), COALESCE(updated_by, created_by)
+FROM endpoints
+WHERE deleted_at IS NULL;
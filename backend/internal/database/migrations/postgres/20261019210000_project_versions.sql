@@ -0,0 +1,28 @@
+-- Named, frozen copies of a project's endpoints, served at /{code}@{name}/...
+CREATE TABLE project_versions (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    description TEXT DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    updated_by VARCHAR DEFAULT NULL,
+    deleted_at TIMESTAMPTZ DEFAULT NULL,
+    deleted_by VARCHAR DEFAULT NULL
+);
+
+-- Names can be reused once a version is deleted
+CREATE UNIQUE INDEX idx_project_versions_project_id_name ON project_versions(project_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE project_version_endpoints (
+    id SERIAL PRIMARY KEY,
+    version_id INT NOT NULL REFERENCES project_versions(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    response_body TEXT NOT NULL,
+    response_status INTEGER NOT NULL,
+    response_headers TEXT NOT NULL,
+    UNIQUE(version_id, method, path)
+);
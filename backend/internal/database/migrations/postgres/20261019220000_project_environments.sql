@@ -0,0 +1,21 @@
+-- Sets of variables substituted into mock responses, e.g. ${BASE_URL}
+CREATE TABLE project_environments (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    -- JSON object of variable names to values
+    variables TEXT NOT NULL DEFAULT '{}',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    updated_by VARCHAR DEFAULT NULL,
+    deleted_at TIMESTAMPTZ DEFAULT NULL,
+    deleted_by VARCHAR DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX idx_project_environments_project_id_name ON project_environments(project_id, name) WHERE deleted_at IS NULL;
+
+-- At most one default environment per project
+CREATE UNIQUE INDEX idx_project_environments_default ON project_environments(project_id) WHERE is_default AND deleted_at IS NULL;
@@ -0,0 +1,27 @@
+-- A branch is a project of its own, created as a copy of its parent and merged
+-- back into it later
+CREATE TABLE project_branches (
+    id SERIAL PRIMARY KEY,
+    uuid UUID DEFAULT gen_random_uuid() UNIQUE NOT NULL,
+    project_id INT NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    parent_project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NULL,
+    created_by VARCHAR DEFAULT NULL,
+    merged_at TIMESTAMPTZ DEFAULT NULL,
+    merged_by VARCHAR DEFAULT NULL
+);
+
+CREATE INDEX idx_project_branches_parent_project_id ON project_branches(parent_project_id);
+
+-- The parent's endpoints as of the branch point, i.e. the branch creation or
+-- its latest merge. Merges compare both sides against them.
+CREATE TABLE project_branch_base_endpoints (
+    id SERIAL PRIMARY KEY,
+    branch_id INT NOT NULL REFERENCES project_branches(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    response_body TEXT NOT NULL,
+    response_status INTEGER NOT NULL,
+    response_headers TEXT NOT NULL,
+    UNIQUE(branch_id, method, path)
+);
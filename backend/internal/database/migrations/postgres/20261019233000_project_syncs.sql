@@ -0,0 +1,20 @@
+-- The latest sync of a project's endpoints from declared definitions
+CREATE TABLE project_syncs (
+    id SERIAL PRIMARY KEY,
+    project_id INT NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    synced_at TIMESTAMPTZ NOT NULL,
+    synced_by VARCHAR DEFAULT NULL
+);
+
+-- The endpoints as the latest sync left them. Live endpoints that differ from
+-- them were changed outside sync.
+CREATE TABLE project_sync_endpoints (
+    id SERIAL PRIMARY KEY,
+    sync_id INT NOT NULL REFERENCES project_syncs(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    response_body TEXT NOT NULL,
+    response_status INTEGER NOT NULL,
+    response_headers TEXT NOT NULL,
+    UNIQUE(sync_id, method, path)
+);
@@ -0,0 +1,20 @@
+-- Requests served by a project's mocks, newest last. Only the latest requests
+-- of each project are kept. Values come from clients, so text columns are
+-- unbounded and the service cuts them to length.
+CREATE TABLE request_logs (
+    id SERIAL PRIMARY KEY,
+    project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    query TEXT NOT NULL,
+    version TEXT DEFAULT NULL,
+    environment TEXT DEFAULT NULL,
+    matched BOOLEAN NOT NULL,
+    endpoint_uuid UUID DEFAULT NULL,
+    response_status INTEGER NOT NULL,
+    ip_address VARCHAR(64) DEFAULT NULL,
+    user_agent TEXT DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_request_logs_project_id ON request_logs(project_id, id);
@@ -0,0 +1,17 @@
+CREATE TABLE project_syncs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INT NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    synced_at DATETIME NOT NULL,
+    synced_by VARCHAR DEFAULT NULL
+);
+
+CREATE TABLE project_sync_endpoints (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sync_id INT NOT NULL REFERENCES project_syncs(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    response_body TEXT NOT NULL,
+    response_status INTEGER NOT NULL,
+    response_headers TEXT NOT NULL,
+    UNIQUE(sync_id, method, path)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE request_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    query TEXT NOT NULL,
+    version TEXT DEFAULT NULL,
+    environment TEXT DEFAULT NULL,
+    matched BOOLEAN NOT NULL,
+    endpoint_uuid VARCHAR(36) DEFAULT NULL,
+    response_status INTEGER NOT NULL,
+    ip_address VARCHAR(64) DEFAULT NULL,
+    user_agent TEXT DEFAULT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_request_logs_project_id ON request_logs(project_id, id);
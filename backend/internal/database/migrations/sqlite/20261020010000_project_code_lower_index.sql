@@ -0,0 +1,3 @@
+-- Subdomains are matched case-insensitively against codes created before codes
+-- had to be lowercase; index that lookup so it does not scan every project.
+CREATE INDEX idx_projects_lower_code ON projects(LOWER(code));
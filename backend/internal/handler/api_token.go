@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+type APITokenHandler struct {
+	service service.APITokenService
+}
+
+func NewAPITokenHandler(service service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{service: service}
+}
+
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.service.CreateToken(&req, userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "expiry must be in the future":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"token": token})
+}
+
+func (h *APITokenHandler) GetTokens(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tokens, err := h.service.GetTokens(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	err := h.service.RevokeToken(c.Param("token_uuid"), userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "token not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// RevokeCurrentToken revokes the API token the request is made with, so that a
+// client can discard a token it created without holding a login session.
+func (h *APITokenHandler) RevokeCurrentToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tokenUUID, isAPIToken := c.Get("token_uuid")
+	if !isAPIToken {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This request is not made with an API token"})
+		return
+	}
+
+	if err := h.service.RevokeToken(tokenUUID.(string), userID.(int)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
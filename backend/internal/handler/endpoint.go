@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -15,12 +17,14 @@ import (
 type EndpointHandler struct {
 	service        service.EndpointService
 	projectService service.ProjectService
+	hosts          service.MockHosts
 }
 
-func NewEndpointHandler(service service.EndpointService, projectService service.ProjectService) *EndpointHandler {
+func NewEndpointHandler(service service.EndpointService, projectService service.ProjectService, hosts service.MockHosts) *EndpointHandler {
 	return &EndpointHandler{
 		service:        service,
 		projectService: projectService,
+		hosts:          hosts,
 	}
 }
 
@@ -43,13 +47,17 @@ func (h *EndpointHandler) CreateEndpoint(c *gin.Context) {
 		return
 	}
 
-	endpoint, err := h.service.CreateEndpoint(&req, projectUUID, userID.(int))
+	endpoint, err := h.service.CreateEndpoint(&req, projectUUID, userID.(int), clientInfo(c))
 	if err != nil {
-		switch err.Error() {
-		case "project not found":
+		switch {
+		case errors.Is(err, service.ErrInvalidEndpoint):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err.Error() == "project not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case "endpoint with same method and path already exists":
+		case err.Error() == "endpoint with same method and path already exists":
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -98,6 +106,8 @@ func (h *EndpointHandler) ImportOpenAPIYAML(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case err.Error() == "project not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInsufficientPermissions):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -131,11 +141,13 @@ func (h *EndpointHandler) CreateEndpointsBulk(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.CreateEndpointsBulk(projectUUID, req.Endpoints, userID.(int))
+	result, err := h.service.CreateEndpointsBulk(projectUUID, req.Endpoints, userID.(int), clientInfo(c))
 	if err != nil {
 		switch err.Error() {
 		case "project not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -145,35 +157,162 @@ func (h *EndpointHandler) CreateEndpointsBulk(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"result": result})
 }
 
+// mockEnvironmentHeader selects the environment whose variables are expanded in
+// a mock response.
+const mockEnvironmentHeader = "X-Crudbox-Environment"
+
+// MockHandler serves the mocks of the project whose code is the first path
+// segment. The segment may name a version and an environment as
+// code@version~environment; both are optional.
 func (h *EndpointHandler) MockHandler(c *gin.Context) {
-	code := c.Param("code")
+	segment := c.Param("code")
+	rest, environment, _ := strings.Cut(segment, "~")
+	code, version, _ := strings.Cut(rest, "@")
 	path := c.Param("path")
-	method := c.Request.Method
 
 	project, err := h.projectService.GetByCode(code)
 	if err != nil {
+		// Projects whose code was changed keep answering on the old code via a redirect
+		if newCode, redirectErr := h.projectService.ResolveCodeRedirect(code); redirectErr == nil {
+			target := "/" + newCode + strings.TrimPrefix(segment, code) + path
+			if c.Request.URL.RawQuery != "" {
+				target += "?" + c.Request.URL.RawQuery
+			}
+			c.Redirect(http.StatusPermanentRedirect, target)
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
 
-	endpoint, err := h.service.GetByProjectIDAndPath(project.ID, path, method)
+	h.serveMock(c, project, version, environment, path)
+}
+
+// MockHostRouting serves mocks for requests whose Host header identifies a
+// project, either as a subdomain of the configured mock base domain (the
+// subdomain being the project code) or as a project's registered hostname.
+// Requests for the API's own hosts and any other host continue to the regular
+// routes without a lookup.
+func (h *EndpointHandler) MockHostRouting(c *gin.Context) {
+	host := service.NormalizeHostname(c.Request.Host)
+	if !strings.Contains(host, ".") || h.hosts.IsReserved(host) {
+		c.Next()
+		return
+	}
+
+	if h.hosts.CustomHostnames {
+		if project, err := h.projectService.GetByHostname(host); err == nil {
+			h.serveMock(c, project, "", "", c.Request.URL.Path)
+			c.Abort()
+			return
+		}
+	}
+
+	baseDomain := h.hosts.BaseDomain
+	if baseDomain == "" || !strings.HasSuffix(host, "."+baseDomain) {
+		c.Next()
+		return
+	}
+
+	code := strings.TrimSuffix(host, "."+baseDomain)
+	if strings.Contains(code, ".") {
+		c.Next()
+		return
+	}
+
+	project, err := h.projectService.GetBySubdomain(code)
+	if errors.Is(err, service.ErrCodeNotSubdomainSafe) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project code contains uppercase letters; change it to serve the project on a subdomain, or use the /{code}/ path"})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		if newCode, redirectErr := h.projectService.ResolveCodeRedirect(code); redirectErr == nil {
+			target := *c.Request.URL
+			target.Scheme = "http"
+			if c.Request.TLS != nil {
+				target.Scheme = "https"
+			}
+			target.Host = newCode + "." + baseDomain
+			if _, port, splitErr := net.SplitHostPort(c.Request.Host); splitErr == nil {
+				target.Host = net.JoinHostPort(target.Host, port)
+			}
+			c.Redirect(http.StatusPermanentRedirect, target.String())
+			c.Abort()
+			return
+		}
+		c.Next()
+		return
+	}
+
+	h.serveMock(c, project, "", "", c.Request.URL.Path)
+	c.Abort()
+}
+
+// serveMock answers with the endpoint of project matching the request. An empty
+// version serves the current endpoints. Without an environment from the URL, the
+// one named in the mockEnvironmentHeader header or else the project's default
+// environment provides the variables.
+func (h *EndpointHandler) serveMock(c *gin.Context, project *contracts.Project, version, environment, path string) {
+	if environment == "" {
+		environment = c.GetHeader(mockEnvironmentHeader)
+	}
+
+	// Every request is logged once answered, including those no endpoint matched
+	var matched bool
+	var endpointUUID string
+	defer func() {
+		h.projectService.RecordRequest(project.ID, &contracts.MockRequest{
+			Method:         c.Request.Method,
+			Path:           path,
+			Query:          c.Request.URL.RawQuery,
+			Version:        version,
+			Environment:    environment,
+			Matched:        matched,
+			EndpointUUID:   endpointUUID,
+			ResponseStatus: c.Writer.Status(),
+		}, clientInfo(c))
+	}()
+
+	variables, err := h.projectService.GetMockVariables(project.ID, environment)
+	if err != nil {
+		if errors.Is(err, service.ErrEnvironmentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Environment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var endpoint *contracts.Endpoint
+	if version == "" {
+		endpoint, err = h.service.GetByProjectIDAndPath(project.ID, path, c.Request.Method)
+	} else {
+		endpoint, err = h.projectService.GetVersionEndpoint(project.ID, version, path, c.Request.Method)
+	}
 	if err != nil {
+		if errors.Is(err, service.ErrVersionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Endpoint not found"})
 		return
 	}
+	matched, endpointUUID = true, endpoint.UUID
 
 	// Set headers
 	if endpoint.ResponseHeaders != "" {
 		var headers map[string]string
 		if err := json.Unmarshal([]byte(endpoint.ResponseHeaders), &headers); err == nil {
 			for k, v := range headers {
-				c.Header(k, v)
+				c.Header(k, service.ExpandVariables(v, variables))
 			}
 		}
 	}
 
-	c.Data(endpoint.ResponseStatus, "application/json", []byte(endpoint.ResponseBody))
+	c.Data(endpoint.ResponseStatus, "application/json", []byte(service.ExpandVariables(endpoint.ResponseBody, variables)))
 }
+
 func (h *EndpointHandler) UpdateEndpoint(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -193,13 +332,17 @@ func (h *EndpointHandler) UpdateEndpoint(c *gin.Context) {
 		return
 	}
 
-	endpoint, err := h.service.UpdateEndpoint(endpointUUID, &req, userID.(int))
+	endpoint, err := h.service.UpdateEndpoint(endpointUUID, &req, userID.(int), clientInfo(c))
 	if err != nil {
-		switch err.Error() {
-		case "endpoint not found", "project not found":
+		switch {
+		case errors.Is(err, service.ErrInvalidEndpoint):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err.Error() == "endpoint not found", err.Error() == "project not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case "endpoint with same method and path already exists":
+		case err.Error() == "endpoint with same method and path already exists":
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -222,11 +365,13 @@ func (h *EndpointHandler) DeleteEndpoint(c *gin.Context) {
 		return
 	}
 
-	err := h.service.DeleteEndpoint(endpointUUID, userID.(int))
+	err := h.service.DeleteEndpoint(endpointUUID, userID.(int), clientInfo(c))
 	if err != nil {
 		switch err.Error() {
 		case "endpoint not found", "project not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -253,6 +398,8 @@ func (h *EndpointHandler) GetEndpoints(c *gin.Context) {
 		switch err.Error() {
 		case "project not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -280,6 +427,56 @@ func (h *EndpointHandler) GetEndpoint(c *gin.Context) {
 		switch err.Error() {
 		case "endpoint not found", "project not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"endpoint": endpoint})
+}
+
+func (h *EndpointHandler) GetTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoints, err := h.service.GetTrash(c.Param("project_uuid"), userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "project not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"endpoints": endpoints})
+}
+
+func (h *EndpointHandler) RestoreEndpoint(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoint, err := h.service.RestoreEndpoint(c.Param("endpoint_uuid"), userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "endpoint not found", "project not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "endpoint with same method and path already exists":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+func (h *EndpointHandler) GetRevisions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(c.Param("endpoint_uuid"), userID.(int))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+func (h *EndpointHandler) DiffRevisions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var query contracts.EndpointRevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(c.Param("endpoint_uuid"), query.From, query.To, userID.(int))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"diff": diff})
+}
+
+func (h *EndpointHandler) RestoreRevision(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	endpoint, err := h.service.RestoreRevision(c.Param("endpoint_uuid"), revision, userID.(int), clientInfo(c))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"endpoint": endpoint})
+}
+
+func revisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRevisionNotFound), err.Error() == "endpoint not found", err.Error() == "project not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err.Error() == "endpoint with same method and path already exists":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
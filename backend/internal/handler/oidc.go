@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+// oidcFlowCookie holds the signed state of a single sign-on login between the
+// redirect to the provider and its callback.
+const oidcFlowCookie = "crudbox_oidc_flow"
+
+type OIDCHandler struct {
+	service    service.OIDCService
+	appBaseURL string
+}
+
+func NewOIDCHandler(service service.OIDCService, appBaseURL string) *OIDCHandler {
+	return &OIDCHandler{
+		service:    service,
+		appBaseURL: strings.TrimSuffix(appBaseURL, "/"),
+	}
+}
+
+func (h *OIDCHandler) Login(c *gin.Context) {
+	login, err := h.service.BeginLogin(c.Request.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOIDCNotConfigured):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, login.Flow, 600, "/auth/oidc", "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, login.AuthorizationURL)
+}
+
+// Callback completes the login and hands the tokens to the frontend in the URL
+// fragment, which is never sent to servers or written to access logs.
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, "", -1, "/auth/oidc", "", c.Request.TLS != nil, true)
+
+	if providerError := c.Query("error"); providerError != "" {
+		description := c.Query("error_description")
+		if description == "" {
+			description = providerError
+		}
+		h.redirectToApp(c, url.Values{"error": {description}})
+		return
+	}
+
+	flow, err := c.Cookie(oidcFlowCookie)
+	if err != nil {
+		h.redirectToApp(c, url.Values{"error": {"login attempt expired, please try again"}})
+		return
+	}
+
+	tokens, err := h.service.CompleteLogin(c.Request.Context(), c.Query("code"), c.Query("state"), flow, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCNotConfigured) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.redirectToApp(c, url.Values{"error": {err.Error()}})
+		return
+	}
+
+	h.redirectToApp(c, url.Values{
+		"token":         {tokens.Token},
+		"refresh_token": {tokens.RefreshToken},
+		"expires_in":    {strconv.Itoa(tokens.ExpiresIn)},
+	})
+}
+
+func (h *OIDCHandler) redirectToApp(c *gin.Context, fragment url.Values) {
+	c.Redirect(http.StatusFound, h.appBaseURL+"/auth/callback#"+fragment.Encode())
+}
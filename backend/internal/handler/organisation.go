@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,7 +31,7 @@ func (h *OrganisationHandler) CreateOrganisation(c *gin.Context) {
 		return
 	}
 
-	org, err := h.service.CreateOrganisation(&req, userID.(int))
+	org, err := h.service.CreateOrganisation(&req, userID.(int), clientInfo(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -54,3 +55,117 @@ func (h *OrganisationHandler) GetOrganisations(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"organisations": organisations})
 }
+
+func (h *OrganisationHandler) UpdateOrganisation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.UpdateOrganisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := h.service.UpdateOrganisation(c.Param("org_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"organisation": org})
+}
+
+func (h *OrganisationHandler) DeleteOrganisation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	err := h.service.DeleteOrganisation(c.Param("org_uuid"), userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (h *OrganisationHandler) TransferOwnership(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := h.service.TransferOwnership(c.Param("org_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "organisation not found", "member not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "user is already the organisation owner":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"organisation": org})
+}
+
+func (h *OrganisationHandler) GetAuditLog(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var query contracts.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.GetAuditLog(c.Param("org_uuid"), &query, userID.(int))
+	if err != nil {
+		switch {
+		case err.Error() == "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidAuditCursor):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+func (h *OrganisationHandler) GetMembers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	members, err := h.service.GetMembers(c.Param("org_uuid"), userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+func (h *OrganisationHandler) RemoveMember(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	err := h.service.RemoveMember(c.Param("org_uuid"), c.Param("user_uuid"), userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "organisation not found", "member not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "the organisation owner cannot be removed":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (h *OrganisationHandler) UpdateMemberRole(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.service.UpdateMemberRole(c.Param("org_uuid"), c.Param("user_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "invalid role":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "organisation not found", "member not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "the organisation owner's role cannot be changed":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"member": member})
+}
+
+func (h *OrganisationHandler) CreateInvitation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, err := h.service.CreateInvitation(c.Param("org_uuid"), &req, userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "invalid role":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "user is already a member of the organisation":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invitation": invitation})
+}
+
+func (h *OrganisationHandler) GetInvitations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invitations, err := h.service.GetPendingInvitations(c.Param("org_uuid"), userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+func (h *OrganisationHandler) RevokeInvitation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	err := h.service.RevokeInvitation(c.Param("org_uuid"), c.Param("invitation_uuid"), userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "organisation not found", "invitation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (h *OrganisationHandler) AcceptInvitation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := h.service.AcceptInvitation(&req, userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "invitation not found", "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invitation was issued to a different email address":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "user is already a member of the organisation":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "invitation has expired or was already used":
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"organisation": org})
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,12 +32,16 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		return
 	}
 
-	project, err := h.service.CreateProject(&req, userID.(int))
+	project, err := h.service.CreateProject(&req, userID.(int), clientInfo(c))
 	if err != nil {
-		switch err.Error() {
-		case "organisation not found":
+		switch {
+		case errors.Is(err, service.ErrInvalidProjectCode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrProjectCodeTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "organisation not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case "user does not belong to the specified organisation":
+		case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -62,6 +68,45 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"projects": projects})
 }
 
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectUUID := c.Param("project_uuid")
+	if projectUUID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project UUID"})
+		return
+	}
+
+	var req contracts.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.service.UpdateProject(projectUUID, &req, userID.(int), clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidProjectCode), errors.Is(err, service.ErrInvalidHostname):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrProjectCodeTaken), errors.Is(err, service.ErrHostnameTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "project not found", err.Error() == "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
 func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -74,11 +119,13 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 		return
 	}
 
-	err := h.service.DeleteProject(projectUUID, userID.(int))
+	err := h.service.DeleteProject(projectUUID, userID.(int), clientInfo(c))
 	if err != nil {
 		switch err.Error() {
 		case "no project found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -87,3 +134,153 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 
 	c.JSON(http.StatusOK, nil)
 }
+
+func (h *ProjectHandler) ExportProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectUUID := c.Param("project_uuid")
+	if projectUUID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project UUID"})
+		return
+	}
+
+	bundle, err := h.service.ExportProject(projectUUID, userID.(int))
+	if err != nil {
+		switch err.Error() {
+		case "project not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "crudbox-"+bundle.Project.Code+".json"))
+	c.JSON(http.StatusOK, bundle)
+}
+
+func (h *ProjectHandler) ImportProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	orgUUID := c.Param("org_uuid")
+	if orgUUID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organisation UUID"})
+		return
+	}
+
+	var bundle contracts.ProjectBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.service.ImportProject(orgUUID, &bundle, userID.(int), clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnsupportedBundleVersion), errors.Is(err, service.ErrInvalidProjectBundle):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err.Error() == "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"project": project})
+}
+
+func (h *ProjectHandler) CloneProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectUUID := c.Param("project_uuid")
+	if projectUUID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project UUID"})
+		return
+	}
+
+	var req contracts.CloneProjectRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	project, err := h.service.CloneProject(projectUUID, &req, userID.(int), clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "project not found", "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "user does not belong to the specified organisation", "insufficient permissions":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"project": project})
+}
+
+func (h *ProjectHandler) GetTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	trash, err := h.service.GetTrash(c.Param("org_uuid"), userID.(int))
+	if err != nil {
+		switch {
+		case err.Error() == "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"trash": trash})
+}
+
+func (h *ProjectHandler) RestoreProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	project, err := h.service.RestoreProject(c.Param("project_uuid"), userID.(int), clientInfo(c))
+	if err != nil {
+		switch {
+		case err.Error() == "project not found", err.Error() == "organisation not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInsufficientPermissions):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+func (h *ProjectHandler) CreateBranch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.CreateProjectBranchRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	branch, err := h.service.CreateBranch(c.Param("project_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		branchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"branch": branch})
+}
+
+func (h *ProjectHandler) GetBranches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	branches, err := h.service.GetBranches(c.Param("project_uuid"), userID.(int))
+	if err != nil {
+		branchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"branches": branches})
+}
+
+func (h *ProjectHandler) GetMergePreview(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	preview, err := h.service.GetMergePreview(c.Param("project_uuid"), userID.(int))
+	if err != nil {
+		branchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"merge": preview})
+}
+
+func (h *ProjectHandler) MergeBranch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.MergeProjectBranchRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := h.service.MergeBranch(c.Param("project_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		branchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
+
+func branchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotABranch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrParentProjectMissing), err.Error() == "project not found", err.Error() == "organisation not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnresolvedConflicts):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+func (h *ProjectHandler) CreateEnvironment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.CreateProjectEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	environment, err := h.service.CreateEnvironment(c.Param("project_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		environmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"environment": environment})
+}
+
+func (h *ProjectHandler) GetEnvironments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	environments, err := h.service.GetEnvironments(c.Param("project_uuid"), userID.(int))
+	if err != nil {
+		environmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"environments": environments})
+}
+
+func (h *ProjectHandler) UpdateEnvironment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.UpdateProjectEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	environment, err := h.service.UpdateEnvironment(c.Param("project_uuid"), c.Param("environment_name"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		environmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"environment": environment})
+}
+
+func (h *ProjectHandler) DeleteEnvironment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.service.DeleteEnvironment(c.Param("project_uuid"), c.Param("environment_name"), userID.(int), clientInfo(c)); err != nil {
+		environmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func environmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidEnvironmentName), errors.Is(err, service.ErrInvalidVariableName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEnvironmentNotFound), err.Error() == "project not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEnvironmentNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
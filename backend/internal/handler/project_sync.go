@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+func (h *ProjectHandler) GetSyncStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	status, err := h.service.GetSyncStatus(c.Param("project_uuid"), userID.(int))
+	if err != nil {
+		syncError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sync": status})
+}
+
+func (h *ProjectHandler) SyncProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.SyncProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := h.service.SyncProject(c.Param("project_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		syncError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sync": plan})
+}
+
+func syncError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSyncRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err.Error() == "project not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+func (h *ProjectHandler) CreateVersion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contracts.CreateProjectVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, err := h.service.CreateVersion(c.Param("project_uuid"), &req, userID.(int), clientInfo(c))
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"version": version})
+}
+
+func (h *ProjectHandler) GetVersions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	versions, err := h.service.GetVersions(c.Param("project_uuid"), userID.(int))
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+func (h *ProjectHandler) GetVersion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	version, err := h.service.GetVersion(c.Param("project_uuid"), c.Param("version_name"), userID.(int))
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version": version})
+}
+
+func (h *ProjectHandler) DeleteVersion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.service.DeleteVersion(c.Param("project_uuid"), c.Param("version_name"), userID.(int), clientInfo(c)); err != nil {
+		versionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (h *ProjectHandler) RestoreVersion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result, err := h.service.RestoreVersion(c.Param("project_uuid"), c.Param("version_name"), userID.(int), clientInfo(c))
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
+
+func versionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidVersionName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrVersionNotFound), err.Error() == "project not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrVersionNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrInsufficientPermissions):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -14,6 +14,9 @@ type Server struct {
 	organisationHandler *OrganisationHandler
 	projectHandler      *ProjectHandler
 	endpointHandler     *EndpointHandler
+	apiTokenHandler     *APITokenHandler
+	oidcHandler         *OIDCHandler
+	jwksHandler         *JWKSHandler
 }
 
 func NewServer(
@@ -21,39 +24,54 @@ func NewServer(
 	organisationHandler *OrganisationHandler,
 	projectHandler *ProjectHandler,
 	endpointHandler *EndpointHandler,
+	apiTokenHandler *APITokenHandler,
+	oidcHandler *OIDCHandler,
+	jwksHandler *JWKSHandler,
 ) *Server {
 	return &Server{
 		userHandler:         userHandler,
 		organisationHandler: organisationHandler,
 		projectHandler:      projectHandler,
 		endpointHandler:     endpointHandler,
+		apiTokenHandler:     apiTokenHandler,
+		oidcHandler:         oidcHandler,
+		jwksHandler:         jwksHandler,
 	}
 }
 
-func (s *Server) SetupRoutes() *gin.Engine {
-	r := gin.Default()
-	r.RedirectTrailingSlash = false
-	r.RedirectFixedPath = false
-
-	// Configure CORS middleware for all routes
-	r.Use(cors.New(cors.Config{
-		AllowAllOrigins: true,
-		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:    []string{"*"},
-		AllowWildcard:   true,
-		ExposeHeaders:   []string{"Content-Length"},
-		MaxAge:          12 * time.Hour,
-	}))
+// ProxyConfig controls which requests may report the client's address, which
+// throttling and the audit log rely on.
+type ProxyConfig struct {
+	// TrustedProxies are the addresses or CIDR ranges of reverse proxies whose
+	// X-Forwarded-For header is believed. Without any, the connection's address
+	// is used.
+	TrustedProxies []string
+	// TrustedPlatform names a header set by the hosting platform that holds the
+	// client address, such as CF-Connecting-IP behind Cloudflare.
+	TrustedPlatform string
+}
 
-	r.GET("/ping", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "pong",
-		})
-	})
+func (s *Server) SetupRoutes(proxies ProxyConfig) (*gin.Engine, error) {
+	r, err := newEngine(s.endpointHandler, proxies)
+	if err != nil {
+		return nil, err
+	}
 
 	// Auth routes
 	r.POST("/signup", s.userHandler.SignUp)
 	r.POST("/login", s.userHandler.Login)
+	r.POST("/token/refresh", s.userHandler.RefreshToken)
+	r.POST("/password/forgot", s.userHandler.ForgotPassword)
+	r.POST("/password/reset", s.userHandler.ResetPassword)
+	r.POST("/email/verify", s.userHandler.VerifyEmail)
+	r.POST("/email/resend", s.userHandler.ResendVerificationEmail)
+
+	// Public keys for verifying access tokens
+	r.GET("/.well-known/jwks.json", s.jwksHandler.GetJWKS)
+
+	// Single sign-on through an OpenID Connect provider
+	r.GET("/auth/oidc/login", s.oidcHandler.Login)
+	r.GET("/auth/oidc/callback", s.oidcHandler.Callback)
 
 	// Protected routes
 	protected := r.Group("/")
@@ -61,24 +79,129 @@ func (s *Server) SetupRoutes() *gin.Engine {
 	{
 		protected.POST("/organisation", s.organisationHandler.CreateOrganisation)
 		protected.GET("/organisations", s.organisationHandler.GetOrganisations)
+		protected.PATCH("/organisation/:org_uuid", s.organisationHandler.UpdateOrganisation)
+		protected.DELETE("/organisation/:org_uuid", s.organisationHandler.DeleteOrganisation)
+		protected.POST("/organisation/:org_uuid/transfer", s.organisationHandler.TransferOwnership)
+		protected.GET("/organisation/:org_uuid/audit", s.organisationHandler.GetAuditLog)
+		protected.GET("/organisation/:org_uuid/trash", s.projectHandler.GetTrash)
+		protected.POST("/organisation/:org_uuid/import", s.projectHandler.ImportProject)
+		protected.GET("/organisation/:org_uuid/members", s.organisationHandler.GetMembers)
+		protected.PATCH("/organisation/:org_uuid/members/:user_uuid", s.organisationHandler.UpdateMemberRole)
+		protected.DELETE("/organisation/:org_uuid/members/:user_uuid", s.organisationHandler.RemoveMember)
+		protected.POST("/organisation/:org_uuid/invitations", s.organisationHandler.CreateInvitation)
+		protected.GET("/organisation/:org_uuid/invitations", s.organisationHandler.GetInvitations)
+		protected.DELETE("/organisation/:org_uuid/invitations/:invitation_uuid", s.organisationHandler.RevokeInvitation)
+		protected.POST("/invitations/accept", s.organisationHandler.AcceptInvitation)
 
 		protected.POST("/project", s.projectHandler.CreateProject)
 		protected.GET("/projects", s.projectHandler.GetProjects)
+		protected.PATCH("/project/:project_uuid", s.projectHandler.UpdateProject)
 		protected.DELETE("/project/:project_uuid", s.projectHandler.DeleteProject)
+		protected.GET("/project/:project_uuid/export", s.projectHandler.ExportProject)
+		protected.POST("/project/:project_uuid/clone", s.projectHandler.CloneProject)
+		protected.POST("/project/:project_uuid/restore", s.projectHandler.RestoreProject)
+		protected.POST("/project/:project_uuid/branches", s.projectHandler.CreateBranch)
+		protected.GET("/project/:project_uuid/branches", s.projectHandler.GetBranches)
+		protected.GET("/project/:project_uuid/merge", s.projectHandler.GetMergePreview)
+		protected.POST("/project/:project_uuid/merge", s.projectHandler.MergeBranch)
+		protected.GET("/project/:project_uuid/sync", s.projectHandler.GetSyncStatus)
+		protected.POST("/project/:project_uuid/sync", s.projectHandler.SyncProject)
+		protected.GET("/project/:project_uuid/requests", s.projectHandler.GetRequestLog)
+		protected.POST("/project/:project_uuid/environments", s.projectHandler.CreateEnvironment)
+		protected.GET("/project/:project_uuid/environments", s.projectHandler.GetEnvironments)
+		protected.PATCH("/project/:project_uuid/environments/:environment_name", s.projectHandler.UpdateEnvironment)
+		protected.DELETE("/project/:project_uuid/environments/:environment_name", s.projectHandler.DeleteEnvironment)
+		protected.POST("/project/:project_uuid/versions", s.projectHandler.CreateVersion)
+		protected.GET("/project/:project_uuid/versions", s.projectHandler.GetVersions)
+		protected.GET("/project/:project_uuid/versions/:version_name", s.projectHandler.GetVersion)
+		protected.DELETE("/project/:project_uuid/versions/:version_name", s.projectHandler.DeleteVersion)
+		protected.POST("/project/:project_uuid/versions/:version_name/restore", s.projectHandler.RestoreVersion)
+		protected.GET("/project/:project_uuid/trash", s.endpointHandler.GetTrash)
 		protected.POST("/project/:project_uuid/upload/openapiyml", s.endpointHandler.ImportOpenAPIYAML)
 		protected.POST("/project/:project_uuid/endpoints/bulk", s.endpointHandler.CreateEndpointsBulk)
 		protected.POST("/project/:project_uuid/endpoint", s.endpointHandler.CreateEndpoint)
 		protected.GET("/endpoint/:endpoint_uuid", s.endpointHandler.GetEndpoint)
 		protected.PUT("/endpoint/:endpoint_uuid", s.endpointHandler.UpdateEndpoint)
+		protected.GET("/endpoint/:endpoint_uuid/revisions", s.endpointHandler.GetRevisions)
+		protected.GET("/endpoint/:endpoint_uuid/revisions/diff", s.endpointHandler.DiffRevisions)
+		protected.POST("/endpoint/:endpoint_uuid/revisions/:revision/restore", s.endpointHandler.RestoreRevision)
 		protected.GET("/project/:project_uuid/endpoints", s.endpointHandler.GetEndpoints)
 
 		protected.DELETE("/endpoint/:endpoint_uuid", s.endpointHandler.DeleteEndpoint)
+		protected.POST("/endpoint/:endpoint_uuid/restore", s.endpointHandler.RestoreEndpoint)
 
 		protected.GET("/user", s.userHandler.GetByID)
+
+		// Logging out revokes login sessions, which API tokens do not have
+		logout := protected.Group("/logout")
+		logout.Use(middleware.RequireSessionAuth())
+		{
+			logout.POST("", s.userHandler.Logout)
+			logout.POST("/all", s.userHandler.LogoutAll)
+		}
+
+		// A token may revoke itself, which is how the CLI logs out
+		protected.DELETE("/user/tokens/self", s.apiTokenHandler.RevokeCurrentToken)
+
+		// API tokens can only be managed from a login session, never with another token
+		tokens := protected.Group("/user/tokens")
+		tokens.Use(middleware.RequireSessionAuth())
+		{
+			tokens.POST("", s.apiTokenHandler.CreateToken)
+			tokens.GET("", s.apiTokenHandler.GetTokens)
+			tokens.DELETE("/:token_uuid", s.apiTokenHandler.RevokeToken)
+		}
 	}
 
 	// Mock endpoint (no auth required)
 	r.Any("/:code/*path", s.endpointHandler.MockHandler)
 
-	return r
+	return r, nil
+}
+
+// SetupMockRoutes returns a router that only serves mocks, for running without
+// the dashboard API.
+func SetupMockRoutes(endpointHandler *EndpointHandler) (*gin.Engine, error) {
+	r, err := newEngine(endpointHandler, ProxyConfig{})
+	if err != nil {
+		return nil, err
+	}
+	r.Any("/:code/*path", endpointHandler.MockHandler)
+	return r, nil
+}
+
+// newEngine creates a router with the middleware and health check shared by
+// every way of running the server.
+func newEngine(endpointHandler *EndpointHandler, proxies ProxyConfig) (*gin.Engine, error) {
+	r := gin.Default()
+	r.RedirectTrailingSlash = false
+	r.RedirectFixedPath = false
+
+	// Gin trusts X-Forwarded-For from anyone by default, which would let clients
+	// pick the address they are throttled and audited under
+	if err := r.SetTrustedProxies(proxies.TrustedProxies); err != nil {
+		return nil, err
+	}
+	r.TrustedPlatform = proxies.TrustedPlatform
+
+	// Configure CORS middleware for all routes
+	r.Use(cors.New(cors.Config{
+		AllowAllOrigins: true,
+		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:    []string{"*"},
+		AllowWildcard:   true,
+		ExposeHeaders:   []string{"Content-Length"},
+		MaxAge:          12 * time.Hour,
+	}))
+
+	// Mocks addressed by Host header take precedence over every other route
+	r.Use(endpointHandler.MockHostRouting)
+
+	r.GET("/ping", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"message": "pong",
+		})
+	})
+
+	return r, nil
 }
@@ -0,0 +1,284 @@
+// Package jwtkeys signs and verifies the JWTs crudbox issues. A key set holds
+// either a single HMAC secret or a list of asymmetric keys identified by kid,
+// of which the most recently activated one signs new tokens.
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no active signing key")
+	ErrUnknownKey   = errors.New("token was signed with an unknown key")
+)
+
+type key struct {
+	id         string
+	method     jwt.SigningMethod
+	signingKey any
+	verifyKey  any
+	activeFrom time.Time
+	expiresAt  *time.Time
+}
+
+func (k *key) usable(now time.Time) bool {
+	return k.expiresAt == nil || now.Before(*k.expiresAt)
+}
+
+// KeySet signs tokens with its current key and verifies tokens signed by any of
+// its keys, always pinning the algorithm to the one of the key named by kid.
+type KeySet struct {
+	// keys are sorted by activation, oldest first
+	keys []*key
+}
+
+// NewHMACKeySet returns a key set that signs with HS256 and the given secret.
+func NewHMACKeySet(secret []byte) *KeySet {
+	return &KeySet{keys: []*key{{
+		method:     jwt.SigningMethodHS256,
+		signingKey: secret,
+		verifyKey:  secret,
+	}}}
+}
+
+// KeyConfig describes one asymmetric key. The private key is given inline as
+// PEM or as the path of a PEM file; RSA keys sign with RS256 and Ed25519 keys
+// with EdDSA.
+type KeyConfig struct {
+	ID             string     `json:"kid"`
+	PrivateKey     string     `json:"private_key"`
+	PrivateKeyFile string     `json:"private_key_file"`
+	ActiveFrom     time.Time  `json:"active_from"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+// Config lists the keys of a key set. Rotation is scheduled by adding a key
+// whose active_from lies in the future: it is published right away so that
+// verifiers can fetch it, and signs tokens once active_from has passed. Old keys
+// keep verifying tokens until they expire or are removed from the list.
+type Config struct {
+	Keys []KeyConfig `json:"keys"`
+}
+
+// Load reads a key set configuration from raw JSON. Relative key file paths are
+// resolved against baseDir.
+func Load(raw []byte, baseDir string) (*KeySet, error) {
+	var config Config
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("invalid key configuration: %w", err)
+	}
+	if len(config.Keys) == 0 {
+		return nil, errors.New("key configuration lists no keys")
+	}
+
+	set := &KeySet{}
+	seen := make(map[string]bool)
+	for _, kc := range config.Keys {
+		if kc.ID == "" {
+			return nil, errors.New("every key needs a kid")
+		}
+		if seen[kc.ID] {
+			return nil, fmt.Errorf("duplicate kid %q", kc.ID)
+		}
+		seen[kc.ID] = true
+
+		pemData := []byte(kc.PrivateKey)
+		if kc.PrivateKeyFile != "" {
+			path := kc.PrivateKeyFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read key %q: %w", kc.ID, err)
+			}
+			pemData = data
+		}
+
+		k, err := parsePrivateKey(pemData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", kc.ID, err)
+		}
+		k.id = kc.ID
+		k.activeFrom = kc.ActiveFrom
+		k.expiresAt = kc.ExpiresAt
+		set.keys = append(set.keys, k)
+	}
+
+	sort.SliceStable(set.keys, func(i, j int) bool {
+		return set.keys[i].activeFrom.Before(set.keys[j].activeFrom)
+	})
+
+	return set, nil
+}
+
+// LoadFile reads a key set configuration from a JSON file.
+func LoadFile(path string) (*KeySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key configuration: %w", err)
+	}
+	return Load(raw, filepath.Dir(path))
+}
+
+func parsePrivateKey(data []byte) (*key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch privateKey := parsed.(type) {
+	case *rsa.PrivateKey:
+		if privateKey.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must have at least 2048 bits")
+		}
+		return &key{method: jwt.SigningMethodRS256, signingKey: privateKey, verifyKey: privateKey.Public()}, nil
+	case ed25519.PrivateKey:
+		return &key{method: jwt.SigningMethodEdDSA, signingKey: privateKey, verifyKey: privateKey.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", parsed)
+	}
+}
+
+// current returns the key that signs new tokens: the most recently activated
+// key that has not expired.
+func (s *KeySet) current(now time.Time) (*key, error) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		k := s.keys[i]
+		if !k.activeFrom.After(now) && k.usable(now) {
+			return k, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// Sign signs claims with the current key, naming it in the kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	k, err := s.current(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.id != "" {
+		token.Header["kid"] = k.id
+	}
+	return token.SignedString(k.signingKey)
+}
+
+// Parse verifies tokenString and decodes it into claims. Only the algorithm of
+// the key named by the kid header is accepted.
+func (s *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	keyFunc := func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		k := s.lookup(kid)
+		if k == nil || !k.usable(time.Now()) {
+			return nil, ErrUnknownKey
+		}
+		if token.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return k.verifyKey, nil
+	}
+
+	token, err := jwt.NewParser(jwt.WithValidMethods(s.methods())).ParseWithClaims(tokenString, claims, keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return token, nil
+}
+
+func (s *KeySet) lookup(kid string) *key {
+	for _, k := range s.keys {
+		if k.id == kid {
+			return k
+		}
+	}
+	return nil
+}
+
+func (s *KeySet) methods() []string {
+	var methods []string
+	for _, k := range s.keys {
+		methods = append(methods, k.method.Alg())
+	}
+	return methods
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKeys returns the public keys that verify tokens, including keys that
+// are scheduled but not yet active. HMAC secrets are never published.
+func (s *KeySet) PublicKeys() *JWKS {
+	now := time.Now()
+	jwks := &JWKS{Keys: []JWK{}}
+	for _, k := range s.keys {
+		if !k.usable(now) {
+			continue
+		}
+		switch publicKey := k.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: k.id,
+				Use: "sig",
+				Alg: k.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: k.id,
+				Use: "sig",
+				Alg: k.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+	return jwks
+}
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+type logSender struct {
+	from string
+}
+
+// NewLogSender writes messages to the application log instead of sending them.
+// It is the default for local development.
+func NewLogSender(from string) Sender {
+	return &logSender{from: from}
+}
+
+func (s *logSender) Send(msg *Message) error {
+	log.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+type fileSender struct {
+	from string
+	dir  string
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// NewFileSender writes every message to its own .eml file in dir, which makes
+// mailed links easy to pick up in tests.
+func NewFileSender(from, dir string) (Sender, error) {
+	if dir == "" {
+		dir = "mail"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &fileSender{from: from, dir: dir}, nil
+}
+
+func (s *fileSender) Send(msg *Message) error {
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(s.dir, name), format(s.from, msg), 0o644)
+}
@@ -0,0 +1,61 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers outbound email.
+type Sender interface {
+	Send(msg *Message) error
+}
+
+type Config struct {
+	// Driver selects the Sender: "smtp", "file" or "log".
+	Driver   string
+	From     string
+	SMTPHost string
+	SMTPPort string
+	Username string
+	Password string
+	// FileDir is where the file driver writes messages.
+	FileDir string
+}
+
+func NewSender(config *Config) (Sender, error) {
+	switch config.Driver {
+	case "smtp":
+		if config.SMTPHost == "" {
+			return nil, fmt.Errorf("smtp mail driver requires a host")
+		}
+		return NewSMTPSender(config)
+	case "file":
+		return NewFileSender(config.From, config.FileDir)
+	case "log", "":
+		return NewLogSender(config.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", config.Driver)
+	}
+}
+
+// format renders msg as an RFC 5322 message.
+func format(from string, msg *Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
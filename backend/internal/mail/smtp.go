@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+)
+
+type smtpSender struct {
+	addr string
+	from string
+	// sender is the bare address of from, which the SMTP envelope needs
+	sender string
+	auth   smtp.Auth
+}
+
+// NewSMTPSender sends mail through an SMTP relay. Authentication is only used
+// when a username is configured.
+func NewSMTPSender(config *Config) (Sender, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+
+	port := config.SMTPPort
+	if port == "" {
+		port = "587"
+	}
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.SMTPHost)
+	}
+
+	return &smtpSender{
+		addr:   net.JoinHostPort(config.SMTPHost, port),
+		from:   config.From,
+		sender: from.Address,
+		auth:   auth,
+	}, nil
+}
+
+func (s *smtpSender) Send(msg *Message) error {
+	return smtp.SendMail(s.addr, s.auth, s.sender, []string{msg.To}, format(s.from, msg))
+}
@@ -2,16 +2,45 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/jwtkeys"
 )
 
-var jwtSecret []byte
+var jwtKeys *jwtkeys.KeySet
+
+// APITokenAuthenticator validates long-lived API tokens and returns the user
+// they belong to together with the token's UUID and scopes.
+type APITokenAuthenticator interface {
+	AuthenticateAPIToken(token string) (userID int, userUUID, tokenUUID string, scopes []string, err error)
+}
+
+var apiTokenAuthenticator APITokenAuthenticator
+
+// SessionValidator checks that the login session an access token belongs to has
+// not been revoked.
+type SessionValidator interface {
+	ValidateSession(sessionUUID string, userID int) error
+}
+
+var sessionValidator SessionValidator
+
+// SetJWTKeys sets the keys access tokens are verified with.
+func SetJWTKeys(keys *jwtkeys.KeySet) {
+	jwtKeys = keys
+}
+
+func SetAPITokenAuthenticator(authenticator APITokenAuthenticator) {
+	apiTokenAuthenticator = authenticator
+}
 
-func SetJWTSecret(secret []byte) {
-	jwtSecret = secret
+func SetSessionValidator(validator SessionValidator) {
+	sessionValidator = validator
 }
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -29,27 +58,88 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-			return jwtSecret, nil
-		})
+		if strings.HasPrefix(tokenString, contracts.APITokenPrefix) && apiTokenAuthenticator != nil {
+			authenticateAPIToken(c, tokenString)
+			return
+		}
 
-		if err != nil || !token.Valid {
+		token, err := jwtKeys.Parse(tokenString, jwt.MapClaims{})
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("user_id", int(claims["user_id"].(float64)))
-			if uuidClaim, ok := claims["user_uuid"].(string); ok {
-				c.Set("user_uuid", uuidClaim)
-			}
-		} else {
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			c.Abort()
+			return
+		}
+
+		userIDClaim, ok := claims["user_id"].(float64)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			c.Abort()
 			return
 		}
+		userID := int(userIDClaim)
 
+		// Access tokens are bound to a login session and stop working once it is revoked
+		sessionUUID, _ := claims["sid"].(string)
+		if sessionValidator != nil {
+			if sessionUUID == "" || sessionValidator.ValidateSession(sessionUUID, userID) != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid session"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("user_id", userID)
+		if uuidClaim, ok := claims["user_uuid"].(string); ok {
+			c.Set("user_uuid", uuidClaim)
+		}
+		if sessionUUID != "" {
+			c.Set("session_uuid", sessionUUID)
+		}
+
+		c.Next()
+	}
+}
+
+// authenticateAPIToken authenticates the request with a personal access token.
+// Tokens without the write scope may only perform safe requests.
+func authenticateAPIToken(c *gin.Context, token string) {
+	userID, userUUID, tokenUUID, scopes, err := apiTokenAuthenticator.AuthenticateAPIToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return
+	}
+
+	safe := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+	if !safe && !slices.Contains(scopes, "write") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Token scope does not allow this request"})
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", userID)
+	c.Set("user_uuid", userUUID)
+	c.Set("token_uuid", tokenUUID)
+	c.Set("token_scopes", scopes)
+	c.Next()
+}
+
+// RequireSessionAuth rejects requests authenticated with an API token. It guards
+// routes such as token management that must only be used from a login session.
+func RequireSessionAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIToken := c.Get("token_scopes"); isAPIToken {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This request cannot be made with an API token"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
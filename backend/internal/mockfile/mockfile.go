@@ -0,0 +1,269 @@
+// Package mockfile serves mocks declared in YAML or JSON files instead of a
+// database. The files are loaded into in-memory repositories through the same
+// services as the API, so codes, hostnames and environments are validated the
+// same way and mocks respond exactly as they would on a full server.
+package mockfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/invopop/yaml"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+// File is the content of one mock file. It either lists several projects under
+// projects or declares a single project at the top level.
+type File struct {
+	Projects []Project `json:"projects"`
+	Project
+}
+
+type Project struct {
+	Name         string        `json:"name"`
+	Code         string        `json:"code"`
+	Hostname     string        `json:"hostname"`
+	Environments []Environment `json:"environments"`
+	Endpoints    []Endpoint    `json:"endpoints"`
+
+	source string
+}
+
+type Environment struct {
+	Name      string            `json:"name"`
+	Default   bool              `json:"default"`
+	Variables map[string]string `json:"variables"`
+}
+
+// Endpoint is a mocked response. Body is sent as is when it is a string and
+// encoded as JSON otherwise, so responses can be written as YAML.
+type Endpoint struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+// Load reads the projects declared in path, which is either a mock file or a
+// directory searched recursively for .yaml, .yml and .json files.
+func Load(path string) ([]Project, error) {
+	files, err := findFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	for _, file := range files {
+		declared, err := parseFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		projects = append(projects, declared...)
+	}
+	return projects, nil
+}
+
+// findFiles returns the mock files at path in lexical order.
+func findFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name != path && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, name)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// EndpointFile is the content of a file declaring endpoints outside of a
+// project. It either lists several endpoints under endpoints or declares a
+// single endpoint at the top level.
+type EndpointFile struct {
+	Endpoints []Endpoint `json:"endpoints"`
+	Endpoint
+}
+
+// LoadEndpoints reads the endpoints declared in an endpoint file and returns
+// them in the form the API creates them.
+func LoadEndpoints(name string) ([]contracts.ProjectBundleEndpoint, error) {
+	var file EndpointFile
+	if err := decodeFile(name, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	declared := Project{Endpoints: file.Endpoints}
+	if file.Method != "" || file.Path != "" || file.Status != 0 || len(file.Headers) > 0 || len(file.Body) > 0 {
+		if len(declared.Endpoints) > 0 {
+			return nil, fmt.Errorf("%s: declare either a list of endpoints or a single endpoint, not both", name)
+		}
+		declared.Endpoints = []Endpoint{file.Endpoint}
+	}
+
+	for i := range declared.Endpoints {
+		if err := declared.Endpoints[i].normalize(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	endpoints, err := declared.BundleEndpoints()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return endpoints, nil
+}
+
+// decodeFile decodes a YAML or JSON file into v, rejecting unknown fields.
+func decodeFile(name string, v any) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so every file goes through the same conversion
+	converted, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(converted))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func parseFile(name string) ([]Project, error) {
+	var file File
+	if err := decodeFile(name, &file); err != nil {
+		return nil, err
+	}
+
+	projects := file.Projects
+	if file.Code != "" || file.Name != "" || len(file.Endpoints) > 0 || len(file.Environments) > 0 {
+		if len(projects) > 0 {
+			return nil, errors.New("declare either a list of projects or a single project, not both")
+		}
+		projects = []Project{file.Project}
+	}
+
+	for i := range projects {
+		if err := projects[i].normalize(); err != nil {
+			return nil, err
+		}
+		projects[i].source = name
+	}
+	return projects, nil
+}
+
+// normalize fills in defaults and rejects what the API would not accept from
+// its own clients either.
+func (p *Project) normalize() error {
+	if p.Code == "" {
+		return errors.New("project code is required")
+	}
+	if p.Name == "" {
+		p.Name = p.Code
+	}
+
+	for i := range p.Endpoints {
+		if err := p.Endpoints[i].normalize(); err != nil {
+			return fmt.Errorf("project %s: %w", p.Code, err)
+		}
+	}
+	return nil
+}
+
+func (e *Endpoint) normalize() error {
+	e.Method = strings.ToUpper(e.Method)
+	if e.Method == "" {
+		e.Method = http.MethodGet
+	}
+	if !strings.HasPrefix(e.Path, "/") {
+		return fmt.Errorf("endpoint %s %q: path must start with /", e.Method, e.Path)
+	}
+	if e.Status == 0 {
+		e.Status = http.StatusOK
+	}
+	if e.Status < 100 || e.Status > 999 {
+		return fmt.Errorf("endpoint %s %s: invalid status %d", e.Method, e.Path, e.Status)
+	}
+	return nil
+}
+
+// BundleEndpoints returns the endpoints of the project in the form the API
+// imports and syncs them.
+func (p *Project) BundleEndpoints() ([]contracts.ProjectBundleEndpoint, error) {
+	endpoints := make([]contracts.ProjectBundleEndpoint, 0, len(p.Endpoints))
+	for _, endpoint := range p.Endpoints {
+		body, err := endpoint.responseBody()
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s %s: %w", endpoint.Method, endpoint.Path, err)
+		}
+		headers, err := endpoint.responseHeaders()
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s %s: %w", endpoint.Method, endpoint.Path, err)
+		}
+		endpoints = append(endpoints, contracts.ProjectBundleEndpoint{
+			Method:          endpoint.Method,
+			Path:            endpoint.Path,
+			ResponseBody:    body,
+			ResponseStatus:  endpoint.Status,
+			ResponseHeaders: headers,
+		})
+	}
+	return endpoints, nil
+}
+
+// responseBody returns the body to serve for the endpoint. An empty body
+// defaults to {} like endpoints created through the API.
+func (e *Endpoint) responseBody() (string, error) {
+	if len(e.Body) == 0 || string(e.Body) == "null" {
+		return "{}", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(e.Body, &text); err == nil {
+		return text, nil
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, e.Body); err != nil {
+		return "", err
+	}
+	return compact.String(), nil
+}
+
+func (e *Endpoint) responseHeaders() (string, error) {
+	if len(e.Headers) == 0 {
+		return "{}", nil
+	}
+	headers, err := json.Marshal(e.Headers)
+	if err != nil {
+		return "", err
+	}
+	return string(headers), nil
+}
@@ -0,0 +1,151 @@
+package mockfile
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSingleProject(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "shop.yaml", `
+code: shop
+hostname: shop.example.com
+environments:
+  - name: dev
+    default: true
+    variables:
+      HOST: dev.example.com
+endpoints:
+  - path: /users
+    body:
+      users: [{id: 1}]
+  - method: post
+    path: /users
+    status: 201
+    headers:
+      Location: /users/1
+    body: created
+`)
+
+	projects, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 {
+		t.Fatalf("Load() returned %d projects, want 1", len(projects))
+	}
+	project := projects[0]
+	if project.Code != "shop" || project.Name != "shop" || project.Hostname != "shop.example.com" {
+		t.Errorf("project = %s %s %s, want code and name shop at shop.example.com", project.Code, project.Name, project.Hostname)
+	}
+	if len(project.Environments) != 1 || !project.Environments[0].Default || project.Environments[0].Variables["HOST"] != "dev.example.com" {
+		t.Errorf("environments = %+v, want the default dev environment", project.Environments)
+	}
+
+	endpoints, err := project.BundleEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []contracts.ProjectBundleEndpoint{
+		{Method: "GET", Path: "/users", ResponseBody: `{"users":[{"id":1}]}`, ResponseStatus: 200, ResponseHeaders: "{}"},
+		{Method: "POST", Path: "/users", ResponseBody: "created", ResponseStatus: 201, ResponseHeaders: `{"Location":"/users/1"}`},
+	}
+	if !slices.Equal(endpoints, want) {
+		t.Errorf("BundleEndpoints() = %+v, want %+v", endpoints, want)
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "b.json", `{"projects": [{"code": "beta"}, {"code": "gamma"}]}`)
+	writeFile(t, dir, "a.yml", "code: alpha\n")
+	writeFile(t, dir, "nested/c.yaml", "code: delta\n")
+	writeFile(t, dir, ".hidden/d.yaml", "code: hidden\n")
+	writeFile(t, dir, "notes.txt", "not a mock file")
+
+	projects, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var codes []string
+	for _, project := range projects {
+		codes = append(codes, project.Code)
+	}
+	if want := []string{"alpha", "beta", "gamma", "delta"}; !slices.Equal(codes, want) {
+		t.Errorf("Load() codes = %v, want %v", codes, want)
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "missing code", content: "name: Shop\n", want: "project code is required"},
+		{name: "unknown field", content: "code: shop\ncolour: blue\n", want: "unknown field"},
+		{name: "relative path", content: "code: shop\nendpoints:\n  - path: users\n", want: "path must start with /"},
+		{name: "invalid status", content: "code: shop\nendpoints:\n  - path: /users\n    status: 42\n", want: "invalid status 42"},
+		{name: "list and project", content: "code: shop\nprojects:\n  - code: other\n", want: "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "mock.yaml", tt.content)
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.want)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), path) {
+				t.Errorf("Load() error = %v, want it to name the file", err)
+			}
+		})
+	}
+}
+
+func TestLoadEndpoints(t *testing.T) {
+	dir := t.TempDir()
+
+	single := writeFile(t, dir, "single.yaml", "path: /health\nbody: ok\n")
+	endpoints, err := LoadEndpoints(single)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []contracts.ProjectBundleEndpoint{{Method: "GET", Path: "/health", ResponseBody: "ok", ResponseStatus: 200, ResponseHeaders: "{}"}}
+	if !slices.Equal(endpoints, want) {
+		t.Errorf("LoadEndpoints(single) = %+v, want %+v", endpoints, want)
+	}
+
+	list := writeFile(t, dir, "list.json", `{"endpoints": [{"method": "delete", "path": "/users/1", "status": 204}, {"path": "/users"}]}`)
+	endpoints, err = LoadEndpoints(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []contracts.ProjectBundleEndpoint{
+		{Method: "DELETE", Path: "/users/1", ResponseBody: "{}", ResponseStatus: 204, ResponseHeaders: "{}"},
+		{Method: "GET", Path: "/users", ResponseBody: "{}", ResponseStatus: 200, ResponseHeaders: "{}"},
+	}
+	if !slices.Equal(endpoints, want) {
+		t.Errorf("LoadEndpoints(list) = %+v, want %+v", endpoints, want)
+	}
+
+	both := writeFile(t, dir, "both.yaml", "path: /health\nendpoints:\n  - path: /users\n")
+	if _, err := LoadEndpoints(both); err == nil {
+		t.Error("LoadEndpoints() with a list and a single endpoint succeeded, want an error")
+	}
+}
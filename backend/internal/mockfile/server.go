@@ -0,0 +1,200 @@
+package mockfile
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/handler"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository/memory"
+	"github.com/crudboxin/crudbox/internal/service"
+)
+
+// ownerEmail identifies the user that owns everything loaded from mock files.
+// Nobody can log in as it because the standalone server has no auth routes.
+const ownerEmail = "mocks@crudbox.local"
+
+// Server serves the mocks declared in a file or directory and swaps in a fresh
+// router whenever Reload succeeds. Requests in flight finish on the router
+// they started on.
+type Server struct {
+	path       string
+	baseDomain string
+	router     atomic.Pointer[gin.Engine]
+	loaded     string
+}
+
+// NewServer loads the mocks at path. mockBaseDomain enables subdomain routing
+// the same way MOCK_BASE_DOMAIN does for the API server.
+func NewServer(path, mockBaseDomain string) (*Server, error) {
+	s := &Server{path: path, baseDomain: mockBaseDomain}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.Load().ServeHTTP(w, r)
+}
+
+// Reload loads the mock files again and returns the number of projects now
+// being served. On error the previously loaded mocks keep being served.
+func (s *Server) Reload() (int, error) {
+	fingerprint, err := fingerprint(s.path)
+	if err != nil {
+		return 0, err
+	}
+
+	projects, err := Load(s.path)
+	if err != nil {
+		return 0, err
+	}
+
+	router, err := build(projects, s.baseDomain)
+	if err != nil {
+		return 0, err
+	}
+
+	s.router.Store(router)
+	s.loaded = fingerprint
+	return len(projects), nil
+}
+
+// Watch checks the mock files for changes every interval and reloads them when
+// a file was added, removed or modified. It runs for the lifetime of the server.
+func (s *Server) Watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failed := ""
+	for range ticker.C {
+		current, err := fingerprint(s.path)
+		if err != nil || current == s.loaded || current == failed {
+			continue
+		}
+
+		count, err := s.Reload()
+		if err != nil {
+			// Report a broken file once rather than on every tick until it is fixed
+			failed = current
+			log.Printf("Failed to reload mocks, still serving the previous ones: %v", err)
+			continue
+		}
+		failed = ""
+		log.Printf("Reloaded %d projects from %s", count, s.path)
+	}
+}
+
+// fingerprint summarises the name, size and modification time of every mock
+// file at path, so any change to the set of files or their content alters it.
+func fingerprint(path string) (string, error) {
+	files, err := findFiles(path)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d\n", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// build creates the projects in a fresh in-memory store and returns a router
+// serving their mocks.
+func build(projects []Project, mockBaseDomain string) (*gin.Engine, error) {
+	repos := memory.NewRepositories()
+	// There is no API to read a request log from, so none is kept
+	repos.RequestLog = nil
+	// Hostnames come from the mock files, which whoever runs the server controls
+	hosts := service.NewMockHosts(mockBaseDomain)
+	hosts.CustomHostnames = true
+	services := service.NewServices(repos, service.AuthConfig{}, hosts, nil, nil)
+
+	now := time.Now()
+	owner := &models.User{
+		Email:           ownerEmail,
+		EmailVerifiedAt: &now,
+		Base:            models.Base{CreatedAt: &now, UpdatedAt: &now},
+	}
+	if err := repos.User.Create(owner); err != nil {
+		return nil, err
+	}
+
+	org, err := services.Organisation.CreateOrganisation(&contracts.CreateOrganisationRequest{Name: "Mocks"}, owner.ID, contracts.ClientInfo{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		if err := createProject(services, org.UUID, owner.ID, project); err != nil {
+			return nil, fmt.Errorf("%s: project %s: %w", project.source, project.Code, err)
+		}
+	}
+
+	endpointHandler := handler.NewEndpointHandler(services.Endpoint, services.Project, hosts)
+	return handler.SetupMockRoutes(endpointHandler)
+}
+
+func createProject(services *service.Services, orgUUID string, userID int, project Project) error {
+	client := contracts.ClientInfo{}
+
+	created, err := services.Project.CreateProject(&contracts.CreateProjectRequest{
+		Name:             project.Name,
+		Code:             project.Code,
+		OrganisationUUID: orgUUID,
+	}, userID, client)
+	if err != nil {
+		return err
+	}
+
+	if project.Hostname != "" {
+		hostname := project.Hostname
+		if _, err := services.Project.UpdateProject(created.UUID, &contracts.UpdateProjectRequest{Hostname: &hostname}, userID, client); err != nil {
+			return err
+		}
+	}
+
+	for _, environment := range project.Environments {
+		_, err := services.Project.CreateEnvironment(created.UUID, &contracts.CreateProjectEnvironmentRequest{
+			Name:      environment.Name,
+			Variables: environment.Variables,
+			IsDefault: environment.Default,
+		}, userID, client)
+		if err != nil {
+			return fmt.Errorf("environment %s: %w", environment.Name, err)
+		}
+	}
+
+	endpoints, err := project.BundleEndpoints()
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints {
+		_, err := services.Endpoint.CreateEndpoint(&contracts.CreateEndpointRequest{
+			Method:          endpoint.Method,
+			Path:            endpoint.Path,
+			ResponseBody:    endpoint.ResponseBody,
+			ResponseStatus:  endpoint.ResponseStatus,
+			ResponseHeaders: endpoint.ResponseHeaders,
+		}, created.UUID, userID, client)
+		if err != nil {
+			return fmt.Errorf("endpoint %s %s: %w", endpoint.Method, endpoint.Path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type APIToken struct {
+	UUID        string     `db:"uuid"`
+	ID          int        `db:"id"`
+	UserID      int        `db:"user_id"`
+	Name        string     `db:"name"`
+	TokenPrefix string     `db:"token_prefix"`
+	TokenHash   string     `db:"token_hash"`
+	Scopes      string     `db:"scopes"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+	LastUsedAt  *time.Time `db:"last_used_at"`
+	Base
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AuditLog is one change to an organisation, project or endpoint. Changes holds
+// a JSON object mapping each changed field to its before and after values.
+type AuditLog struct {
+	UUID           string         `db:"uuid"`
+	ID             int            `db:"id"`
+	OrganisationID int            `db:"organisation_id"`
+	ProjectUUID    sql.NullString `db:"project_uuid"`
+	ActorUUID      string         `db:"actor_uuid"`
+	ActorEmail     string         `db:"actor_email"`
+	Action         string         `db:"action"`
+	EntityType     string         `db:"entity_type"`
+	EntityUUID     string         `db:"entity_uuid"`
+	Changes        string         `db:"changes"`
+	IPAddress      sql.NullString `db:"ip_address"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
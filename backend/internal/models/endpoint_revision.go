@@ -0,0 +1,23 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// EndpointRevision is a full snapshot of an endpoint as saved by one change.
+// RestoredFrom is set when the revision was created by restoring an older one.
+type EndpointRevision struct {
+	UUID            string         `db:"uuid"`
+	ID              int            `db:"id"`
+	EndpointID      int            `db:"endpoint_id"`
+	Revision        int            `db:"revision"`
+	Method          string         `db:"method"`
+	Path            string         `db:"path"`
+	ResponseBody    string         `db:"response_body"`
+	ResponseStatus  int            `db:"response_status"`
+	ResponseHeaders string         `db:"response_headers"`
+	RestoredFrom    sql.NullInt64  `db:"restored_from"`
+	CreatedAt       *time.Time     `db:"created_at"`
+	CreatedBy       sql.NullString `db:"created_by"`
+}
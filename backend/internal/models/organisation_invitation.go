@@ -0,0 +1,20 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OrganisationInvitation struct {
+	UUID           string        `db:"uuid"`
+	ID             int           `db:"id"`
+	OrganisationID int           `db:"organisation_id"`
+	Email          string        `db:"email"`
+	Role           string        `db:"role"`
+	TokenHash      string        `db:"token_hash"`
+	InvitedBy      int           `db:"invited_by"`
+	ExpiresAt      time.Time     `db:"expires_at"`
+	AcceptedAt     *time.Time    `db:"accepted_at"`
+	AcceptedBy     sql.NullInt64 `db:"accepted_by"`
+	Base
+}
@@ -1,11 +1,14 @@
 package models
 
+import "database/sql"
+
 type Project struct {
-	UUID           string `db:"uuid"`
-	ID             int    `db:"id"`
-	Name           string `db:"name"`
-	Code           string `db:"code"`
-	UserID         int    `db:"user_id"`
-	OrganisationID int    `db:"organisation_id"`
+	UUID           string         `db:"uuid"`
+	ID             int            `db:"id"`
+	Name           string         `db:"name"`
+	Code           string         `db:"code"`
+	Hostname       sql.NullString `db:"hostname"`
+	UserID         int            `db:"user_id"`
+	OrganisationID int            `db:"organisation_id"`
 	Base
 }
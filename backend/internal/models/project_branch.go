@@ -0,0 +1,29 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProjectBranch struct {
+	UUID            string         `db:"uuid"`
+	ID              int            `db:"id"`
+	ProjectID       int            `db:"project_id"`
+	ParentProjectID int            `db:"parent_project_id"`
+	CreatedAt       *time.Time     `db:"created_at"`
+	CreatedBy       sql.NullString `db:"created_by"`
+	MergedAt        *time.Time     `db:"merged_at"`
+	MergedBy        sql.NullString `db:"merged_by"`
+}
+
+// ProjectBranchEndpoint is an endpoint of the parent project as it was at the
+// branch point.
+type ProjectBranchEndpoint struct {
+	ID              int    `db:"id"`
+	BranchID        int    `db:"branch_id"`
+	Method          string `db:"method"`
+	Path            string `db:"path"`
+	ResponseBody    string `db:"response_body"`
+	ResponseStatus  int    `db:"response_status"`
+	ResponseHeaders string `db:"response_headers"`
+}
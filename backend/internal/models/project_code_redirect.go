@@ -0,0 +1,9 @@
+package models
+
+type ProjectCodeRedirect struct {
+	UUID      string `db:"uuid"`
+	ID        int    `db:"id"`
+	Code      string `db:"code"`
+	ProjectID int    `db:"project_id"`
+	Base
+}
@@ -0,0 +1,12 @@
+package models
+
+type ProjectEnvironment struct {
+	UUID      string `db:"uuid"`
+	ID        int    `db:"id"`
+	ProjectID int    `db:"project_id"`
+	Name      string `db:"name"`
+	// Variables is a JSON object of variable names to values.
+	Variables string `db:"variables"`
+	IsDefault bool   `db:"is_default"`
+	Base
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ProjectSync records the latest sync of a project's endpoints from declared
+// definitions.
+type ProjectSync struct {
+	ID        int            `db:"id"`
+	ProjectID int            `db:"project_id"`
+	SyncedAt  time.Time      `db:"synced_at"`
+	SyncedBy  sql.NullString `db:"synced_by"`
+}
+
+// ProjectSyncEndpoint is an endpoint of the project as the latest sync left it.
+type ProjectSyncEndpoint struct {
+	ID              int    `db:"id"`
+	SyncID          int    `db:"sync_id"`
+	Method          string `db:"method"`
+	Path            string `db:"path"`
+	ResponseBody    string `db:"response_body"`
+	ResponseStatus  int    `db:"response_status"`
+	ResponseHeaders string `db:"response_headers"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "database/sql"
+
+type ProjectVersion struct {
+	UUID        string         `db:"uuid"`
+	ID          int            `db:"id"`
+	ProjectID   int            `db:"project_id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	// EndpointCount is computed when versions are read and not stored.
+	EndpointCount int `db:"endpoint_count"`
+	Base
+}
+
+// ProjectVersionEndpoint is an endpoint as it was when its version was created.
+type ProjectVersionEndpoint struct {
+	ID              int    `db:"id"`
+	VersionID       int    `db:"version_id"`
+	Method          string `db:"method"`
+	Path            string `db:"path"`
+	ResponseBody    string `db:"response_body"`
+	ResponseStatus  int    `db:"response_status"`
+	ResponseHeaders string `db:"response_headers"`
+}
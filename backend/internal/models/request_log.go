@@ -0,0 +1,25 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RequestLog is one request served by a project's mocks. Matched is set when an
+// endpoint answered it; EndpointUUID names that endpoint unless it was served
+// from a version, whose endpoints are snapshots without a UUID.
+type RequestLog struct {
+	ID             int            `db:"id"`
+	ProjectID      int            `db:"project_id"`
+	Method         string         `db:"method"`
+	Path           string         `db:"path"`
+	Query          string         `db:"query"`
+	Version        sql.NullString `db:"version"`
+	Environment    sql.NullString `db:"environment"`
+	Matched        bool           `db:"matched"`
+	EndpointUUID   sql.NullString `db:"endpoint_uuid"`
+	ResponseStatus int            `db:"response_status"`
+	IPAddress      sql.NullString `db:"ip_address"`
+	UserAgent      sql.NullString `db:"user_agent"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
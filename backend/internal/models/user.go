@@ -1,9 +1,12 @@
 package models
 
+import "time"
+
 type User struct {
-	UUID     string `db:"uuid"`
-	ID       int    `db:"id"`
-	Email    string `db:"email"`
-	Password string `db:"password"`
+	UUID            string     `db:"uuid"`
+	ID              int        `db:"id"`
+	Email           string     `db:"email"`
+	Password        string     `db:"password"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
 	Base
 }
@@ -0,0 +1,13 @@
+package models
+
+import "database/sql"
+
+type UserIdentity struct {
+	UUID    string         `db:"uuid"`
+	ID      int            `db:"id"`
+	UserID  int            `db:"user_id"`
+	Issuer  string         `db:"issuer"`
+	Subject string         `db:"subject"`
+	Email   sql.NullString `db:"email"`
+	Base
+}
@@ -5,5 +5,6 @@ type UserOrganisationMapping struct {
 	ID             int    `db:"id"`
 	UserID         int    `db:"user_id"`
 	OrganisationID int    `db:"organisation_id"`
+	Role           string `db:"role"`
 	Base
 }
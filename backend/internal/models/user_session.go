@@ -0,0 +1,19 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type UserSession struct {
+	UUID                     string         `db:"uuid"`
+	ID                       int            `db:"id"`
+	UserID                   int            `db:"user_id"`
+	RefreshTokenHash         string         `db:"refresh_token_hash"`
+	PreviousRefreshTokenHash sql.NullString `db:"previous_refresh_token_hash"`
+	ExpiresAt                time.Time      `db:"expires_at"`
+	LastUsedAt               *time.Time     `db:"last_used_at"`
+	IPAddress                sql.NullString `db:"ip_address"`
+	UserAgent                sql.NullString `db:"user_agent"`
+	Base
+}
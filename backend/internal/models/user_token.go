@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type UserToken struct {
+	UUID      string     `db:"uuid"`
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	Purpose   string     `db:"purpose"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	Base
+}
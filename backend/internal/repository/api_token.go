@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type apiTokenRepository struct {
+	db DBTX
+}
+
+func NewAPITokenRepository(db DBTX) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(token *models.APIToken) error {
+	return r.db.QueryRowx(
+		"INSERT INTO api_tokens (user_id, name, token_prefix, token_hash, scopes, expires_at, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9) RETURNING id, uuid",
+		token.UserID, token.Name, token.TokenPrefix, token.TokenHash, token.Scopes, token.ExpiresAt, token.CreatedAt, token.UpdatedAt, token.CreatedBy.String,
+	).StructScan(token)
+}
+
+func (r *apiTokenRepository) GetByTokenHash(tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.db.Get(
+		&token,
+		"SELECT id, uuid, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM api_tokens WHERE token_hash = $1 AND deleted_at IS NULL",
+		tokenHash,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *apiTokenRepository) GetByUUIDForUser(uuid string, userID int) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.db.Get(
+		&token,
+		"SELECT id, uuid, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM api_tokens WHERE uuid = $1 AND user_id = $2 AND deleted_at IS NULL",
+		uuid, userID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *apiTokenRepository) GetByUserID(userID int) ([]*models.APIToken, error) {
+	var tokens []*models.APIToken
+	err := r.db.Select(
+		&tokens,
+		"SELECT id, uuid, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM api_tokens WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC",
+		userID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(id int, lastUsedAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE api_tokens SET last_used_at = $1 WHERE id = $2",
+		lastUsedAt, id,
+	)
+	return err
+}
+
+func (r *apiTokenRepository) Revoke(id int, revokedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE api_tokens SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE id = $3",
+		now, revokedBy, id,
+	)
+	return err
+}
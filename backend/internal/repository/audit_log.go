@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type auditLogRepository struct {
+	db DBTX
+}
+
+func NewAuditLogRepository(db DBTX) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(entry *models.AuditLog) error {
+	return r.db.QueryRowx(
+		"INSERT INTO audit_logs (organisation_id, project_uuid, actor_uuid, actor_email, action, entity_type, entity_uuid, changes, ip_address, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, uuid",
+		entry.OrganisationID, entry.ProjectUUID, entry.ActorUUID, entry.ActorEmail, entry.Action, entry.EntityType, entry.EntityUUID, entry.Changes, entry.IPAddress, entry.CreatedAt,
+	).StructScan(entry)
+}
+
+// List returns the entries of an organisation matching filter, newest first.
+func (r *auditLogRepository) List(filter AuditLogFilter) ([]*models.AuditLog, error) {
+	conditions := []string{"organisation_id = $1"}
+	args := []any{filter.OrganisationID}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ProjectUUID != "" {
+		add("project_uuid = $%d", filter.ProjectUUID)
+	}
+	if filter.EntityType != "" {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityUUID != "" {
+		add("entity_uuid = $%d", filter.EntityUUID)
+	}
+	if filter.ActorUUID != "" {
+		add("actor_uuid = $%d", filter.ActorUUID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.Since != nil {
+		add("created_at >= $%d", *filter.Since)
+	}
+	if filter.Until != nil {
+		add("created_at < $%d", *filter.Until)
+	}
+	if filter.BeforeID != 0 {
+		add("id < $%d", filter.BeforeID)
+	}
+	args = append(args, filter.Limit)
+
+	entries := []*models.AuditLog{}
+	err := r.db.Select(
+		&entries,
+		fmt.Sprintf(
+			"SELECT id, uuid, organisation_id, project_uuid, actor_uuid, actor_email, action, entity_type, entity_uuid, changes, ip_address, created_at FROM audit_logs WHERE %s ORDER BY id DESC LIMIT $%d",
+			strings.Join(conditions, " AND "), len(args),
+		),
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
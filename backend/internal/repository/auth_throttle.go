@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type authThrottleRepository struct {
+	db DBTX
+}
+
+func NewAuthThrottleRepository(db DBTX) AuthThrottleRepository {
+	return &authThrottleRepository{db: db}
+}
+
+func (r *authThrottleRepository) Get(key string) (*models.AuthThrottle, error) {
+	var throttle models.AuthThrottle
+	err := r.db.Get(
+		&throttle,
+		"SELECT key, failures, last_failure_at, locked_until FROM auth_throttles WHERE key = $1",
+		key,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+// RecordFailure atomically counts a failure for key. Counting starts over when
+// the previous failure and any lockout are older than windowStart.
+func (r *authThrottleRepository) RecordFailure(key string, at, windowStart time.Time) (*models.AuthThrottle, error) {
+	var throttle models.AuthThrottle
+	err := r.db.Get(
+		&throttle,
+		`INSERT INTO auth_throttles (key, failures, last_failure_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN auth_throttles.last_failure_at < $3 AND (auth_throttles.locked_until IS NULL OR auth_throttles.locked_until < $3) THEN 1
+				ELSE auth_throttles.failures + 1
+			END,
+			last_failure_at = $2
+		RETURNING key, failures, last_failure_at, locked_until`,
+		key, at, windowStart,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+func (r *authThrottleRepository) Lock(key string, until time.Time) error {
+	_, err := r.db.Exec("UPDATE auth_throttles SET locked_until = $1 WHERE key = $2", until, key)
+	return err
+}
+
+func (r *authThrottleRepository) Reset(key string) error {
+	_, err := r.db.Exec("DELETE FROM auth_throttles WHERE key = $1", key)
+	return err
+}
+
+// DeleteStale removes counters whose last failure and lockout ended before cutoff.
+func (r *authThrottleRepository) DeleteStale(cutoff time.Time) error {
+	_, err := r.db.Exec(
+		"DELETE FROM auth_throttles WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $1)",
+		cutoff,
+	)
+	return err
+}
@@ -3,16 +3,14 @@ package repository
 import (
 	"time"
 
-	"github.com/jmoiron/sqlx"
-
 	"github.com/crudboxin/crudbox/internal/models"
 )
 
 type endpointRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewEndpointRepository(db *sqlx.DB) EndpointRepository {
+func NewEndpointRepository(db DBTX) EndpointRepository {
 	return &endpointRepository{db: db}
 }
 
@@ -91,15 +89,30 @@ func (r *endpointRepository) GetByUUID(uuid string) (*models.Endpoint, error) {
 	return &endpoint, nil
 }
 
-func (r *endpointRepository) GetByUUIDForUser(uuid string, userID int) (*models.Endpoint, error) {
+// DeleteByProjectID deletes the project's remaining endpoints. Passing the
+// deletion time of the project lets them be restored together with it.
+func (r *endpointRepository) DeleteByProjectID(projectID int, deletedBy string, deletedAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE endpoints SET deleted_at = $1, deleted_by = $2, updated_by = $2, updated_at = $1 WHERE project_id = $3 AND deleted_at IS NULL",
+		deletedAt, deletedBy, projectID,
+	)
+	return err
+}
+
+func (r *endpointRepository) DeleteByOrganisationID(organisationID int, deletedBy string, deletedAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE endpoints SET deleted_at = $1, deleted_by = $2, updated_by = $2, updated_at = $1 WHERE project_id IN (SELECT id FROM projects WHERE organisation_id = $3) AND deleted_at IS NULL",
+		deletedAt, deletedBy, organisationID,
+	)
+	return err
+}
+
+func (r *endpointRepository) GetDeletedByUUID(uuid string) (*models.Endpoint, error) {
 	var endpoint models.Endpoint
 	err := r.db.Get(
 		&endpoint,
-		`SELECT e.id, e.uuid, e.method, e.path, e.response_body, e.response_status, e.response_headers, e.project_id, e.created_at, e.updated_at, e.created_by, e.updated_by, e.deleted_at, e.deleted_by
-         FROM endpoints e
-         JOIN projects p ON e.project_id = p.id
-         WHERE e.uuid = $1 AND p.user_id = $2 AND e.deleted_at IS NULL AND p.deleted_at IS NULL`,
-		uuid, userID,
+		"SELECT id, uuid, method, path, response_body, response_status, response_headers, project_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM endpoints WHERE uuid = $1 AND deleted_at IS NOT NULL",
+		uuid,
 	)
 
 	if err != nil {
@@ -109,11 +122,68 @@ func (r *endpointRepository) GetByUUIDForUser(uuid string, userID int) (*models.
 	return &endpoint, nil
 }
 
-func (r *endpointRepository) DeleteByProjectID(projectID int, userID int) error {
-	now := time.Now()
+// GetDeletedByProjectID returns the deleted endpoints of a project, most
+// recently deleted first.
+func (r *endpointRepository) GetDeletedByProjectID(projectID int) ([]*models.Endpoint, error) {
+	endpoints := []*models.Endpoint{}
+	err := r.db.Select(
+		&endpoints,
+		"SELECT id, uuid, method, path, response_body, response_status, response_headers, project_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM endpoints WHERE project_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC",
+		projectID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// GetDeletedByOrganisationID returns the deleted endpoints of the organisation's
+// live projects, most recently deleted first. Endpoints of deleted projects are
+// only restored together with their project.
+func (r *endpointRepository) GetDeletedByOrganisationID(organisationID int) ([]*models.Endpoint, error) {
+	endpoints := []*models.Endpoint{}
+	err := r.db.Select(
+		&endpoints,
+		"SELECT e.id, e.uuid, e.method, e.path, e.response_body, e.response_status, e.response_headers, e.project_id, e.created_at, e.updated_at, e.created_by, e.updated_by, e.deleted_at, e.deleted_by FROM endpoints e JOIN projects p ON p.id = e.project_id WHERE p.organisation_id = $1 AND p.deleted_at IS NULL AND e.deleted_at IS NOT NULL ORDER BY e.deleted_at DESC",
+		organisationID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+func (r *endpointRepository) Restore(id int, restoredBy string, restoredAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE endpoints SET deleted_at = NULL, deleted_by = NULL, updated_by = $1, updated_at = $2 WHERE id = $3",
+		restoredBy, restoredAt, id,
+	)
+	return err
+}
+
+// RestoreByProjectID restores the project's endpoints that were deleted at
+// deletedAt, i.e. together with the project.
+func (r *endpointRepository) RestoreByProjectID(projectID int, deletedAt time.Time, restoredBy string, restoredAt time.Time) error {
 	_, err := r.db.Exec(
-		"UPDATE endpoints SET deleted_at = $1, deleted_by = $2, updated_by = $2, updated_at = $1 WHERE project_id = $3",
-		now, userID, projectID,
+		"UPDATE endpoints SET deleted_at = NULL, deleted_by = NULL, updated_by = $1, updated_at = $2 WHERE project_id = $3 AND deleted_at = $4",
+		restoredBy, restoredAt, projectID, deletedAt,
 	)
 	return err
 }
+
+// PurgeDeletedBefore permanently removes endpoints deleted before cutoff along
+// with every endpoint of projects deleted before cutoff.
+func (r *endpointRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(
+		"DELETE FROM endpoints WHERE deleted_at < $1 OR project_id IN (SELECT id FROM projects WHERE deleted_at < $1)",
+		cutoff,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type endpointRevisionRepository struct {
+	db DBTX
+}
+
+func NewEndpointRevisionRepository(db DBTX) EndpointRevisionRepository {
+	return &endpointRevisionRepository{db: db}
+}
+
+// Create appends revision to the endpoint's history, numbering it after the
+// newest existing revision.
+func (r *endpointRevisionRepository) Create(revision *models.EndpointRevision) error {
+	return r.db.QueryRowx(
+		"INSERT INTO endpoint_revisions (endpoint_id, revision, method, path, response_body, response_status, response_headers, restored_from, created_at, created_by) VALUES ($1, (SELECT COALESCE(MAX(revision), 0) + 1 FROM endpoint_revisions WHERE endpoint_id = $1), $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, uuid, revision",
+		revision.EndpointID, revision.Method, revision.Path, revision.ResponseBody, revision.ResponseStatus, revision.ResponseHeaders, revision.RestoredFrom, revision.CreatedAt, revision.CreatedBy,
+	).StructScan(revision)
+}
+
+// GetByEndpointID returns the endpoint's revisions, newest first.
+func (r *endpointRevisionRepository) GetByEndpointID(endpointID int) ([]*models.EndpointRevision, error) {
+	revisions := []*models.EndpointRevision{}
+	err := r.db.Select(
+		&revisions,
+		"SELECT id, uuid, endpoint_id, revision, method, path, response_body, response_status, response_headers, restored_from, created_at, created_by FROM endpoint_revisions WHERE endpoint_id = $1 ORDER BY revision DESC",
+		endpointID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *endpointRevisionRepository) GetByEndpointIDAndRevision(endpointID, revision int) (*models.EndpointRevision, error) {
+	var endpointRevision models.EndpointRevision
+	err := r.db.Get(
+		&endpointRevision,
+		"SELECT id, uuid, endpoint_id, revision, method, path, response_body, response_status, response_headers, restored_from, created_at, created_by FROM endpoint_revisions WHERE endpoint_id = $1 AND revision = $2",
+		endpointID, revision,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &endpointRevision, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/jmoiron/sqlx"
 
 	"github.com/crudboxin/crudbox/internal/models"
@@ -11,10 +13,14 @@ type UserRepository interface {
 	GetByEmail(email string) (*models.User, error)
 	GetByID(id int) (*models.User, error)
 	GetByUUID(uuid string) (*models.User, error)
+	UpdatePassword(id int, password, updatedBy string) error
+	MarkEmailVerified(id int, verifiedAt time.Time) error
 }
 
 type OrganisationRepository interface {
 	Create(org *models.Organisation) error
+	Update(org *models.Organisation) error
+	DeleteByID(id int, deletedBy string) error
 	GetByID(id int) (*models.Organisation, error)
 	GetByUUID(uuid string) (*models.Organisation, error)
 }
@@ -22,11 +28,64 @@ type OrganisationRepository interface {
 type ProjectRepository interface {
 	GetByOrganisationID(organisationID int) ([]*models.Project, error)
 	Create(project *models.Project) error
+	Update(project *models.Project) error
 	GetByID(id int) (*models.Project, error)
 	GetByCode(code string) (*models.Project, error)
+	GetByCodeIgnoringCase(code string) (*models.Project, error)
+	GetByHostname(hostname string) (*models.Project, error)
 	GetByUUID(uuid string) (*models.Project, error)
-	GetByUUIDForUser(uuid string, userID int) (*models.Project, error)
-	DeleteByUUID(uuid string, userID int) error
+	DeleteByUUID(uuid string, deletedBy string, deletedAt time.Time) error
+	DeleteByOrganisationID(organisationID int, deletedBy string, deletedAt time.Time) error
+	CodeExists(code string) (bool, error)
+	GetDeletedByUUID(uuid string) (*models.Project, error)
+	GetDeletedByOrganisationID(organisationID int) ([]*models.Project, error)
+	Restore(id int, restoredBy string, restoredAt time.Time) error
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+}
+
+type ProjectEnvironmentRepository interface {
+	Create(environment *models.ProjectEnvironment) error
+	Update(environment *models.ProjectEnvironment) error
+	ClearDefault(projectID int) error
+	GetByProjectID(projectID int) ([]*models.ProjectEnvironment, error)
+	GetByProjectIDAndName(projectID int, name string) (*models.ProjectEnvironment, error)
+	GetDefault(projectID int) (*models.ProjectEnvironment, error)
+	Delete(id int, deletedBy string) error
+}
+
+type ProjectBranchRepository interface {
+	Create(branch *models.ProjectBranch) error
+	CreateBaseEndpoint(endpoint *models.ProjectBranchEndpoint) error
+	DeleteBaseEndpoints(branchID int) error
+	GetByProjectID(projectID int) (*models.ProjectBranch, error)
+	GetByParentProjectID(parentProjectID int) ([]*models.ProjectBranch, error)
+	GetBaseEndpoints(branchID int) ([]*models.ProjectBranchEndpoint, error)
+	MarkMerged(id int, mergedBy string, mergedAt time.Time) error
+}
+
+type ProjectSyncRepository interface {
+	Save(sync *models.ProjectSync) error
+	CreateEndpoint(endpoint *models.ProjectSyncEndpoint) error
+	DeleteEndpoints(syncID int) error
+	GetByProjectID(projectID int) (*models.ProjectSync, error)
+	GetEndpoints(syncID int) ([]*models.ProjectSyncEndpoint, error)
+}
+
+type ProjectVersionRepository interface {
+	Create(version *models.ProjectVersion) error
+	CreateEndpoint(endpoint *models.ProjectVersionEndpoint) error
+	GetByProjectID(projectID int) ([]*models.ProjectVersion, error)
+	GetByProjectIDAndName(projectID int, name string) (*models.ProjectVersion, error)
+	GetEndpoints(versionID int) ([]*models.ProjectVersionEndpoint, error)
+	GetEndpoint(versionID int, path, method string) (*models.ProjectVersionEndpoint, error)
+	Delete(id int, deletedBy string) error
+}
+
+type ProjectCodeRedirectRepository interface {
+	Create(redirect *models.ProjectCodeRedirect) error
+	GetByCode(code string) (*models.ProjectCodeRedirect, error)
+	DeleteByCode(code string) error
+	DeleteByProjectID(projectID int) error
 }
 
 type EndpointRepository interface {
@@ -36,30 +95,180 @@ type EndpointRepository interface {
 	GetByID(id int) (*models.Endpoint, error)
 	GetByProjectIDAndPath(projectID int, path, method string) (*models.Endpoint, error)
 	GetByUUID(uuid string) (*models.Endpoint, error)
-	GetByUUIDForUser(uuid string, userID int) (*models.Endpoint, error)
-	DeleteByProjectID(projectID int, userID int) error
+	DeleteByProjectID(projectID int, deletedBy string, deletedAt time.Time) error
+	DeleteByOrganisationID(organisationID int, deletedBy string, deletedAt time.Time) error
+	GetDeletedByUUID(uuid string) (*models.Endpoint, error)
+	GetDeletedByProjectID(projectID int) ([]*models.Endpoint, error)
+	GetDeletedByOrganisationID(organisationID int) ([]*models.Endpoint, error)
+	Restore(id int, restoredBy string, restoredAt time.Time) error
+	RestoreByProjectID(projectID int, deletedAt time.Time, restoredBy string, restoredAt time.Time) error
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+}
+
+// EndpointRevisionRepository only appends; revisions are removed together with
+// their endpoint.
+type EndpointRevisionRepository interface {
+	Create(revision *models.EndpointRevision) error
+	GetByEndpointID(endpointID int) ([]*models.EndpointRevision, error)
+	GetByEndpointIDAndRevision(endpointID, revision int) (*models.EndpointRevision, error)
 }
 
 type UserOrganisationMappingRepository interface {
 	Create(mapping *models.UserOrganisationMapping) error
 	GetByUserID(userID int) ([]*models.UserOrganisationMapping, error)
+	GetByOrganisationID(orgID int) ([]*models.UserOrganisationMapping, error)
+	GetByUserIDAndOrganisationID(userID, orgID int) (*models.UserOrganisationMapping, error)
 	CheckUserInOrganisation(userID, orgID int) (bool, error)
+	UpdateRole(userID, orgID int, role string, updatedBy string) error
+	Delete(userID, orgID int, deletedBy string) error
+	DeleteByOrganisationID(orgID int, deletedBy string) error
+}
+
+type OrganisationInvitationRepository interface {
+	Create(invitation *models.OrganisationInvitation) error
+	GetByUUID(uuid string) (*models.OrganisationInvitation, error)
+	GetByTokenHash(tokenHash string) (*models.OrganisationInvitation, error)
+	GetPendingByOrganisationID(organisationID int) ([]*models.OrganisationInvitation, error)
+	MarkAccepted(id int, userID int, acceptedBy string) error
+	RevokePendingByEmail(organisationID int, email string, revokedBy string) error
+	Revoke(id int, revokedBy string) error
+}
+
+type APITokenRepository interface {
+	Create(token *models.APIToken) error
+	GetByTokenHash(tokenHash string) (*models.APIToken, error)
+	GetByUUIDForUser(uuid string, userID int) (*models.APIToken, error)
+	GetByUserID(userID int) ([]*models.APIToken, error)
+	UpdateLastUsed(id int, lastUsedAt time.Time) error
+	Revoke(id int, revokedBy string) error
+}
+
+type UserSessionRepository interface {
+	Create(session *models.UserSession) error
+	GetByUUID(uuid string) (*models.UserSession, error)
+	GetByRefreshTokenHash(tokenHash string) (*models.UserSession, error)
+	GetByPreviousRefreshTokenHash(tokenHash string) (*models.UserSession, error)
+	Rotate(id int, oldHash, refreshTokenHash string, expiresAt time.Time) error
+	Revoke(id int, revokedBy string) error
+	RevokeByUserID(userID int, revokedBy string) error
+}
+
+type UserTokenRepository interface {
+	Create(token *models.UserToken) error
+	GetByTokenHash(tokenHash, purpose string) (*models.UserToken, error)
+	MarkUsed(id int, usedAt time.Time) error
+	InvalidateByUserID(userID int, purpose string) error
+}
+
+type UserIdentityRepository interface {
+	Create(identity *models.UserIdentity) error
+	GetByIssuerAndSubject(issuer, subject string) (*models.UserIdentity, error)
+}
+
+type AuthThrottleRepository interface {
+	Get(key string) (*models.AuthThrottle, error)
+	RecordFailure(key string, at, windowStart time.Time) (*models.AuthThrottle, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+	DeleteStale(cutoff time.Time) error
+}
+
+// AuditLogFilter narrows an organisation's audit log. Zero values match
+// everything; BeforeID pages backwards through entries ordered by id.
+type AuditLogFilter struct {
+	OrganisationID int
+	ProjectUUID    string
+	EntityType     string
+	EntityUUID     string
+	ActorUUID      string
+	Action         string
+	Since          *time.Time
+	Until          *time.Time
+	BeforeID       int
+	Limit          int
+}
+
+// AuditLogRepository only appends; entries are never changed once written.
+type AuditLogRepository interface {
+	Create(entry *models.AuditLog) error
+	List(filter AuditLogFilter) ([]*models.AuditLog, error)
+}
+
+// RequestLogFilter selects part of a project's request log. Without AfterID the
+// newest Limit entries are returned; with it, the first Limit entries recorded
+// after that id. Entries are ordered oldest first either way.
+type RequestLogFilter struct {
+	ProjectID int
+	AfterID   *int
+	Limit     int
+}
+
+// RequestLogRepository records the requests served by mocks. Entries are never
+// changed; Trim drops the oldest ones.
+type RequestLogRepository interface {
+	Create(entry *models.RequestLog) error
+	List(filter RequestLogFilter) ([]*models.RequestLog, error)
+	// Trim deletes all but the newest keep entries of a project.
+	Trim(projectID, keep int) error
+}
+
+// Transactor runs fn with a set of repositories bound to a single database
+// transaction. The transaction is rolled back if fn returns an error.
+type Transactor interface {
+	WithinTransaction(fn func(repos *Repositories) error) error
 }
 
 type Repositories struct {
 	User           UserRepository
 	Organisation   OrganisationRepository
 	Project        ProjectRepository
+	CodeRedirect   ProjectCodeRedirectRepository
+	Version        ProjectVersionRepository
+	Environment    ProjectEnvironmentRepository
+	Branch         ProjectBranchRepository
+	Sync           ProjectSyncRepository
 	Endpoint       EndpointRepository
+	Revision       EndpointRevisionRepository
 	UserOrgMapping UserOrganisationMappingRepository
+	Invitation     OrganisationInvitationRepository
+	APIToken       APITokenRepository
+	Session        UserSessionRepository
+	UserToken      UserTokenRepository
+	UserIdentity   UserIdentityRepository
+	AuthThrottle   AuthThrottleRepository
+	AuditLog       AuditLogRepository
+	RequestLog     RequestLogRepository
+	Transactor     Transactor
 }
 
 func NewRepositories(db *sqlx.DB) *Repositories {
+	repos := newRepositories(db)
+	repos.Transactor = NewTransactor(db)
+	return repos
+}
+
+// newRepositories builds the repositories on top of db. Repositories created for
+// a transaction keep a nil Transactor; nested transactions are not supported.
+func newRepositories(db DBTX) *Repositories {
 	return &Repositories{
 		User:           NewUserRepository(db),
 		Organisation:   NewOrganisationRepository(db),
 		Project:        NewProjectRepository(db),
+		CodeRedirect:   NewProjectCodeRedirectRepository(db),
+		Version:        NewProjectVersionRepository(db),
+		Environment:    NewProjectEnvironmentRepository(db),
+		Branch:         NewProjectBranchRepository(db),
+		Sync:           NewProjectSyncRepository(db),
 		Endpoint:       NewEndpointRepository(db),
+		Revision:       NewEndpointRevisionRepository(db),
 		UserOrgMapping: NewUserOrganisationMappingRepository(db),
+		Invitation:     NewOrganisationInvitationRepository(db),
+		APIToken:       NewAPITokenRepository(db),
+		Session:        NewUserSessionRepository(db),
+		UserToken:      NewUserTokenRepository(db),
+		UserIdentity:   NewUserIdentityRepository(db),
+		AuthThrottle:   NewAuthThrottleRepository(db),
+		AuditLog:       NewAuditLogRepository(db),
+		RequestLog:     NewRequestLogRepository(db),
 	}
 }
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type apiTokenRepository struct {
+	store *store
+}
+
+func (r *apiTokenRepository) Create(token *models.APIToken) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	tokens := &r.store.data.apiTokens
+	if tokens.exists(func(t *models.APIToken) bool { return t.TokenHash == token.TokenHash }) {
+		return uniqueViolation("api_tokens.token_hash")
+	}
+
+	token.ID = tokens.nextID()
+	token.UUID = newUUID()
+
+	row := *token
+	row.LastUsedAt = nil
+	row.Base = newBase(token.CreatedAt, token.UpdatedAt, nullString(token.CreatedBy.String))
+	tokens.insert(row)
+	return nil
+}
+
+func (r *apiTokenRepository) GetByTokenHash(tokenHash string) (*models.APIToken, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.apiTokens.get(func(t *models.APIToken) bool {
+		return t.TokenHash == tokenHash && t.DeletedAt == nil
+	})
+}
+
+func (r *apiTokenRepository) GetByUUIDForUser(uuid string, userID int) (*models.APIToken, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.apiTokens.get(func(t *models.APIToken) bool {
+		return t.UUID == uuid && t.UserID == userID && t.DeletedAt == nil
+	})
+}
+
+// GetByUserID returns the user's tokens, newest first.
+func (r *apiTokenRepository) GetByUserID(userID int) ([]*models.APIToken, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	tokens := r.store.data.apiTokens.filter(func(t *models.APIToken) bool {
+		return t.UserID == userID && t.DeletedAt == nil
+	})
+	return sortRows(tokens, func(a, b *models.APIToken) bool { return createdBefore(b.CreatedAt, a.CreatedAt) }), nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(id int, lastUsedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.apiTokens.update(
+		func(t *models.APIToken) bool { return t.ID == id },
+		func(t *models.APIToken) { t.LastUsedAt = &lastUsedAt },
+	)
+	return nil
+}
+
+func (r *apiTokenRepository) Revoke(id int, revokedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.apiTokens.update(
+		func(t *models.APIToken) bool { return t.ID == id },
+		func(t *models.APIToken) { markDeleted(&t.Base, revokedBy, now) },
+	)
+	return nil
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+type auditLogRepository struct {
+	store *store
+}
+
+func (r *auditLogRepository) Create(entry *models.AuditLog) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	entries := &r.store.data.auditLogs
+	entry.ID = entries.nextID()
+	entry.UUID = newUUID()
+	entries.insert(*entry)
+	return nil
+}
+
+// List returns the entries of an organisation matching filter, newest first.
+func (r *auditLogRepository) List(filter repository.AuditLogFilter) ([]*models.AuditLog, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	matches := func(e *models.AuditLog) bool {
+		switch {
+		case e.OrganisationID != filter.OrganisationID,
+			filter.ProjectUUID != "" && (!e.ProjectUUID.Valid || e.ProjectUUID.String != filter.ProjectUUID),
+			filter.EntityType != "" && e.EntityType != filter.EntityType,
+			filter.EntityUUID != "" && e.EntityUUID != filter.EntityUUID,
+			filter.ActorUUID != "" && e.ActorUUID != filter.ActorUUID,
+			filter.Action != "" && e.Action != filter.Action,
+			filter.Since != nil && e.CreatedAt.Before(*filter.Since),
+			filter.Until != nil && !e.CreatedAt.Before(*filter.Until),
+			filter.BeforeID != 0 && e.ID >= filter.BeforeID:
+			return false
+		}
+		return true
+	}
+
+	entries := []*models.AuditLog{}
+	rows := r.store.data.auditLogs.rows
+	for i := len(rows) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+		if matches(rows[i]) {
+			entry := *rows[i]
+			entries = append(entries, &entry)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type authThrottleRepository struct {
+	store *store
+}
+
+func (r *authThrottleRepository) Get(key string) (*models.AuthThrottle, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.authThrottles.get(func(t *models.AuthThrottle) bool { return t.Key == key })
+}
+
+// RecordFailure counts a failure for key. Counting starts over when the previous
+// failure and any lockout are older than windowStart.
+func (r *authThrottleRepository) RecordFailure(key string, at, windowStart time.Time) (*models.AuthThrottle, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	throttles := &r.store.data.authThrottles
+	throttle := throttles.find(func(t *models.AuthThrottle) bool { return t.Key == key })
+	if throttle == nil {
+		throttles.insert(models.AuthThrottle{Key: key, Failures: 1, LastFailureAt: at})
+		return &models.AuthThrottle{Key: key, Failures: 1, LastFailureAt: at}, nil
+	}
+
+	if throttle.LastFailureAt.Before(windowStart) && (throttle.LockedUntil == nil || throttle.LockedUntil.Before(windowStart)) {
+		throttle.Failures = 1
+	} else {
+		throttle.Failures++
+	}
+	throttle.LastFailureAt = at
+
+	recorded := *throttle
+	return &recorded, nil
+}
+
+func (r *authThrottleRepository) Lock(key string, until time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.authThrottles.update(
+		func(t *models.AuthThrottle) bool { return t.Key == key },
+		func(t *models.AuthThrottle) { t.LockedUntil = &until },
+	)
+	return nil
+}
+
+func (r *authThrottleRepository) Reset(key string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.authThrottles.remove(func(t *models.AuthThrottle) bool { return t.Key == key })
+	return nil
+}
+
+// DeleteStale removes counters whose last failure and lockout ended before cutoff.
+func (r *authThrottleRepository) DeleteStale(cutoff time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.authThrottles.remove(func(t *models.AuthThrottle) bool {
+		return t.LastFailureAt.Before(cutoff) && (t.LockedUntil == nil || t.LockedUntil.Before(cutoff))
+	})
+	return nil
+}
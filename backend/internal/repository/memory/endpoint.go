@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type endpointRepository struct {
+	store *store
+}
+
+func (r *endpointRepository) Create(endpoint *models.Endpoint) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := &r.store.data.endpoints
+	endpoint.ID = endpoints.nextID()
+	endpoint.UUID = newUUID()
+
+	row := *endpoint
+	row.Base = newBase(endpoint.CreatedAt, endpoint.UpdatedAt, nullString(endpoint.CreatedBy.String))
+	endpoints.insert(row)
+	return nil
+}
+
+func (r *endpointRepository) GetByProjectIDAndPath(projectID int, path, method string) (*models.Endpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.endpoints.get(func(e *models.Endpoint) bool {
+		return e.ProjectID == projectID && e.Path == path && e.Method == method && e.DeletedAt == nil
+	})
+}
+
+func (r *endpointRepository) GetByID(id int) (*models.Endpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.endpoints.get(func(e *models.Endpoint) bool {
+		return e.ID == id && e.DeletedAt == nil
+	})
+}
+
+func (r *endpointRepository) Update(endpoint *models.Endpoint) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.endpoints.update(
+		func(e *models.Endpoint) bool { return e.ID == endpoint.ID },
+		func(e *models.Endpoint) {
+			e.Method = endpoint.Method
+			e.Path = endpoint.Path
+			e.ResponseBody = endpoint.ResponseBody
+			e.ResponseStatus = endpoint.ResponseStatus
+			e.ResponseHeaders = endpoint.ResponseHeaders
+			e.UpdatedBy = nullString(endpoint.UpdatedBy.String)
+			e.UpdatedAt = endpoint.UpdatedAt
+			e.DeletedBy = nullString(endpoint.DeletedBy.String)
+			e.DeletedAt = endpoint.DeletedAt
+		},
+	)
+	return nil
+}
+
+func (r *endpointRepository) GetByProjectID(projectID int) ([]*models.Endpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.endpoints.filter(func(e *models.Endpoint) bool {
+		return e.ProjectID == projectID && e.DeletedAt == nil
+	}), nil
+}
+
+func (r *endpointRepository) GetByUUID(uuid string) (*models.Endpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.endpoints.get(func(e *models.Endpoint) bool {
+		return e.UUID == uuid && e.DeletedAt == nil
+	})
+}
+
+// DeleteByProjectID deletes the project's remaining endpoints. Passing the
+// deletion time of the project lets them be restored together with it.
+func (r *endpointRepository) DeleteByProjectID(projectID int, deletedBy string, deletedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.endpoints.update(
+		func(e *models.Endpoint) bool { return e.ProjectID == projectID && e.DeletedAt == nil },
+		func(e *models.Endpoint) { markDeleted(&e.Base, deletedBy, deletedAt) },
+	)
+	return nil
+}
+
+func (r *endpointRepository) DeleteByOrganisationID(organisationID int, deletedBy string, deletedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	projects := r.store.data.projectIDs(func(p *models.Project) bool { return p.OrganisationID == organisationID })
+	r.store.data.endpoints.update(
+		func(e *models.Endpoint) bool { return projects[e.ProjectID] && e.DeletedAt == nil },
+		func(e *models.Endpoint) { markDeleted(&e.Base, deletedBy, deletedAt) },
+	)
+	return nil
+}
+
+func (r *endpointRepository) GetDeletedByUUID(uuid string) (*models.Endpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.endpoints.get(func(e *models.Endpoint) bool {
+		return e.UUID == uuid && e.DeletedAt != nil
+	})
+}
+
+// GetDeletedByProjectID returns the deleted endpoints of a project, most
+// recently deleted first.
+func (r *endpointRepository) GetDeletedByProjectID(projectID int) ([]*models.Endpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := r.store.data.endpoints.filter(func(e *models.Endpoint) bool {
+		return e.ProjectID == projectID && e.DeletedAt != nil
+	})
+	return sortRows(endpoints, recentlyDeletedFirst), nil
+}
+
+// GetDeletedByOrganisationID returns the deleted endpoints of the organisation's
+// live projects, most recently deleted first. Endpoints of deleted projects are
+// only restored together with their project.
+func (r *endpointRepository) GetDeletedByOrganisationID(organisationID int) ([]*models.Endpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	projects := r.store.data.projectIDs(func(p *models.Project) bool {
+		return p.OrganisationID == organisationID && p.DeletedAt == nil
+	})
+	endpoints := r.store.data.endpoints.filter(func(e *models.Endpoint) bool {
+		return projects[e.ProjectID] && e.DeletedAt != nil
+	})
+	return sortRows(endpoints, recentlyDeletedFirst), nil
+}
+
+func recentlyDeletedFirst(a, b *models.Endpoint) bool {
+	return a.DeletedAt.After(*b.DeletedAt)
+}
+
+func (r *endpointRepository) Restore(id int, restoredBy string, restoredAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.endpoints.update(
+		func(e *models.Endpoint) bool { return e.ID == id },
+		func(e *models.Endpoint) { markRestored(&e.Base, restoredBy, restoredAt) },
+	)
+	return nil
+}
+
+// RestoreByProjectID restores the project's endpoints that were deleted at
+// deletedAt, i.e. together with the project.
+func (r *endpointRepository) RestoreByProjectID(projectID int, deletedAt time.Time, restoredBy string, restoredAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.endpoints.update(
+		func(e *models.Endpoint) bool {
+			return e.ProjectID == projectID && e.DeletedAt != nil && e.DeletedAt.Equal(deletedAt)
+		},
+		func(e *models.Endpoint) { markRestored(&e.Base, restoredBy, restoredAt) },
+	)
+	return nil
+}
+
+// PurgeDeletedBefore permanently removes endpoints deleted before cutoff along
+// with every endpoint of projects deleted before cutoff. Their revisions go with
+// them.
+func (r *endpointRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	data := r.store.data
+	projects := data.projectIDs(func(p *models.Project) bool {
+		return p.DeletedAt != nil && p.DeletedAt.Before(cutoff)
+	})
+	purged := map[int]bool{}
+	for _, e := range data.endpoints.filter(func(e *models.Endpoint) bool {
+		return (e.DeletedAt != nil && e.DeletedAt.Before(cutoff)) || projects[e.ProjectID]
+	}) {
+		purged[e.ID] = true
+	}
+
+	data.revisions.remove(func(rev *models.EndpointRevision) bool { return purged[rev.EndpointID] })
+	return data.endpoints.remove(func(e *models.Endpoint) bool { return purged[e.ID] }), nil
+}
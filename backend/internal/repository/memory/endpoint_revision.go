@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type endpointRevisionRepository struct {
+	store *store
+}
+
+// Create appends revision to the endpoint's history, numbering it after the
+// newest existing revision.
+func (r *endpointRevisionRepository) Create(revision *models.EndpointRevision) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	revisions := &r.store.data.revisions
+	latest := 0
+	for _, rev := range revisions.rows {
+		if rev.EndpointID == revision.EndpointID && rev.Revision > latest {
+			latest = rev.Revision
+		}
+	}
+
+	revision.ID = revisions.nextID()
+	revision.UUID = newUUID()
+	revision.Revision = latest + 1
+	revisions.insert(*revision)
+	return nil
+}
+
+// GetByEndpointID returns the endpoint's revisions, newest first.
+func (r *endpointRevisionRepository) GetByEndpointID(endpointID int) ([]*models.EndpointRevision, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	revisions := r.store.data.revisions.filter(func(rev *models.EndpointRevision) bool {
+		return rev.EndpointID == endpointID
+	})
+	return sortRows(revisions, func(a, b *models.EndpointRevision) bool { return a.Revision > b.Revision }), nil
+}
+
+func (r *endpointRevisionRepository) GetByEndpointIDAndRevision(endpointID, revision int) (*models.EndpointRevision, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.revisions.get(func(rev *models.EndpointRevision) bool {
+		return rev.EndpointID == endpointID && rev.Revision == revision
+	})
+}
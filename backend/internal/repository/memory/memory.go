@@ -0,0 +1,310 @@
+// Package memory implements the repository interfaces without a database. All
+// data lives in process memory and is lost on exit, which makes it suitable for
+// service tests and for running crudbox as a throwaway mock server.
+//
+// The repositories follow the SQL implementations closely: lookups of missing
+// or soft-deleted rows fail with sql.ErrNoRows, the unique constraints of the
+// schema are enforced, and hard deletes cascade like the foreign keys do.
+package memory
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+// ErrUniqueViolation is returned when a write would break one of the unique
+// constraints of the SQL schema.
+var ErrUniqueViolation = errors.New("unique constraint violation")
+
+// table holds the rows of one table in insertion order. Rows handed out by the
+// repositories are copies, so callers cannot change stored data by accident.
+type table[T any] struct {
+	rows   []*T
+	lastID int
+}
+
+func (t *table[T]) nextID() int {
+	t.lastID++
+	return t.lastID
+}
+
+func (t *table[T]) insert(row T) {
+	t.rows = append(t.rows, &row)
+}
+
+// find returns the stored row matching match, or nil.
+func (t *table[T]) find(match func(*T) bool) *T {
+	for _, row := range t.rows {
+		if match(row) {
+			return row
+		}
+	}
+	return nil
+}
+
+// get returns a copy of the row matching match.
+func (t *table[T]) get(match func(*T) bool) (*T, error) {
+	row := t.find(match)
+	if row == nil {
+		return nil, sql.ErrNoRows
+	}
+	found := *row
+	return &found, nil
+}
+
+// filter returns copies of the rows matching match in insertion order.
+func (t *table[T]) filter(match func(*T) bool) []*T {
+	rows := []*T{}
+	for _, row := range t.rows {
+		if match(row) {
+			found := *row
+			rows = append(rows, &found)
+		}
+	}
+	return rows
+}
+
+func (t *table[T]) exists(match func(*T) bool) bool {
+	return t.find(match) != nil
+}
+
+// update applies fn to every stored row matching match.
+func (t *table[T]) update(match func(*T) bool, fn func(*T)) {
+	for _, row := range t.rows {
+		if match(row) {
+			fn(row)
+		}
+	}
+}
+
+// remove deletes the rows matching match and returns how many there were.
+func (t *table[T]) remove(match func(*T) bool) int64 {
+	kept := t.rows[:0]
+	var removed int64
+	for _, row := range t.rows {
+		if match(row) {
+			removed++
+			continue
+		}
+		kept = append(kept, row)
+	}
+	clear(t.rows[len(kept):])
+	t.rows = kept
+	return removed
+}
+
+func (t *table[T]) clone() table[T] {
+	rows := make([]*T, len(t.rows))
+	for i, row := range t.rows {
+		copied := *row
+		rows[i] = &copied
+	}
+	return table[T]{rows: rows, lastID: t.lastID}
+}
+
+type tables struct {
+	users            table[models.User]
+	organisations    table[models.Organisation]
+	projects         table[models.Project]
+	codeRedirects    table[models.ProjectCodeRedirect]
+	versions         table[models.ProjectVersion]
+	versionEndpoints table[models.ProjectVersionEndpoint]
+	environments     table[models.ProjectEnvironment]
+	branches         table[models.ProjectBranch]
+	branchEndpoints  table[models.ProjectBranchEndpoint]
+	syncs            table[models.ProjectSync]
+	syncEndpoints    table[models.ProjectSyncEndpoint]
+	endpoints        table[models.Endpoint]
+	revisions        table[models.EndpointRevision]
+	userOrgMappings  table[models.UserOrganisationMapping]
+	invitations      table[models.OrganisationInvitation]
+	apiTokens        table[models.APIToken]
+	sessions         table[models.UserSession]
+	userTokens       table[models.UserToken]
+	userIdentities   table[models.UserIdentity]
+	authThrottles    table[models.AuthThrottle]
+	auditLogs        table[models.AuditLog]
+	requestLogs      table[models.RequestLog]
+}
+
+func (t *tables) clone() *tables {
+	return &tables{
+		users:            t.users.clone(),
+		organisations:    t.organisations.clone(),
+		projects:         t.projects.clone(),
+		codeRedirects:    t.codeRedirects.clone(),
+		versions:         t.versions.clone(),
+		versionEndpoints: t.versionEndpoints.clone(),
+		environments:     t.environments.clone(),
+		branches:         t.branches.clone(),
+		branchEndpoints:  t.branchEndpoints.clone(),
+		syncs:            t.syncs.clone(),
+		syncEndpoints:    t.syncEndpoints.clone(),
+		endpoints:        t.endpoints.clone(),
+		revisions:        t.revisions.clone(),
+		userOrgMappings:  t.userOrgMappings.clone(),
+		invitations:      t.invitations.clone(),
+		apiTokens:        t.apiTokens.clone(),
+		sessions:         t.sessions.clone(),
+		userTokens:       t.userTokens.clone(),
+		userIdentities:   t.userIdentities.clone(),
+		authThrottles:    t.authThrottles.clone(),
+		auditLogs:        t.auditLogs.clone(),
+		requestLogs:      t.requestLogs.clone(),
+	}
+}
+
+// projectIDs returns the ids of the projects matching match. It stands in for
+// the subqueries and joins on projects of the SQL repositories.
+func (t *tables) projectIDs(match func(*models.Project) bool) map[int]bool {
+	ids := map[int]bool{}
+	for _, p := range t.projects.rows {
+		if match(p) {
+			ids[p.ID] = true
+		}
+	}
+	return ids
+}
+
+// store is the state shared by a set of repositories. Every repository call
+// holds mu for its duration. Repositories of a transaction have a nil mu since
+// the transaction holds the lock until it ends.
+type store struct {
+	mu   *sync.Mutex
+	data *tables
+}
+
+func (s *store) lock() {
+	if s.mu != nil {
+		s.mu.Lock()
+	}
+}
+
+func (s *store) unlock() {
+	if s.mu != nil {
+		s.mu.Unlock()
+	}
+}
+
+// NewRepositories returns a set of repositories backed by a new, empty store.
+func NewRepositories() *repository.Repositories {
+	s := &store{mu: &sync.Mutex{}, data: &tables{}}
+	repos := newRepositories(s)
+	repos.Transactor = &transactor{store: s}
+	return repos
+}
+
+func newRepositories(s *store) *repository.Repositories {
+	return &repository.Repositories{
+		User:           &userRepository{store: s},
+		Organisation:   &organisationRepository{store: s},
+		Project:        &projectRepository{store: s},
+		CodeRedirect:   &projectCodeRedirectRepository{store: s},
+		Version:        &projectVersionRepository{store: s},
+		Environment:    &projectEnvironmentRepository{store: s},
+		Branch:         &projectBranchRepository{store: s},
+		Sync:           &projectSyncRepository{store: s},
+		Endpoint:       &endpointRepository{store: s},
+		Revision:       &endpointRevisionRepository{store: s},
+		UserOrgMapping: &userOrganisationMappingRepository{store: s},
+		Invitation:     &organisationInvitationRepository{store: s},
+		APIToken:       &apiTokenRepository{store: s},
+		Session:        &userSessionRepository{store: s},
+		UserToken:      &userTokenRepository{store: s},
+		UserIdentity:   &userIdentityRepository{store: s},
+		AuthThrottle:   &authThrottleRepository{store: s},
+		AuditLog:       &auditLogRepository{store: s},
+		RequestLog:     &requestLogRepository{store: s},
+	}
+}
+
+type transactor struct {
+	store *store
+}
+
+// WithinTransaction runs fn while holding the store lock, so transactions are
+// serialised against each other and against every other repository call. On
+// error the tables are put back as they were before fn started.
+func (t *transactor) WithinTransaction(fn func(repos *repository.Repositories) error) error {
+	t.store.lock()
+	defer t.store.unlock()
+
+	snapshot := t.store.data.clone()
+	if err := fn(newRepositories(&store{data: t.store.data})); err != nil {
+		*t.store.data = *snapshot
+		return err
+	}
+
+	return nil
+}
+
+func uniqueViolation(constraint string) error {
+	return fmt.Errorf("%w: %s", ErrUniqueViolation, constraint)
+}
+
+// newUUID returns a random version 4 UUID like the gen_random_uuid() column
+// defaults of the schema.
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// newBase returns the audit columns written by an INSERT: updated_by starts out
+// as created_by and the row is not deleted.
+func newBase(createdAt, updatedAt *time.Time, createdBy sql.NullString) models.Base {
+	return models.Base{CreatedAt: createdAt, UpdatedAt: updatedAt, CreatedBy: createdBy, UpdatedBy: createdBy}
+}
+
+// markDeleted soft-deletes a row the way the SQL repositories do, recording the
+// deletion as its latest update.
+func markDeleted(base *models.Base, deletedBy string, deletedAt time.Time) {
+	base.DeletedAt = &deletedAt
+	base.DeletedBy = nullString(deletedBy)
+	base.UpdatedAt = &deletedAt
+	base.UpdatedBy = nullString(deletedBy)
+}
+
+func markRestored(base *models.Base, restoredBy string, restoredAt time.Time) {
+	base.DeletedAt = nil
+	base.DeletedBy = sql.NullString{}
+	base.UpdatedAt = &restoredAt
+	base.UpdatedBy = nullString(restoredBy)
+}
+
+// nullString mirrors writing a plain string into a nullable column.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+// createdBefore orders rows by a nullable created_at, with NULLs last as in an
+// ascending ORDER BY.
+func createdBefore(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a != nil
+	}
+	return a.Before(*b)
+}
+
+// pathMethodLess orders snapshot endpoints by path, then method.
+func pathMethodLess(pathA, methodA, pathB, methodB string) bool {
+	if pathA != pathB {
+		return pathA < pathB
+	}
+	return methodA < methodB
+}
+
+func sortRows[T any](rows []*T, less func(a, b *T) bool) []*T {
+	sort.SliceStable(rows, func(i, j int) bool { return less(rows[i], rows[j]) })
+	return rows
+}
@@ -0,0 +1,294 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+func TestWithinTransactionRollsBackOnError(t *testing.T) {
+	repos := NewRepositories()
+	owner := &models.User{Email: "owner@example.com", Password: "old-hash"}
+	if err := repos.User.Create(owner); err != nil {
+		t.Fatal(err)
+	}
+
+	errAbort := errors.New("abort")
+	var org *models.Organisation
+	err := repos.Transactor.WithinTransaction(func(tx *repository.Repositories) error {
+		if err := tx.User.Create(&models.User{Email: "member@example.com"}); err != nil {
+			return err
+		}
+		org = &models.Organisation{Name: "Acme", UserID: owner.ID}
+		if err := tx.Organisation.Create(org); err != nil {
+			return err
+		}
+		if err := tx.User.UpdatePassword(owner.ID, "new-hash", owner.UUID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTransaction() error = %v, want %v", err, errAbort)
+	}
+
+	if _, err := repos.User.GetByEmail("member@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("user created in the transaction: GetByEmail() error = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := repos.Organisation.GetByUUID(org.UUID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("organisation created in the transaction: GetByUUID() error = %v, want sql.ErrNoRows", err)
+	}
+	stored, err := repos.User.GetByID(owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Password != "old-hash" {
+		t.Errorf("password updated in the transaction = %q, want it rolled back to old-hash", stored.Password)
+	}
+}
+
+func TestWithinTransactionCommits(t *testing.T) {
+	repos := NewRepositories()
+
+	err := repos.Transactor.WithinTransaction(func(tx *repository.Repositories) error {
+		return tx.User.Create(&models.User{Email: "member@example.com"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repos.User.GetByEmail("member@example.com"); err != nil {
+		t.Errorf("GetByEmail() after commit error = %v", err)
+	}
+}
+
+func TestUniqueConstraintInTransactionRollsBackEarlierWrites(t *testing.T) {
+	repos := NewRepositories()
+	if err := repos.User.Create(&models.User{Email: "taken@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := repos.Transactor.WithinTransaction(func(tx *repository.Repositories) error {
+		if err := tx.User.Create(&models.User{Email: "first@example.com"}); err != nil {
+			return err
+		}
+		return tx.User.Create(&models.User{Email: "taken@example.com"})
+	})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Fatalf("WithinTransaction() error = %v, want ErrUniqueViolation", err)
+	}
+	if _, err := repos.User.GetByEmail("first@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetByEmail() error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestUniqueConstraints(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  func(repos *repository.Repositories) error
+		second func(repos *repository.Repositories) error
+	}{
+		{
+			name: "user email",
+			first: func(repos *repository.Repositories) error {
+				return repos.User.Create(&models.User{Email: "a@example.com"})
+			},
+			second: func(repos *repository.Repositories) error {
+				return repos.User.Create(&models.User{Email: "a@example.com"})
+			},
+		},
+		{
+			name: "project code",
+			first: func(repos *repository.Repositories) error {
+				return repos.Project.Create(&models.Project{Code: "abc123"})
+			},
+			second: func(repos *repository.Repositories) error {
+				return repos.Project.Create(&models.Project{Code: "abc123"})
+			},
+		},
+		{
+			name: "project code on update",
+			first: func(repos *repository.Repositories) error {
+				if err := repos.Project.Create(&models.Project{Code: "abc123"}); err != nil {
+					return err
+				}
+				return repos.Project.Create(&models.Project{Code: "def456"})
+			},
+			second: func(repos *repository.Repositories) error {
+				project, err := repos.Project.GetByCode("def456")
+				if err != nil {
+					return err
+				}
+				project.Code = "abc123"
+				return repos.Project.Update(project)
+			},
+		},
+		{
+			name: "project hostname",
+			first: func(repos *repository.Repositories) error {
+				for _, code := range []string{"abc123", "def456"} {
+					if err := repos.Project.Create(&models.Project{Code: code}); err != nil {
+						return err
+					}
+				}
+				return setHostname(repos, "abc123", "mock.example.com")
+			},
+			second: func(repos *repository.Repositories) error { return setHostname(repos, "def456", "mock.example.com") },
+		},
+		{
+			name: "environment name within a project",
+			first: func(repos *repository.Repositories) error {
+				return repos.Environment.Create(&models.ProjectEnvironment{ProjectID: 1, Name: "dev", Variables: "{}"})
+			},
+			second: func(repos *repository.Repositories) error {
+				return repos.Environment.Create(&models.ProjectEnvironment{ProjectID: 1, Name: "dev", Variables: "{}"})
+			},
+		},
+		{
+			name: "one default environment per project",
+			first: func(repos *repository.Repositories) error {
+				return repos.Environment.Create(&models.ProjectEnvironment{ProjectID: 1, Name: "dev", Variables: "{}", IsDefault: true})
+			},
+			second: func(repos *repository.Repositories) error {
+				return repos.Environment.Create(&models.ProjectEnvironment{ProjectID: 1, Name: "qa", Variables: "{}", IsDefault: true})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := NewRepositories()
+			if err := tt.first(repos); err != nil {
+				t.Fatalf("first write: %v", err)
+			}
+			if err := tt.second(repos); !errors.Is(err, ErrUniqueViolation) {
+				t.Errorf("second write error = %v, want ErrUniqueViolation", err)
+			}
+		})
+	}
+}
+
+func TestUniqueConstraintsAllowDistinctValues(t *testing.T) {
+	repos := NewRepositories()
+	for _, environment := range []*models.ProjectEnvironment{
+		{ProjectID: 1, Name: "dev", Variables: "{}", IsDefault: true},
+		{ProjectID: 1, Name: "qa", Variables: "{}"},
+		{ProjectID: 2, Name: "dev", Variables: "{}", IsDefault: true},
+	} {
+		if err := repos.Environment.Create(environment); err != nil {
+			t.Errorf("Create(%d %s) error = %v", environment.ProjectID, environment.Name, err)
+		}
+	}
+}
+
+func TestRowsAreCopies(t *testing.T) {
+	repos := NewRepositories()
+	user := &models.User{Email: "a@example.com"}
+	if err := repos.User.Create(user); err != nil {
+		t.Fatal(err)
+	}
+
+	user.Email = "changed@example.com"
+	found, err := repos.User.GetByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found.Email = "changed@example.com"
+
+	stored, err := repos.User.GetByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Email != "a@example.com" {
+		t.Errorf("stored email = %q, want a@example.com", stored.Email)
+	}
+}
+
+func setHostname(repos *repository.Repositories, code, hostname string) error {
+	project, err := repos.Project.GetByCode(code)
+	if err != nil {
+		return err
+	}
+	project.Hostname = sql.NullString{String: hostname, Valid: true}
+	return repos.Project.Update(project)
+}
+
+func TestRequestLogListAndTrim(t *testing.T) {
+	repos := NewRepositories()
+	for i := range 5 {
+		for _, projectID := range []int{1, 2} {
+			if err := repos.RequestLog.Create(&models.RequestLog{ProjectID: projectID, Path: fmt.Sprintf("/%d", i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	paths := func(filter repository.RequestLogFilter) []string {
+		t.Helper()
+		entries, err := repos.RequestLog.List(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var paths []string
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+		return paths
+	}
+	after := func(id int) *int { return &id }
+
+	tests := []struct {
+		name   string
+		filter repository.RequestLogFilter
+		want   []string
+	}{
+		{name: "newest", filter: repository.RequestLogFilter{ProjectID: 1, Limit: 2}, want: []string{"/3", "/4"}},
+		{name: "all", filter: repository.RequestLogFilter{ProjectID: 1, Limit: 10}, want: []string{"/0", "/1", "/2", "/3", "/4"}},
+		{name: "after", filter: repository.RequestLogFilter{ProjectID: 1, AfterID: after(3), Limit: 2}, want: []string{"/2", "/3"}},
+		{name: "after start", filter: repository.RequestLogFilter{ProjectID: 1, AfterID: after(0), Limit: 1}, want: []string{"/0"}},
+		{name: "after last", filter: repository.RequestLogFilter{ProjectID: 1, AfterID: after(9), Limit: 2}, want: nil},
+	}
+	for _, tt := range tests {
+		if got := paths(tt.filter); !slices.Equal(got, tt.want) {
+			t.Errorf("List(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if err := repos.RequestLog.Trim(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := paths(repository.RequestLogFilter{ProjectID: 1, Limit: 10}), []string{"/3", "/4"}; !slices.Equal(got, want) {
+		t.Errorf("project 1 after Trim() = %v, want %v", got, want)
+	}
+	if got := paths(repository.RequestLogFilter{ProjectID: 2, Limit: 10}); len(got) != 5 {
+		t.Errorf("Trim() of project 1 left %d entries of project 2, want 5", len(got))
+	}
+}
+
+func TestRotateClaimsTheCurrentToken(t *testing.T) {
+	repos := NewRepositories()
+	session := &models.UserSession{UserID: 1, RefreshTokenHash: "first", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repos.Session.Create(session); err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	if err := repos.Session.Rotate(session.ID, "first", "second", expiresAt); err != nil {
+		t.Fatalf("Rotate(first) error = %v", err)
+	}
+	if err := repos.Session.Rotate(session.ID, "first", "third", expiresAt); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Rotate(first) again error = %v, want sql.ErrNoRows", err)
+	}
+	if err := repos.Session.Revoke(session.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Session.Rotate(session.ID, "second", "third", expiresAt); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Rotate() of a revoked session error = %v, want sql.ErrNoRows", err)
+	}
+}
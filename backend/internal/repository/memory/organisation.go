@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type organisationRepository struct {
+	store *store
+}
+
+func (r *organisationRepository) Create(org *models.Organisation) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	organisations := &r.store.data.organisations
+	org.ID = organisations.nextID()
+	org.UUID = newUUID()
+
+	row := *org
+	row.Base = newBase(org.CreatedAt, org.UpdatedAt, nullString(org.CreatedBy.String))
+	organisations.insert(row)
+	return nil
+}
+
+func (r *organisationRepository) Update(org *models.Organisation) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.organisations.update(
+		func(o *models.Organisation) bool { return o.ID == org.ID },
+		func(o *models.Organisation) {
+			o.Name = org.Name
+			o.UserID = org.UserID
+			o.UpdatedBy = nullString(org.UpdatedBy.String)
+			o.UpdatedAt = org.UpdatedAt
+		},
+	)
+	return nil
+}
+
+func (r *organisationRepository) DeleteByID(id int, deletedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.organisations.update(
+		func(o *models.Organisation) bool { return o.ID == id },
+		func(o *models.Organisation) { markDeleted(&o.Base, deletedBy, now) },
+	)
+	return nil
+}
+
+func (r *organisationRepository) GetByID(id int) (*models.Organisation, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.organisations.get(func(o *models.Organisation) bool {
+		return o.ID == id && o.DeletedAt == nil
+	})
+}
+
+func (r *organisationRepository) GetByUUID(uuid string) (*models.Organisation, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.organisations.get(func(o *models.Organisation) bool {
+		return o.UUID == uuid && o.DeletedAt == nil
+	})
+}
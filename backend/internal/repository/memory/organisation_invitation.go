@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type organisationInvitationRepository struct {
+	store *store
+}
+
+func (r *organisationInvitationRepository) Create(invitation *models.OrganisationInvitation) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	invitations := &r.store.data.invitations
+	if invitations.exists(func(i *models.OrganisationInvitation) bool { return i.TokenHash == invitation.TokenHash }) {
+		return uniqueViolation("organisation_invitations.token_hash")
+	}
+
+	invitation.ID = invitations.nextID()
+	invitation.UUID = newUUID()
+
+	row := *invitation
+	row.AcceptedAt = nil
+	row.AcceptedBy = sql.NullInt64{}
+	row.Base = newBase(invitation.CreatedAt, invitation.UpdatedAt, nullString(invitation.CreatedBy.String))
+	invitations.insert(row)
+	return nil
+}
+
+func (r *organisationInvitationRepository) GetByUUID(uuid string) (*models.OrganisationInvitation, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.invitations.get(func(i *models.OrganisationInvitation) bool {
+		return i.UUID == uuid && i.DeletedAt == nil
+	})
+}
+
+func (r *organisationInvitationRepository) GetByTokenHash(tokenHash string) (*models.OrganisationInvitation, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.invitations.get(func(i *models.OrganisationInvitation) bool {
+		return i.TokenHash == tokenHash && i.DeletedAt == nil
+	})
+}
+
+// GetPendingByOrganisationID returns invitations that are neither accepted,
+// revoked nor expired.
+func (r *organisationInvitationRepository) GetPendingByOrganisationID(organisationID int) ([]*models.OrganisationInvitation, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	invitations := r.store.data.invitations.filter(func(i *models.OrganisationInvitation) bool {
+		return i.OrganisationID == organisationID && i.AcceptedAt == nil && i.ExpiresAt.After(now) && i.DeletedAt == nil
+	})
+	return sortRows(invitations, func(a, b *models.OrganisationInvitation) bool { return createdBefore(a.CreatedAt, b.CreatedAt) }), nil
+}
+
+func (r *organisationInvitationRepository) MarkAccepted(id int, userID int, acceptedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	invitation := r.store.data.invitations.find(func(i *models.OrganisationInvitation) bool {
+		return i.ID == id && i.AcceptedAt == nil && i.DeletedAt == nil
+	})
+	if invitation == nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	invitation.AcceptedBy = sql.NullInt64{Int64: int64(userID), Valid: true}
+	invitation.UpdatedAt = &now
+	invitation.UpdatedBy = nullString(acceptedBy)
+	return nil
+}
+
+// RevokePendingByEmail revokes every open invitation for email in the organisation.
+func (r *organisationInvitationRepository) RevokePendingByEmail(organisationID int, email string, revokedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.invitations.update(
+		func(i *models.OrganisationInvitation) bool {
+			return i.OrganisationID == organisationID && strings.EqualFold(i.Email, email) && i.AcceptedAt == nil && i.DeletedAt == nil
+		},
+		func(i *models.OrganisationInvitation) { markDeleted(&i.Base, revokedBy, now) },
+	)
+	return nil
+}
+
+func (r *organisationInvitationRepository) Revoke(id int, revokedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.invitations.update(
+		func(i *models.OrganisationInvitation) bool { return i.ID == id },
+		func(i *models.OrganisationInvitation) { markDeleted(&i.Base, revokedBy, now) },
+	)
+	return nil
+}
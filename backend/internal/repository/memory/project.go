@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectRepository struct {
+	store *store
+}
+
+func (r *projectRepository) Create(project *models.Project) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	projects := &r.store.data.projects
+	if projects.exists(func(p *models.Project) bool { return p.Code == project.Code }) {
+		return uniqueViolation("projects.code")
+	}
+
+	project.ID = projects.nextID()
+	project.UUID = newUUID()
+
+	row := *project
+	row.Hostname = sql.NullString{}
+	row.Base = newBase(project.CreatedAt, project.UpdatedAt, nullString(project.CreatedBy.String))
+	projects.insert(row)
+	return nil
+}
+
+func (r *projectRepository) Update(project *models.Project) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	projects := &r.store.data.projects
+	if projects.exists(func(p *models.Project) bool { return p.ID != project.ID && p.Code == project.Code }) {
+		return uniqueViolation("projects.code")
+	}
+	if project.Hostname.Valid && projects.exists(func(p *models.Project) bool {
+		return p.ID != project.ID && p.Hostname.Valid && p.Hostname.String == project.Hostname.String
+	}) {
+		return uniqueViolation("projects.hostname")
+	}
+
+	projects.update(
+		func(p *models.Project) bool { return p.ID == project.ID },
+		func(p *models.Project) {
+			p.Name = project.Name
+			p.Code = project.Code
+			p.Hostname = project.Hostname
+			p.OrganisationID = project.OrganisationID
+			p.UpdatedBy = nullString(project.UpdatedBy.String)
+			p.UpdatedAt = project.UpdatedAt
+		},
+	)
+	return nil
+}
+
+func (r *projectRepository) GetByID(id int) (*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.get(func(p *models.Project) bool {
+		return p.ID == id && p.DeletedAt == nil
+	})
+}
+
+func (r *projectRepository) GetByCode(code string) (*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.get(func(p *models.Project) bool {
+		return p.Code == code && p.DeletedAt == nil
+	})
+}
+
+func (r *projectRepository) GetByCodeIgnoringCase(code string) (*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.get(func(p *models.Project) bool {
+		return strings.EqualFold(p.Code, code) && p.DeletedAt == nil
+	})
+}
+
+func (r *projectRepository) GetByHostname(hostname string) (*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.get(func(p *models.Project) bool {
+		return p.Hostname.Valid && p.Hostname.String == hostname && p.DeletedAt == nil
+	})
+}
+
+func (r *projectRepository) GetByOrganisationID(organisationID int) ([]*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.filter(func(p *models.Project) bool {
+		return p.OrganisationID == organisationID && p.DeletedAt == nil
+	}), nil
+}
+
+func (r *projectRepository) GetByUUID(uuid string) (*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.get(func(p *models.Project) bool {
+		return p.UUID == uuid && p.DeletedAt == nil
+	})
+}
+
+// CodeExists also sees deleted projects, whose codes stay reserved until they
+// are purged.
+func (r *projectRepository) CodeExists(code string) (bool, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.exists(func(p *models.Project) bool { return p.Code == code }), nil
+}
+
+func (r *projectRepository) DeleteByUUID(projectUUID string, deletedBy string, deletedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.projects.update(
+		func(p *models.Project) bool { return p.UUID == projectUUID && p.DeletedAt == nil },
+		func(p *models.Project) { deleteProject(p, deletedBy, deletedAt) },
+	)
+	return nil
+}
+
+func (r *projectRepository) DeleteByOrganisationID(organisationID int, deletedBy string, deletedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.projects.update(
+		func(p *models.Project) bool { return p.OrganisationID == organisationID && p.DeletedAt == nil },
+		func(p *models.Project) { deleteProject(p, deletedBy, deletedAt) },
+	)
+	return nil
+}
+
+// deleteProject soft-deletes p and gives up its hostname so another project can
+// claim it.
+func deleteProject(p *models.Project, deletedBy string, deletedAt time.Time) {
+	p.Hostname = sql.NullString{}
+	markDeleted(&p.Base, deletedBy, deletedAt)
+}
+
+func (r *projectRepository) GetDeletedByUUID(uuid string) (*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.projects.get(func(p *models.Project) bool {
+		return p.UUID == uuid && p.DeletedAt != nil
+	})
+}
+
+// GetDeletedByOrganisationID returns the organisation's deleted projects, most
+// recently deleted first.
+func (r *projectRepository) GetDeletedByOrganisationID(organisationID int) ([]*models.Project, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	projects := r.store.data.projects.filter(func(p *models.Project) bool {
+		return p.OrganisationID == organisationID && p.DeletedAt != nil
+	})
+	return sortRows(projects, func(a, b *models.Project) bool { return a.DeletedAt.After(*b.DeletedAt) }), nil
+}
+
+func (r *projectRepository) Restore(id int, restoredBy string, restoredAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.projects.update(
+		func(p *models.Project) bool { return p.ID == id },
+		func(p *models.Project) { markRestored(&p.Base, restoredBy, restoredAt) },
+	)
+	return nil
+}
+
+// PurgeDeletedBefore permanently removes projects deleted before cutoff together
+// with everything that belongs to them. Their endpoints must be purged first.
+func (r *projectRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	data := r.store.data
+	purged := data.projectIDs(func(p *models.Project) bool {
+		return p.DeletedAt != nil && p.DeletedAt.Before(cutoff)
+	})
+	if data.endpoints.exists(func(e *models.Endpoint) bool { return purged[e.ProjectID] }) {
+		return 0, errors.New("projects to purge still have endpoints")
+	}
+
+	data.codeRedirects.remove(func(c *models.ProjectCodeRedirect) bool { return purged[c.ProjectID] })
+	data.environments.remove(func(e *models.ProjectEnvironment) bool { return purged[e.ProjectID] })
+	data.requestLogs.remove(func(l *models.RequestLog) bool { return purged[l.ProjectID] })
+
+	versions := map[int]bool{}
+	for _, v := range data.versions.filter(func(v *models.ProjectVersion) bool { return purged[v.ProjectID] }) {
+		versions[v.ID] = true
+	}
+	data.versionEndpoints.remove(func(e *models.ProjectVersionEndpoint) bool { return versions[e.VersionID] })
+	data.versions.remove(func(v *models.ProjectVersion) bool { return versions[v.ID] })
+
+	branches := map[int]bool{}
+	for _, b := range data.branches.filter(func(b *models.ProjectBranch) bool {
+		return purged[b.ProjectID] || purged[b.ParentProjectID]
+	}) {
+		branches[b.ID] = true
+	}
+	data.branchEndpoints.remove(func(e *models.ProjectBranchEndpoint) bool { return branches[e.BranchID] })
+	data.branches.remove(func(b *models.ProjectBranch) bool { return branches[b.ID] })
+
+	syncs := map[int]bool{}
+	for _, s := range data.syncs.filter(func(s *models.ProjectSync) bool { return purged[s.ProjectID] }) {
+		syncs[s.ID] = true
+	}
+	data.syncEndpoints.remove(func(e *models.ProjectSyncEndpoint) bool { return syncs[e.SyncID] })
+	data.syncs.remove(func(s *models.ProjectSync) bool { return syncs[s.ID] })
+
+	return data.projects.remove(func(p *models.Project) bool { return purged[p.ID] }), nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectBranchRepository struct {
+	store *store
+}
+
+func (r *projectBranchRepository) Create(branch *models.ProjectBranch) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	branches := &r.store.data.branches
+	if branches.exists(func(b *models.ProjectBranch) bool { return b.ProjectID == branch.ProjectID }) {
+		return uniqueViolation("project_branches.project_id")
+	}
+
+	branch.ID = branches.nextID()
+	branch.UUID = newUUID()
+
+	row := *branch
+	row.CreatedBy = nullString(branch.CreatedBy.String)
+	row.MergedAt = nil
+	row.MergedBy = sql.NullString{}
+	branches.insert(row)
+	return nil
+}
+
+func (r *projectBranchRepository) CreateBaseEndpoint(endpoint *models.ProjectBranchEndpoint) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := &r.store.data.branchEndpoints
+	if endpoints.exists(func(e *models.ProjectBranchEndpoint) bool {
+		return e.BranchID == endpoint.BranchID && e.Method == endpoint.Method && e.Path == endpoint.Path
+	}) {
+		return uniqueViolation("project_branch_base_endpoints.branch_id_method_path")
+	}
+
+	endpoint.ID = endpoints.nextID()
+	endpoints.insert(*endpoint)
+	return nil
+}
+
+// DeleteBaseEndpoints removes the branch point of a branch so a new one can be
+// recorded after a merge.
+func (r *projectBranchRepository) DeleteBaseEndpoints(branchID int) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.branchEndpoints.remove(func(e *models.ProjectBranchEndpoint) bool { return e.BranchID == branchID })
+	return nil
+}
+
+func (r *projectBranchRepository) GetByProjectID(projectID int) (*models.ProjectBranch, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.branches.get(func(b *models.ProjectBranch) bool { return b.ProjectID == projectID })
+}
+
+// GetByParentProjectID returns the branches of a project whose branch project
+// has not been deleted, oldest first.
+func (r *projectBranchRepository) GetByParentProjectID(parentProjectID int) ([]*models.ProjectBranch, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	live := r.store.data.projectIDs(func(p *models.Project) bool { return p.DeletedAt == nil })
+	return r.store.data.branches.filter(func(b *models.ProjectBranch) bool {
+		return b.ParentProjectID == parentProjectID && live[b.ProjectID]
+	}), nil
+}
+
+func (r *projectBranchRepository) GetBaseEndpoints(branchID int) ([]*models.ProjectBranchEndpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := r.store.data.branchEndpoints.filter(func(e *models.ProjectBranchEndpoint) bool {
+		return e.BranchID == branchID
+	})
+	return sortRows(endpoints, func(a, b *models.ProjectBranchEndpoint) bool {
+		return pathMethodLess(a.Path, a.Method, b.Path, b.Method)
+	}), nil
+}
+
+func (r *projectBranchRepository) MarkMerged(id int, mergedBy string, mergedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.branches.update(
+		func(b *models.ProjectBranch) bool { return b.ID == id },
+		func(b *models.ProjectBranch) {
+			b.MergedAt = &mergedAt
+			b.MergedBy = nullString(mergedBy)
+		},
+	)
+	return nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectCodeRedirectRepository struct {
+	store *store
+}
+
+func (r *projectCodeRedirectRepository) Create(redirect *models.ProjectCodeRedirect) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	redirects := &r.store.data.codeRedirects
+	if redirects.exists(func(c *models.ProjectCodeRedirect) bool { return c.Code == redirect.Code }) {
+		return uniqueViolation("project_code_redirects.code")
+	}
+
+	redirect.ID = redirects.nextID()
+	redirect.UUID = newUUID()
+
+	row := *redirect
+	row.Base = newBase(redirect.CreatedAt, redirect.UpdatedAt, nullString(redirect.CreatedBy.String))
+	redirects.insert(row)
+	return nil
+}
+
+func (r *projectCodeRedirectRepository) GetByCode(code string) (*models.ProjectCodeRedirect, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.codeRedirects.get(func(c *models.ProjectCodeRedirect) bool { return c.Code == code })
+}
+
+// Redirects are removed outright rather than soft-deleted so their codes can be
+// reused by other projects.
+func (r *projectCodeRedirectRepository) DeleteByCode(code string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.codeRedirects.remove(func(c *models.ProjectCodeRedirect) bool { return c.Code == code })
+	return nil
+}
+
+func (r *projectCodeRedirectRepository) DeleteByProjectID(projectID int) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.codeRedirects.remove(func(c *models.ProjectCodeRedirect) bool { return c.ProjectID == projectID })
+	return nil
+}
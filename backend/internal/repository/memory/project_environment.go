@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectEnvironmentRepository struct {
+	store *store
+}
+
+func (r *projectEnvironmentRepository) Create(environment *models.ProjectEnvironment) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	environments := &r.store.data.environments
+	if err := r.checkUnique(environment); err != nil {
+		return err
+	}
+
+	environment.ID = environments.nextID()
+	environment.UUID = newUUID()
+
+	row := *environment
+	row.Base = newBase(environment.CreatedAt, environment.UpdatedAt, nullString(environment.CreatedBy.String))
+	environments.insert(row)
+	return nil
+}
+
+func (r *projectEnvironmentRepository) Update(environment *models.ProjectEnvironment) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	if err := r.checkUnique(environment); err != nil {
+		return err
+	}
+
+	r.store.data.environments.update(
+		func(e *models.ProjectEnvironment) bool { return e.ID == environment.ID },
+		func(e *models.ProjectEnvironment) {
+			e.Name = environment.Name
+			e.Variables = environment.Variables
+			e.IsDefault = environment.IsDefault
+			e.UpdatedAt = environment.UpdatedAt
+			e.UpdatedBy = nullString(environment.UpdatedBy.String)
+		},
+	)
+	return nil
+}
+
+// checkUnique enforces the partial unique indexes on live environments: names
+// are unique within a project and a project has at most one default.
+func (r *projectEnvironmentRepository) checkUnique(environment *models.ProjectEnvironment) error {
+	others := r.store.data.environments.filter(func(e *models.ProjectEnvironment) bool {
+		return e.ID != environment.ID && e.ProjectID == environment.ProjectID && e.DeletedAt == nil
+	})
+	for _, other := range others {
+		if other.Name == environment.Name {
+			return uniqueViolation("project_environments.project_id_name")
+		}
+		if other.IsDefault && environment.IsDefault {
+			return uniqueViolation("project_environments.default")
+		}
+	}
+	return nil
+}
+
+// ClearDefault unsets the default flag on every environment of the project.
+func (r *projectEnvironmentRepository) ClearDefault(projectID int) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.environments.update(
+		func(e *models.ProjectEnvironment) bool { return e.ProjectID == projectID && e.IsDefault },
+		func(e *models.ProjectEnvironment) { e.IsDefault = false },
+	)
+	return nil
+}
+
+func (r *projectEnvironmentRepository) GetByProjectID(projectID int) ([]*models.ProjectEnvironment, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	environments := r.store.data.environments.filter(func(e *models.ProjectEnvironment) bool {
+		return e.ProjectID == projectID && e.DeletedAt == nil
+	})
+	return sortRows(environments, func(a, b *models.ProjectEnvironment) bool { return a.Name < b.Name }), nil
+}
+
+func (r *projectEnvironmentRepository) GetByProjectIDAndName(projectID int, name string) (*models.ProjectEnvironment, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.environments.get(func(e *models.ProjectEnvironment) bool {
+		return e.ProjectID == projectID && e.Name == name && e.DeletedAt == nil
+	})
+}
+
+func (r *projectEnvironmentRepository) GetDefault(projectID int) (*models.ProjectEnvironment, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.environments.get(func(e *models.ProjectEnvironment) bool {
+		return e.ProjectID == projectID && e.IsDefault && e.DeletedAt == nil
+	})
+}
+
+func (r *projectEnvironmentRepository) Delete(id int, deletedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.environments.update(
+		func(e *models.ProjectEnvironment) bool { return e.ID == id && e.DeletedAt == nil },
+		func(e *models.ProjectEnvironment) {
+			markDeleted(&e.Base, deletedBy, now)
+			e.IsDefault = false
+		},
+	)
+	return nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectSyncRepository struct {
+	store *store
+}
+
+// Save records a sync of the project, replacing the time and user of any
+// earlier one while keeping its id.
+func (r *projectSyncRepository) Save(sync *models.ProjectSync) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	syncs := &r.store.data.syncs
+	if existing := syncs.find(func(s *models.ProjectSync) bool { return s.ProjectID == sync.ProjectID }); existing != nil {
+		existing.SyncedAt = sync.SyncedAt
+		existing.SyncedBy = nullString(sync.SyncedBy.String)
+		sync.ID = existing.ID
+		return nil
+	}
+
+	sync.ID = syncs.nextID()
+	row := *sync
+	row.SyncedBy = nullString(sync.SyncedBy.String)
+	syncs.insert(row)
+	return nil
+}
+
+func (r *projectSyncRepository) CreateEndpoint(endpoint *models.ProjectSyncEndpoint) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := &r.store.data.syncEndpoints
+	if endpoints.exists(func(e *models.ProjectSyncEndpoint) bool {
+		return e.SyncID == endpoint.SyncID && e.Method == endpoint.Method && e.Path == endpoint.Path
+	}) {
+		return uniqueViolation("project_sync_endpoints.sync_id_method_path")
+	}
+
+	endpoint.ID = endpoints.nextID()
+	endpoints.insert(*endpoint)
+	return nil
+}
+
+// DeleteEndpoints removes the endpoints recorded by a sync so the next sync can
+// record its own.
+func (r *projectSyncRepository) DeleteEndpoints(syncID int) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.syncEndpoints.remove(func(e *models.ProjectSyncEndpoint) bool { return e.SyncID == syncID })
+	return nil
+}
+
+func (r *projectSyncRepository) GetByProjectID(projectID int) (*models.ProjectSync, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.syncs.get(func(s *models.ProjectSync) bool { return s.ProjectID == projectID })
+}
+
+func (r *projectSyncRepository) GetEndpoints(syncID int) ([]*models.ProjectSyncEndpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := r.store.data.syncEndpoints.filter(func(e *models.ProjectSyncEndpoint) bool {
+		return e.SyncID == syncID
+	})
+	return sortRows(endpoints, func(a, b *models.ProjectSyncEndpoint) bool {
+		return pathMethodLess(a.Path, a.Method, b.Path, b.Method)
+	}), nil
+}
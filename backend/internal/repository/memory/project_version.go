@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectVersionRepository struct {
+	store *store
+}
+
+func (r *projectVersionRepository) Create(version *models.ProjectVersion) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	versions := &r.store.data.versions
+	if versions.exists(func(v *models.ProjectVersion) bool {
+		return v.ProjectID == version.ProjectID && v.Name == version.Name && v.DeletedAt == nil
+	}) {
+		return uniqueViolation("project_versions.project_id_name")
+	}
+
+	version.ID = versions.nextID()
+	version.UUID = newUUID()
+
+	row := *version
+	row.EndpointCount = 0
+	row.Base = newBase(version.CreatedAt, version.UpdatedAt, nullString(version.CreatedBy.String))
+	versions.insert(row)
+	return nil
+}
+
+func (r *projectVersionRepository) CreateEndpoint(endpoint *models.ProjectVersionEndpoint) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := &r.store.data.versionEndpoints
+	if endpoints.exists(func(e *models.ProjectVersionEndpoint) bool {
+		return e.VersionID == endpoint.VersionID && e.Method == endpoint.Method && e.Path == endpoint.Path
+	}) {
+		return uniqueViolation("project_version_endpoints.version_id_method_path")
+	}
+
+	endpoint.ID = endpoints.nextID()
+	endpoints.insert(*endpoint)
+	return nil
+}
+
+// GetByProjectID returns the project's versions, newest first.
+func (r *projectVersionRepository) GetByProjectID(projectID int) ([]*models.ProjectVersion, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	versions := r.store.data.versions.filter(func(v *models.ProjectVersion) bool {
+		return v.ProjectID == projectID && v.DeletedAt == nil
+	})
+	for _, version := range versions {
+		r.countEndpoints(version)
+	}
+	return sortRows(versions, func(a, b *models.ProjectVersion) bool { return a.ID > b.ID }), nil
+}
+
+func (r *projectVersionRepository) GetByProjectIDAndName(projectID int, name string) (*models.ProjectVersion, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	version, err := r.store.data.versions.get(func(v *models.ProjectVersion) bool {
+		return v.ProjectID == projectID && v.Name == name && v.DeletedAt == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.countEndpoints(version)
+	return version, nil
+}
+
+func (r *projectVersionRepository) countEndpoints(version *models.ProjectVersion) {
+	version.EndpointCount = len(r.store.data.versionEndpoints.filter(func(e *models.ProjectVersionEndpoint) bool {
+		return e.VersionID == version.ID
+	}))
+}
+
+func (r *projectVersionRepository) GetEndpoints(versionID int) ([]*models.ProjectVersionEndpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	endpoints := r.store.data.versionEndpoints.filter(func(e *models.ProjectVersionEndpoint) bool {
+		return e.VersionID == versionID
+	})
+	return sortRows(endpoints, func(a, b *models.ProjectVersionEndpoint) bool {
+		return pathMethodLess(a.Path, a.Method, b.Path, b.Method)
+	}), nil
+}
+
+func (r *projectVersionRepository) GetEndpoint(versionID int, path, method string) (*models.ProjectVersionEndpoint, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.versionEndpoints.get(func(e *models.ProjectVersionEndpoint) bool {
+		return e.VersionID == versionID && e.Path == path && e.Method == method
+	})
+}
+
+func (r *projectVersionRepository) Delete(id int, deletedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.versions.update(
+		func(v *models.ProjectVersion) bool { return v.ID == id && v.DeletedAt == nil },
+		func(v *models.ProjectVersion) { markDeleted(&v.Base, deletedBy, now) },
+	)
+	return nil
+}
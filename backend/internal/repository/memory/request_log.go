@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+type requestLogRepository struct {
+	store *store
+}
+
+func (r *requestLogRepository) Create(entry *models.RequestLog) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	entries := &r.store.data.requestLogs
+	entry.ID = entries.nextID()
+	entries.insert(*entry)
+	return nil
+}
+
+// List returns the entries of a project selected by filter, oldest first.
+func (r *requestLogRepository) List(filter repository.RequestLogFilter) ([]*models.RequestLog, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	entries := r.store.data.requestLogs.filter(func(e *models.RequestLog) bool {
+		return e.ProjectID == filter.ProjectID && (filter.AfterID == nil || e.ID > *filter.AfterID)
+	})
+	if filter.AfterID != nil {
+		return entries[:min(len(entries), filter.Limit)], nil
+	}
+	return entries[max(0, len(entries)-filter.Limit):], nil
+}
+
+func (r *requestLogRepository) Trim(projectID, keep int) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	entries := &r.store.data.requestLogs
+	count := len(entries.filter(func(e *models.RequestLog) bool { return e.ProjectID == projectID }))
+	excess := count - keep
+	entries.remove(func(e *models.RequestLog) bool {
+		if e.ProjectID != projectID || excess <= 0 {
+			return false
+		}
+		excess--
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userRepository struct {
+	store *store
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	users := &r.store.data.users
+	if users.exists(func(u *models.User) bool { return u.Email == user.Email }) {
+		return uniqueViolation("users.email")
+	}
+
+	user.ID = users.nextID()
+	user.UUID = newUUID()
+	user.CreatedBy = nullString(user.UUID)
+	user.UpdatedBy = nullString(user.UUID)
+
+	row := *user
+	row.Base = newBase(user.CreatedAt, user.UpdatedAt, user.CreatedBy)
+	users.insert(row)
+	return nil
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.users.get(func(u *models.User) bool {
+		return u.Email == email && u.DeletedAt == nil
+	})
+}
+
+func (r *userRepository) GetByID(id int) (*models.User, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.users.get(func(u *models.User) bool {
+		return u.ID == id && u.DeletedAt == nil
+	})
+}
+
+func (r *userRepository) GetByUUID(uuid string) (*models.User, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.users.get(func(u *models.User) bool {
+		return u.UUID == uuid && u.DeletedAt == nil
+	})
+}
+
+func (r *userRepository) UpdatePassword(id int, password, updatedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.users.update(
+		func(u *models.User) bool { return u.ID == id && u.DeletedAt == nil },
+		func(u *models.User) {
+			u.Password = password
+			u.UpdatedAt = &now
+			u.UpdatedBy = nullString(updatedBy)
+		},
+	)
+	return nil
+}
+
+func (r *userRepository) MarkEmailVerified(id int, verifiedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.store.data.users.update(
+		func(u *models.User) bool { return u.ID == id && u.DeletedAt == nil },
+		func(u *models.User) {
+			u.EmailVerifiedAt = &verifiedAt
+			u.UpdatedAt = &verifiedAt
+		},
+	)
+	return nil
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userIdentityRepository struct {
+	store *store
+}
+
+func (r *userIdentityRepository) Create(identity *models.UserIdentity) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	identities := &r.store.data.userIdentities
+	if identities.exists(func(i *models.UserIdentity) bool {
+		return i.Issuer == identity.Issuer && i.Subject == identity.Subject
+	}) {
+		return uniqueViolation("user_identities.issuer_subject")
+	}
+
+	identity.ID = identities.nextID()
+	identity.UUID = newUUID()
+
+	row := *identity
+	row.Base = newBase(identity.CreatedAt, identity.UpdatedAt, nullString(identity.CreatedBy.String))
+	identities.insert(row)
+	return nil
+}
+
+func (r *userIdentityRepository) GetByIssuerAndSubject(issuer, subject string) (*models.UserIdentity, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.userIdentities.get(func(i *models.UserIdentity) bool {
+		return i.Issuer == issuer && i.Subject == subject && i.DeletedAt == nil
+	})
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userOrganisationMappingRepository struct {
+	store *store
+}
+
+func (r *userOrganisationMappingRepository) Create(mapping *models.UserOrganisationMapping) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	mappings := &r.store.data.userOrgMappings
+	base := newBase(mapping.CreatedAt, mapping.UpdatedAt, mapping.CreatedBy)
+
+	// A member who was removed earlier keeps a soft-deleted row, which is revived
+	// instead of violating the unique (user_id, organisation_id) constraint.
+	if existing := mappings.find(func(m *models.UserOrganisationMapping) bool {
+		return m.UserID == mapping.UserID && m.OrganisationID == mapping.OrganisationID
+	}); existing != nil {
+		existing.Role = mapping.Role
+		existing.Base = base
+		mapping.ID = existing.ID
+		mapping.UUID = existing.UUID
+		return nil
+	}
+
+	mapping.ID = mappings.nextID()
+	mapping.UUID = newUUID()
+
+	row := *mapping
+	row.Base = base
+	mappings.insert(row)
+	return nil
+}
+
+func (r *userOrganisationMappingRepository) GetByUserID(userID int) ([]*models.UserOrganisationMapping, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.userOrgMappings.filter(func(m *models.UserOrganisationMapping) bool {
+		return m.UserID == userID && m.DeletedAt == nil
+	}), nil
+}
+
+func (r *userOrganisationMappingRepository) GetByOrganisationID(orgID int) ([]*models.UserOrganisationMapping, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	mappings := r.store.data.userOrgMappings.filter(func(m *models.UserOrganisationMapping) bool {
+		return m.OrganisationID == orgID && m.DeletedAt == nil
+	})
+	return sortRows(mappings, func(a, b *models.UserOrganisationMapping) bool { return createdBefore(a.CreatedAt, b.CreatedAt) }), nil
+}
+
+func (r *userOrganisationMappingRepository) GetByUserIDAndOrganisationID(userID, orgID int) (*models.UserOrganisationMapping, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.userOrgMappings.get(func(m *models.UserOrganisationMapping) bool {
+		return m.UserID == userID && m.OrganisationID == orgID && m.DeletedAt == nil
+	})
+}
+
+func (r *userOrganisationMappingRepository) UpdateRole(userID, orgID int, role string, updatedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.userOrgMappings.update(
+		func(m *models.UserOrganisationMapping) bool {
+			return m.UserID == userID && m.OrganisationID == orgID && m.DeletedAt == nil
+		},
+		func(m *models.UserOrganisationMapping) {
+			m.Role = role
+			m.UpdatedAt = &now
+			m.UpdatedBy = nullString(updatedBy)
+		},
+	)
+	return nil
+}
+
+func (r *userOrganisationMappingRepository) CheckUserInOrganisation(userID, orgID int) (bool, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.userOrgMappings.exists(func(m *models.UserOrganisationMapping) bool {
+		return m.UserID == userID && m.OrganisationID == orgID && m.DeletedAt == nil
+	}), nil
+}
+
+func (r *userOrganisationMappingRepository) Delete(userID, orgID int, deletedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.softDelete(func(m *models.UserOrganisationMapping) bool {
+		return m.UserID == userID && m.OrganisationID == orgID
+	}, deletedBy)
+	return nil
+}
+
+func (r *userOrganisationMappingRepository) DeleteByOrganisationID(orgID int, deletedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	r.softDelete(func(m *models.UserOrganisationMapping) bool { return m.OrganisationID == orgID }, deletedBy)
+	return nil
+}
+
+func (r *userOrganisationMappingRepository) softDelete(match func(*models.UserOrganisationMapping) bool, deletedBy string) {
+	now := time.Now()
+	r.store.data.userOrgMappings.update(
+		func(m *models.UserOrganisationMapping) bool { return match(m) && m.DeletedAt == nil },
+		func(m *models.UserOrganisationMapping) { markDeleted(&m.Base, deletedBy, now) },
+	)
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userSessionRepository struct {
+	store *store
+}
+
+func (r *userSessionRepository) Create(session *models.UserSession) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	sessions := &r.store.data.sessions
+	if sessions.exists(func(s *models.UserSession) bool { return s.RefreshTokenHash == session.RefreshTokenHash }) {
+		return uniqueViolation("user_sessions.refresh_token_hash")
+	}
+
+	session.ID = sessions.nextID()
+	session.UUID = newUUID()
+
+	row := *session
+	row.PreviousRefreshTokenHash = sql.NullString{}
+	row.Base = newBase(session.CreatedAt, session.UpdatedAt, nullString(session.CreatedBy.String))
+	sessions.insert(row)
+	return nil
+}
+
+func (r *userSessionRepository) GetByUUID(uuid string) (*models.UserSession, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.sessions.get(func(s *models.UserSession) bool {
+		return s.UUID == uuid && s.DeletedAt == nil
+	})
+}
+
+func (r *userSessionRepository) GetByRefreshTokenHash(tokenHash string) (*models.UserSession, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.sessions.get(func(s *models.UserSession) bool {
+		return s.RefreshTokenHash == tokenHash && s.DeletedAt == nil
+	})
+}
+
+func (r *userSessionRepository) GetByPreviousRefreshTokenHash(tokenHash string) (*models.UserSession, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.sessions.get(func(s *models.UserSession) bool {
+		return s.PreviousRefreshTokenHash.Valid && s.PreviousRefreshTokenHash.String == tokenHash && s.DeletedAt == nil
+	})
+}
+
+// Rotate replaces the session's refresh token, remembering the previous one so
+// that its reuse can be detected. It returns sql.ErrNoRows unless oldHash is
+// still the current token of a live session.
+func (r *userSessionRepository) Rotate(id int, oldHash, refreshTokenHash string, expiresAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	sessions := &r.store.data.sessions
+	session := sessions.find(func(s *models.UserSession) bool {
+		return s.ID == id && s.RefreshTokenHash == oldHash && s.DeletedAt == nil
+	})
+	if session == nil {
+		return sql.ErrNoRows
+	}
+	if sessions.exists(func(s *models.UserSession) bool { return s.ID != id && s.RefreshTokenHash == refreshTokenHash }) {
+		return uniqueViolation("user_sessions.refresh_token_hash")
+	}
+
+	now := time.Now()
+	session.PreviousRefreshTokenHash = nullString(session.RefreshTokenHash)
+	session.RefreshTokenHash = refreshTokenHash
+	session.ExpiresAt = expiresAt
+	session.LastUsedAt = &now
+	session.UpdatedAt = &now
+	return nil
+}
+
+func (r *userSessionRepository) Revoke(id int, revokedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.sessions.update(
+		func(s *models.UserSession) bool { return s.ID == id && s.DeletedAt == nil },
+		func(s *models.UserSession) { markDeleted(&s.Base, revokedBy, now) },
+	)
+	return nil
+}
+
+func (r *userSessionRepository) RevokeByUserID(userID int, revokedBy string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.sessions.update(
+		func(s *models.UserSession) bool { return s.UserID == userID && s.DeletedAt == nil },
+		func(s *models.UserSession) { markDeleted(&s.Base, revokedBy, now) },
+	)
+	return nil
+}
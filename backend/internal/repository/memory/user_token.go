@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userTokenRepository struct {
+	store *store
+}
+
+func (r *userTokenRepository) Create(token *models.UserToken) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	tokens := &r.store.data.userTokens
+	if tokens.exists(func(t *models.UserToken) bool { return t.TokenHash == token.TokenHash }) {
+		return uniqueViolation("user_tokens.token_hash")
+	}
+
+	token.ID = tokens.nextID()
+	token.UUID = newUUID()
+
+	row := *token
+	row.UsedAt = nil
+	row.Base = newBase(token.CreatedAt, token.UpdatedAt, nullString(token.CreatedBy.String))
+	tokens.insert(row)
+	return nil
+}
+
+// GetByTokenHash returns the unused token with the given hash and purpose.
+// Expiry is left to the caller.
+func (r *userTokenRepository) GetByTokenHash(tokenHash, purpose string) (*models.UserToken, error) {
+	r.store.lock()
+	defer r.store.unlock()
+
+	return r.store.data.userTokens.get(func(t *models.UserToken) bool {
+		return t.TokenHash == tokenHash && t.Purpose == purpose && t.UsedAt == nil && t.DeletedAt == nil
+	})
+}
+
+func (r *userTokenRepository) MarkUsed(id int, usedAt time.Time) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	token := r.store.data.userTokens.find(func(t *models.UserToken) bool { return t.ID == id && t.UsedAt == nil })
+	if token == nil {
+		return sql.ErrNoRows
+	}
+	token.UsedAt = &usedAt
+	token.UpdatedAt = &usedAt
+	return nil
+}
+
+// InvalidateByUserID retires every outstanding token of the user for purpose,
+// so only the most recently mailed link works.
+func (r *userTokenRepository) InvalidateByUserID(userID int, purpose string) error {
+	r.store.lock()
+	defer r.store.unlock()
+
+	now := time.Now()
+	r.store.data.userTokens.update(
+		func(t *models.UserToken) bool {
+			return t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil && t.DeletedAt == nil
+		},
+		func(t *models.UserToken) {
+			t.DeletedAt = &now
+			t.DeletedBy = nullString("system")
+			t.UpdatedAt = &now
+		},
+	)
+	return nil
+}
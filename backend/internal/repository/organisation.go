@@ -1,16 +1,16 @@
 package repository
 
 import (
-	"github.com/jmoiron/sqlx"
+	"time"
 
 	"github.com/crudboxin/crudbox/internal/models"
 )
 
 type organisationRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewOrganisationRepository(db *sqlx.DB) OrganisationRepository {
+func NewOrganisationRepository(db DBTX) OrganisationRepository {
 	return &organisationRepository{db: db}
 }
 
@@ -21,6 +21,23 @@ func (r *organisationRepository) Create(org *models.Organisation) error {
 	).StructScan(org)
 }
 
+func (r *organisationRepository) Update(org *models.Organisation) error {
+	_, err := r.db.Exec(
+		"UPDATE organisations SET name = $1, user_id = $2, updated_by = $3, updated_at = $4 WHERE id = $5",
+		org.Name, org.UserID, org.UpdatedBy.String, org.UpdatedAt, org.ID,
+	)
+	return err
+}
+
+func (r *organisationRepository) DeleteByID(id int, deletedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE organisations SET deleted_at = $1, deleted_by = $2, updated_by = $2, updated_at = $1 WHERE id = $3",
+		now, deletedBy, id,
+	)
+	return err
+}
+
 func (r *organisationRepository) GetByID(id int) (*models.Organisation, error) {
 	var org models.Organisation
 	err := r.db.Get(
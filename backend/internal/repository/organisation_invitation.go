@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type organisationInvitationRepository struct {
+	db DBTX
+}
+
+func NewOrganisationInvitationRepository(db DBTX) OrganisationInvitationRepository {
+	return &organisationInvitationRepository{db: db}
+}
+
+func (r *organisationInvitationRepository) Create(invitation *models.OrganisationInvitation) error {
+	return r.db.QueryRowx(
+		"INSERT INTO organisation_invitations (organisation_id, email, role, token_hash, invited_by, expires_at, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9) RETURNING id, uuid",
+		invitation.OrganisationID, invitation.Email, invitation.Role, invitation.TokenHash, invitation.InvitedBy, invitation.ExpiresAt, invitation.CreatedAt, invitation.UpdatedAt, invitation.CreatedBy.String,
+	).StructScan(invitation)
+}
+
+func (r *organisationInvitationRepository) GetByUUID(uuid string) (*models.OrganisationInvitation, error) {
+	var invitation models.OrganisationInvitation
+	err := r.db.Get(
+		&invitation,
+		"SELECT id, uuid, organisation_id, email, role, token_hash, invited_by, expires_at, accepted_at, accepted_by, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM organisation_invitations WHERE uuid = $1 AND deleted_at IS NULL",
+		uuid,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+func (r *organisationInvitationRepository) GetByTokenHash(tokenHash string) (*models.OrganisationInvitation, error) {
+	var invitation models.OrganisationInvitation
+	err := r.db.Get(
+		&invitation,
+		"SELECT id, uuid, organisation_id, email, role, token_hash, invited_by, expires_at, accepted_at, accepted_by, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM organisation_invitations WHERE token_hash = $1 AND deleted_at IS NULL",
+		tokenHash,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// GetPendingByOrganisationID returns invitations that are neither accepted,
+// revoked nor expired.
+func (r *organisationInvitationRepository) GetPendingByOrganisationID(organisationID int) ([]*models.OrganisationInvitation, error) {
+	var invitations []*models.OrganisationInvitation
+	err := r.db.Select(
+		&invitations,
+		"SELECT id, uuid, organisation_id, email, role, token_hash, invited_by, expires_at, accepted_at, accepted_by, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM organisation_invitations WHERE organisation_id = $1 AND accepted_at IS NULL AND expires_at > $2 AND deleted_at IS NULL ORDER BY created_at",
+		organisationID, time.Now(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// MarkAccepted claims a pending invitation. It returns sql.ErrNoRows when the
+// invitation was accepted or revoked in the meantime, so it is used only once.
+func (r *organisationInvitationRepository) MarkAccepted(id int, userID int, acceptedBy string) error {
+	now := time.Now()
+	result, err := r.db.Exec(
+		"UPDATE organisation_invitations SET accepted_at = $1, accepted_by = $2, updated_at = $1, updated_by = $3 WHERE id = $4 AND accepted_at IS NULL AND deleted_at IS NULL",
+		now, userID, acceptedBy, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RevokePendingByEmail revokes every open invitation for email in the organisation.
+func (r *organisationInvitationRepository) RevokePendingByEmail(organisationID int, email string, revokedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE organisation_invitations SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE organisation_id = $3 AND LOWER(email) = LOWER($4) AND accepted_at IS NULL AND deleted_at IS NULL",
+		now, revokedBy, organisationID, email,
+	)
+	return err
+}
+
+func (r *organisationInvitationRepository) Revoke(id int, revokedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE organisation_invitations SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE id = $3",
+		now, revokedBy, id,
+	)
+	return err
+}
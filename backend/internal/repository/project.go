@@ -3,16 +3,14 @@ package repository
 import (
 	"time"
 
-	"github.com/jmoiron/sqlx"
-
 	"github.com/crudboxin/crudbox/internal/models"
 )
 
 type projectRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewProjectRepository(db *sqlx.DB) ProjectRepository {
+func NewProjectRepository(db DBTX) ProjectRepository {
 	return &projectRepository{db: db}
 }
 
@@ -23,11 +21,19 @@ func (r *projectRepository) Create(project *models.Project) error {
 	).StructScan(project)
 }
 
+func (r *projectRepository) Update(project *models.Project) error {
+	_, err := r.db.Exec(
+		"UPDATE projects SET name = $1, code = $2, hostname = $3, organisation_id = $4, updated_by = $5, updated_at = $6 WHERE id = $7",
+		project.Name, project.Code, project.Hostname, project.OrganisationID, project.UpdatedBy.String, project.UpdatedAt, project.ID,
+	)
+	return err
+}
+
 func (r *projectRepository) GetByID(id int) (*models.Project, error) {
 	var project models.Project
 	err := r.db.Get(
 		&project,
-		"SELECT id, uuid, name, code, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE id = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE id = $1 AND deleted_at IS NULL",
 		id,
 	)
 
@@ -42,7 +48,24 @@ func (r *projectRepository) GetByCode(code string) (*models.Project, error) {
 	var project models.Project
 	err := r.db.Get(
 		&project,
-		"SELECT id, uuid, name, code, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE code = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE code = $1 AND deleted_at IS NULL",
+		code,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// GetByCodeIgnoringCase finds projects created before codes had to be lowercase,
+// which cannot be matched exactly against a lowercased host name.
+func (r *projectRepository) GetByCodeIgnoringCase(code string) (*models.Project, error) {
+	var project models.Project
+	err := r.db.Get(
+		&project,
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE LOWER(code) = LOWER($1) AND deleted_at IS NULL ORDER BY id LIMIT 1",
 		code,
 	)
 
@@ -53,11 +76,26 @@ func (r *projectRepository) GetByCode(code string) (*models.Project, error) {
 	return &project, nil
 }
 
+func (r *projectRepository) GetByHostname(hostname string) (*models.Project, error) {
+	var project models.Project
+	err := r.db.Get(
+		&project,
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE hostname = $1 AND deleted_at IS NULL",
+		hostname,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
 func (r *projectRepository) GetByOrganisationID(organisationID int) ([]*models.Project, error) {
 	var projects []*models.Project
 	err := r.db.Select(
 		&projects,
-		"SELECT id, uuid, name, code, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE organisation_id = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE organisation_id = $1 AND deleted_at IS NULL",
 		organisationID,
 	)
 
@@ -72,7 +110,7 @@ func (r *projectRepository) GetByUUID(uuid string) (*models.Project, error) {
 	var project models.Project
 	err := r.db.Get(
 		&project,
-		"SELECT id, uuid, name, code, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE uuid = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE uuid = $1 AND deleted_at IS NULL",
 		uuid,
 	)
 
@@ -83,12 +121,43 @@ func (r *projectRepository) GetByUUID(uuid string) (*models.Project, error) {
 	return &project, nil
 }
 
-func (r *projectRepository) GetByUUIDForUser(uuid string, userID int) (*models.Project, error) {
+func (r *projectRepository) CodeExists(code string) (bool, error) {
+	var count int
+	err := r.db.Get(
+		&count,
+		"SELECT COUNT(*) FROM projects WHERE code = $1",
+		code,
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *projectRepository) DeleteByUUID(projectUUID string, deletedBy string, deletedAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE projects SET hostname = NULL, deleted_at = $1, deleted_by = $2, updated_by = $2, updated_at = $1 WHERE uuid = $3 AND deleted_at IS NULL",
+		deletedAt, deletedBy, projectUUID,
+	)
+	return err
+}
+
+func (r *projectRepository) DeleteByOrganisationID(organisationID int, deletedBy string, deletedAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE projects SET hostname = NULL, deleted_at = $1, deleted_by = $2, updated_by = $2, updated_at = $1 WHERE organisation_id = $3 AND deleted_at IS NULL",
+		deletedAt, deletedBy, organisationID,
+	)
+	return err
+}
+
+func (r *projectRepository) GetDeletedByUUID(uuid string) (*models.Project, error) {
 	var project models.Project
 	err := r.db.Get(
 		&project,
-		"SELECT id, uuid, name, code, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE uuid = $1 AND user_id = $2 AND deleted_at IS NULL",
-		uuid, userID,
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE uuid = $1 AND deleted_at IS NOT NULL",
+		uuid,
 	)
 
 	if err != nil {
@@ -98,11 +167,37 @@ func (r *projectRepository) GetByUUIDForUser(uuid string, userID int) (*models.P
 	return &project, nil
 }
 
-func (r *projectRepository) DeleteByUUID(projectUUID string, userID int) error {
-	now := time.Now()
+// GetDeletedByOrganisationID returns the organisation's deleted projects, most
+// recently deleted first.
+func (r *projectRepository) GetDeletedByOrganisationID(organisationID int) ([]*models.Project, error) {
+	projects := []*models.Project{}
+	err := r.db.Select(
+		&projects,
+		"SELECT id, uuid, name, code, hostname, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM projects WHERE organisation_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC",
+		organisationID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (r *projectRepository) Restore(id int, restoredBy string, restoredAt time.Time) error {
 	_, err := r.db.Exec(
-		"UPDATE projects SET deleted_at = $1, deleted_by = $2, updated_by = $2, updated_at = $1 WHERE uuid = $3",
-		now, userID, projectUUID,
+		"UPDATE projects SET deleted_at = NULL, deleted_by = NULL, updated_by = $1, updated_at = $2 WHERE id = $3",
+		restoredBy, restoredAt, id,
 	)
 	return err
 }
+
+// PurgeDeletedBefore permanently removes projects deleted before cutoff. Their
+// endpoints must be purged first.
+func (r *projectRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM projects WHERE deleted_at < $1", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectBranchRepository struct {
+	db DBTX
+}
+
+func NewProjectBranchRepository(db DBTX) ProjectBranchRepository {
+	return &projectBranchRepository{db: db}
+}
+
+func (r *projectBranchRepository) Create(branch *models.ProjectBranch) error {
+	return r.db.QueryRowx(
+		"INSERT INTO project_branches (project_id, parent_project_id, created_at, created_by) VALUES ($1, $2, $3, $4) RETURNING id, uuid",
+		branch.ProjectID, branch.ParentProjectID, branch.CreatedAt, branch.CreatedBy.String,
+	).StructScan(branch)
+}
+
+func (r *projectBranchRepository) CreateBaseEndpoint(endpoint *models.ProjectBranchEndpoint) error {
+	return r.db.QueryRowx(
+		"INSERT INTO project_branch_base_endpoints (branch_id, method, path, response_body, response_status, response_headers) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		endpoint.BranchID, endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders,
+	).StructScan(endpoint)
+}
+
+// DeleteBaseEndpoints removes the branch point of a branch so a new one can be
+// recorded after a merge.
+func (r *projectBranchRepository) DeleteBaseEndpoints(branchID int) error {
+	_, err := r.db.Exec("DELETE FROM project_branch_base_endpoints WHERE branch_id = $1", branchID)
+	return err
+}
+
+func (r *projectBranchRepository) GetByProjectID(projectID int) (*models.ProjectBranch, error) {
+	var branch models.ProjectBranch
+	err := r.db.Get(
+		&branch,
+		"SELECT id, uuid, project_id, parent_project_id, created_at, created_by, merged_at, merged_by FROM project_branches WHERE project_id = $1",
+		projectID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &branch, nil
+}
+
+// GetByParentProjectID returns the branches of a project whose branch project
+// has not been deleted, oldest first.
+func (r *projectBranchRepository) GetByParentProjectID(parentProjectID int) ([]*models.ProjectBranch, error) {
+	branches := []*models.ProjectBranch{}
+	err := r.db.Select(
+		&branches,
+		"SELECT b.id, b.uuid, b.project_id, b.parent_project_id, b.created_at, b.created_by, b.merged_at, b.merged_by FROM project_branches b JOIN projects p ON p.id = b.project_id WHERE b.parent_project_id = $1 AND p.deleted_at IS NULL ORDER BY b.id",
+		parentProjectID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return branches, nil
+}
+
+func (r *projectBranchRepository) GetBaseEndpoints(branchID int) ([]*models.ProjectBranchEndpoint, error) {
+	endpoints := []*models.ProjectBranchEndpoint{}
+	err := r.db.Select(
+		&endpoints,
+		"SELECT id, branch_id, method, path, response_body, response_status, response_headers FROM project_branch_base_endpoints WHERE branch_id = $1 ORDER BY path, method",
+		branchID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+func (r *projectBranchRepository) MarkMerged(id int, mergedBy string, mergedAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE project_branches SET merged_at = $1, merged_by = $2 WHERE id = $3",
+		mergedAt, mergedBy, id,
+	)
+	return err
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectCodeRedirectRepository struct {
+	db DBTX
+}
+
+func NewProjectCodeRedirectRepository(db DBTX) ProjectCodeRedirectRepository {
+	return &projectCodeRedirectRepository{db: db}
+}
+
+func (r *projectCodeRedirectRepository) Create(redirect *models.ProjectCodeRedirect) error {
+	return r.db.QueryRowx(
+		"INSERT INTO project_code_redirects (code, project_id, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $5) RETURNING id, uuid",
+		redirect.Code, redirect.ProjectID, redirect.CreatedAt, redirect.UpdatedAt, redirect.CreatedBy.String,
+	).StructScan(redirect)
+}
+
+func (r *projectCodeRedirectRepository) GetByCode(code string) (*models.ProjectCodeRedirect, error) {
+	var redirect models.ProjectCodeRedirect
+	err := r.db.Get(
+		&redirect,
+		"SELECT id, uuid, code, project_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM project_code_redirects WHERE code = $1",
+		code,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &redirect, nil
+}
+
+// Redirects are removed outright rather than soft-deleted so their codes can be
+// reused by other projects.
+func (r *projectCodeRedirectRepository) DeleteByCode(code string) error {
+	_, err := r.db.Exec("DELETE FROM project_code_redirects WHERE code = $1", code)
+	return err
+}
+
+func (r *projectCodeRedirectRepository) DeleteByProjectID(projectID int) error {
+	_, err := r.db.Exec("DELETE FROM project_code_redirects WHERE project_id = $1", projectID)
+	return err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectEnvironmentRepository struct {
+	db DBTX
+}
+
+func NewProjectEnvironmentRepository(db DBTX) ProjectEnvironmentRepository {
+	return &projectEnvironmentRepository{db: db}
+}
+
+func (r *projectEnvironmentRepository) Create(environment *models.ProjectEnvironment) error {
+	return r.db.QueryRowx(
+		"INSERT INTO project_environments (project_id, name, variables, is_default, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $7) RETURNING id, uuid",
+		environment.ProjectID, environment.Name, environment.Variables, environment.IsDefault, environment.CreatedAt, environment.UpdatedAt, environment.CreatedBy.String,
+	).StructScan(environment)
+}
+
+func (r *projectEnvironmentRepository) Update(environment *models.ProjectEnvironment) error {
+	_, err := r.db.Exec(
+		"UPDATE project_environments SET name = $1, variables = $2, is_default = $3, updated_at = $4, updated_by = $5 WHERE id = $6",
+		environment.Name, environment.Variables, environment.IsDefault, environment.UpdatedAt, environment.UpdatedBy.String, environment.ID,
+	)
+	return err
+}
+
+// ClearDefault unsets the default flag on every environment of the project.
+func (r *projectEnvironmentRepository) ClearDefault(projectID int) error {
+	_, err := r.db.Exec(
+		"UPDATE project_environments SET is_default = FALSE WHERE project_id = $1 AND is_default",
+		projectID,
+	)
+	return err
+}
+
+func (r *projectEnvironmentRepository) GetByProjectID(projectID int) ([]*models.ProjectEnvironment, error) {
+	environments := []*models.ProjectEnvironment{}
+	err := r.db.Select(
+		&environments,
+		"SELECT id, uuid, project_id, name, variables, is_default, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM project_environments WHERE project_id = $1 AND deleted_at IS NULL ORDER BY name",
+		projectID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return environments, nil
+}
+
+func (r *projectEnvironmentRepository) GetByProjectIDAndName(projectID int, name string) (*models.ProjectEnvironment, error) {
+	var environment models.ProjectEnvironment
+	err := r.db.Get(
+		&environment,
+		"SELECT id, uuid, project_id, name, variables, is_default, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM project_environments WHERE project_id = $1 AND name = $2 AND deleted_at IS NULL",
+		projectID, name,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &environment, nil
+}
+
+func (r *projectEnvironmentRepository) GetDefault(projectID int) (*models.ProjectEnvironment, error) {
+	var environment models.ProjectEnvironment
+	err := r.db.Get(
+		&environment,
+		"SELECT id, uuid, project_id, name, variables, is_default, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM project_environments WHERE project_id = $1 AND is_default AND deleted_at IS NULL",
+		projectID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &environment, nil
+}
+
+func (r *projectEnvironmentRepository) Delete(id int, deletedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE project_environments SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2, is_default = FALSE WHERE id = $3 AND deleted_at IS NULL",
+		now, deletedBy, id,
+	)
+	return err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectSyncRepository struct {
+	db DBTX
+}
+
+func NewProjectSyncRepository(db DBTX) ProjectSyncRepository {
+	return &projectSyncRepository{db: db}
+}
+
+// Save records a sync of the project, replacing the time and user of any
+// earlier one while keeping its id.
+func (r *projectSyncRepository) Save(sync *models.ProjectSync) error {
+	return r.db.QueryRowx(
+		`INSERT INTO project_syncs (project_id, synced_at, synced_by) VALUES ($1, $2, $3)
+         ON CONFLICT (project_id) DO UPDATE SET synced_at = EXCLUDED.synced_at, synced_by = EXCLUDED.synced_by
+         RETURNING id`,
+		sync.ProjectID, sync.SyncedAt, sync.SyncedBy.String,
+	).StructScan(sync)
+}
+
+func (r *projectSyncRepository) CreateEndpoint(endpoint *models.ProjectSyncEndpoint) error {
+	return r.db.QueryRowx(
+		"INSERT INTO project_sync_endpoints (sync_id, method, path, response_body, response_status, response_headers) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		endpoint.SyncID, endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders,
+	).StructScan(endpoint)
+}
+
+// DeleteEndpoints removes the endpoints recorded by a sync so the next sync can
+// record its own.
+func (r *projectSyncRepository) DeleteEndpoints(syncID int) error {
+	_, err := r.db.Exec("DELETE FROM project_sync_endpoints WHERE sync_id = $1", syncID)
+	return err
+}
+
+func (r *projectSyncRepository) GetByProjectID(projectID int) (*models.ProjectSync, error) {
+	var sync models.ProjectSync
+	err := r.db.Get(
+		&sync,
+		"SELECT id, project_id, synced_at, synced_by FROM project_syncs WHERE project_id = $1",
+		projectID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &sync, nil
+}
+
+func (r *projectSyncRepository) GetEndpoints(syncID int) ([]*models.ProjectSyncEndpoint, error) {
+	endpoints := []*models.ProjectSyncEndpoint{}
+	err := r.db.Select(
+		&endpoints,
+		"SELECT id, sync_id, method, path, response_body, response_status, response_headers FROM project_sync_endpoints WHERE sync_id = $1 ORDER BY path, method",
+		syncID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
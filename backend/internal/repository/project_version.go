@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type projectVersionRepository struct {
+	db DBTX
+}
+
+func NewProjectVersionRepository(db DBTX) ProjectVersionRepository {
+	return &projectVersionRepository{db: db}
+}
+
+func (r *projectVersionRepository) Create(version *models.ProjectVersion) error {
+	return r.db.QueryRowx(
+		"INSERT INTO project_versions (project_id, name, description, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $6) RETURNING id, uuid",
+		version.ProjectID, version.Name, version.Description, version.CreatedAt, version.UpdatedAt, version.CreatedBy.String,
+	).StructScan(version)
+}
+
+func (r *projectVersionRepository) CreateEndpoint(endpoint *models.ProjectVersionEndpoint) error {
+	return r.db.QueryRowx(
+		"INSERT INTO project_version_endpoints (version_id, method, path, response_body, response_status, response_headers) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		endpoint.VersionID, endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders,
+	).StructScan(endpoint)
+}
+
+// GetByProjectID returns the project's versions, newest first.
+func (r *projectVersionRepository) GetByProjectID(projectID int) ([]*models.ProjectVersion, error) {
+	versions := []*models.ProjectVersion{}
+	err := r.db.Select(
+		&versions,
+		"SELECT v.id, v.uuid, v.project_id, v.name, v.description, (SELECT COUNT(*) FROM project_version_endpoints e WHERE e.version_id = v.id) AS endpoint_count, v.created_at, v.updated_at, v.created_by, v.updated_by, v.deleted_at, v.deleted_by FROM project_versions v WHERE v.project_id = $1 AND v.deleted_at IS NULL ORDER BY v.id DESC",
+		projectID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (r *projectVersionRepository) GetByProjectIDAndName(projectID int, name string) (*models.ProjectVersion, error) {
+	var version models.ProjectVersion
+	err := r.db.Get(
+		&version,
+		"SELECT v.id, v.uuid, v.project_id, v.name, v.description, (SELECT COUNT(*) FROM project_version_endpoints e WHERE e.version_id = v.id) AS endpoint_count, v.created_at, v.updated_at, v.created_by, v.updated_by, v.deleted_at, v.deleted_by FROM project_versions v WHERE v.project_id = $1 AND v.name = $2 AND v.deleted_at IS NULL",
+		projectID, name,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+func (r *projectVersionRepository) GetEndpoints(versionID int) ([]*models.ProjectVersionEndpoint, error) {
+	endpoints := []*models.ProjectVersionEndpoint{}
+	err := r.db.Select(
+		&endpoints,
+		"SELECT id, version_id, method, path, response_body, response_status, response_headers FROM project_version_endpoints WHERE version_id = $1 ORDER BY path, method",
+		versionID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+func (r *projectVersionRepository) GetEndpoint(versionID int, path, method string) (*models.ProjectVersionEndpoint, error) {
+	var endpoint models.ProjectVersionEndpoint
+	err := r.db.Get(
+		&endpoint,
+		"SELECT id, version_id, method, path, response_body, response_status, response_headers FROM project_version_endpoints WHERE version_id = $1 AND path = $2 AND method = $3",
+		versionID, path, method,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &endpoint, nil
+}
+
+func (r *projectVersionRepository) Delete(id int, deletedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE project_versions SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE id = $3 AND deleted_at IS NULL",
+		now, deletedBy, id,
+	)
+	return err
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+const requestLogColumns = "id, project_id, method, path, query, version, environment, matched, endpoint_uuid, response_status, ip_address, user_agent, created_at"
+
+type requestLogRepository struct {
+	db DBTX
+}
+
+func NewRequestLogRepository(db DBTX) RequestLogRepository {
+	return &requestLogRepository{db: db}
+}
+
+func (r *requestLogRepository) Create(entry *models.RequestLog) error {
+	return r.db.QueryRowx(
+		"INSERT INTO request_logs (project_id, method, path, query, version, environment, matched, endpoint_uuid, response_status, ip_address, user_agent, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id",
+		entry.ProjectID, entry.Method, entry.Path, entry.Query, entry.Version, entry.Environment, entry.Matched, entry.EndpointUUID, entry.ResponseStatus, entry.IPAddress, entry.UserAgent, entry.CreatedAt,
+	).Scan(&entry.ID)
+}
+
+// List returns the entries of a project selected by filter, oldest first.
+func (r *requestLogRepository) List(filter RequestLogFilter) ([]*models.RequestLog, error) {
+	entries := []*models.RequestLog{}
+	var err error
+	if filter.AfterID != nil {
+		err = r.db.Select(
+			&entries,
+			"SELECT "+requestLogColumns+" FROM request_logs WHERE project_id = $1 AND id > $2 ORDER BY id LIMIT $3",
+			filter.ProjectID, *filter.AfterID, filter.Limit,
+		)
+	} else {
+		err = r.db.Select(
+			&entries,
+			"SELECT "+requestLogColumns+" FROM (SELECT "+requestLogColumns+" FROM request_logs WHERE project_id = $1 ORDER BY id DESC LIMIT $2) AS newest ORDER BY id",
+			filter.ProjectID, filter.Limit,
+		)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *requestLogRepository) Trim(projectID, keep int) error {
+	_, err := r.db.Exec(
+		"DELETE FROM request_logs WHERE project_id = $1 AND id <= (SELECT id FROM request_logs WHERE project_id = $1 ORDER BY id DESC LIMIT 1 OFFSET $2)",
+		projectID, keep,
+	)
+	return err
+}
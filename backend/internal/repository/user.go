@@ -2,23 +2,23 @@ package repository
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/crudboxin/crudbox/internal/models"
-	"github.com/jmoiron/sqlx"
 )
 
 type userRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewUserRepository(db *sqlx.DB) UserRepository {
+func NewUserRepository(db DBTX) UserRepository {
 	return &userRepository{db: db}
 }
 
 func (r *userRepository) Create(user *models.User) error {
 	err := r.db.QueryRowx(
-		"INSERT INTO users (email, password, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $5) RETURNING id, uuid",
-		user.Email, user.Password, user.CreatedAt, user.UpdatedAt, user.CreatedBy,
+		"INSERT INTO users (email, password, email_verified_at, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $6) RETURNING id, uuid",
+		user.Email, user.Password, user.EmailVerifiedAt, user.CreatedAt, user.UpdatedAt, user.CreatedBy,
 	).StructScan(user)
 
 	if err != nil {
@@ -35,7 +35,7 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	var user models.User
 	err := r.db.Get(
 		&user,
-		"SELECT id, uuid, email, password, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM users WHERE email = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, email, password, email_verified_at, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM users WHERE email = $1 AND deleted_at IS NULL",
 		email,
 	)
 
@@ -50,7 +50,7 @@ func (r *userRepository) GetByID(id int) (*models.User, error) {
 	var user models.User
 	err := r.db.Get(
 		&user,
-		"SELECT id, uuid, email, password, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM users WHERE id = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, email, password, email_verified_at, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM users WHERE id = $1 AND deleted_at IS NULL",
 		id,
 	)
 
@@ -65,7 +65,7 @@ func (r *userRepository) GetByUUID(uuid string) (*models.User, error) {
 	var user models.User
 	err := r.db.Get(
 		&user,
-		"SELECT id, uuid, email, password, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM users WHERE uuid = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, email, password, email_verified_at, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM users WHERE uuid = $1 AND deleted_at IS NULL",
 		uuid,
 	)
 
@@ -75,3 +75,19 @@ func (r *userRepository) GetByUUID(uuid string) (*models.User, error) {
 
 	return &user, nil
 }
+
+func (r *userRepository) UpdatePassword(id int, password, updatedBy string) error {
+	_, err := r.db.Exec(
+		"UPDATE users SET password = $1, updated_at = $2, updated_by = $3 WHERE id = $4 AND deleted_at IS NULL",
+		password, time.Now(), updatedBy, id,
+	)
+	return err
+}
+
+func (r *userRepository) MarkEmailVerified(id int, verifiedAt time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE users SET email_verified_at = $1, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL",
+		verifiedAt, id,
+	)
+	return err
+}
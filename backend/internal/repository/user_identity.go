@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userIdentityRepository struct {
+	db DBTX
+}
+
+func NewUserIdentityRepository(db DBTX) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Create(identity *models.UserIdentity) error {
+	return r.db.QueryRowx(
+		"INSERT INTO user_identities (user_id, issuer, subject, email, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $7) RETURNING id, uuid",
+		identity.UserID, identity.Issuer, identity.Subject, identity.Email, identity.CreatedAt, identity.UpdatedAt, identity.CreatedBy.String,
+	).StructScan(identity)
+}
+
+func (r *userIdentityRepository) GetByIssuerAndSubject(issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.Get(
+		&identity,
+		"SELECT id, uuid, user_id, issuer, subject, email, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_identities WHERE issuer = $1 AND subject = $2 AND deleted_at IS NULL",
+		issuer, subject,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
@@ -1,22 +1,27 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/crudboxin/crudbox/internal/models"
-	"github.com/jmoiron/sqlx"
 )
 
 type userOrganisationMappingRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewUserOrganisationMappingRepository(db *sqlx.DB) UserOrganisationMappingRepository {
+func NewUserOrganisationMappingRepository(db DBTX) UserOrganisationMappingRepository {
 	return &userOrganisationMappingRepository{db: db}
 }
 
 func (r *userOrganisationMappingRepository) Create(mapping *models.UserOrganisationMapping) error {
+	// A member who was removed earlier keeps a soft-deleted row, which is revived
+	// instead of violating the unique (user_id, organisation_id) constraint.
 	err := r.db.QueryRowx(
-		"INSERT INTO user_organisation_mapping (user_id, organisation_id, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $5) RETURNING id, uuid",
-		mapping.UserID, mapping.OrganisationID, mapping.CreatedAt, mapping.UpdatedAt, mapping.CreatedBy,
+		`INSERT INTO user_organisation_mapping (user_id, organisation_id, role, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $6)
+         ON CONFLICT (user_id, organisation_id) DO UPDATE SET role = EXCLUDED.role, deleted_at = NULL, deleted_by = NULL, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, created_by = EXCLUDED.created_by, updated_by = EXCLUDED.updated_by
+         RETURNING id, uuid`,
+		mapping.UserID, mapping.OrganisationID, mapping.Role, mapping.CreatedAt, mapping.UpdatedAt, mapping.CreatedBy,
 	).StructScan(mapping)
 
 	if err != nil {
@@ -30,7 +35,7 @@ func (r *userOrganisationMappingRepository) GetByUserID(userID int) ([]*models.U
 	var mappings []*models.UserOrganisationMapping
 	err := r.db.Select(
 		&mappings,
-		"SELECT id, uuid, user_id, organisation_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_organisation_mapping WHERE user_id = $1 AND deleted_at IS NULL",
+		"SELECT id, uuid, user_id, organisation_id, role, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_organisation_mapping WHERE user_id = $1 AND deleted_at IS NULL",
 		userID,
 	)
 
@@ -41,6 +46,45 @@ func (r *userOrganisationMappingRepository) GetByUserID(userID int) ([]*models.U
 	return mappings, nil
 }
 
+func (r *userOrganisationMappingRepository) GetByOrganisationID(orgID int) ([]*models.UserOrganisationMapping, error) {
+	var mappings []*models.UserOrganisationMapping
+	err := r.db.Select(
+		&mappings,
+		"SELECT id, uuid, user_id, organisation_id, role, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_organisation_mapping WHERE organisation_id = $1 AND deleted_at IS NULL ORDER BY created_at",
+		orgID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+func (r *userOrganisationMappingRepository) GetByUserIDAndOrganisationID(userID, orgID int) (*models.UserOrganisationMapping, error) {
+	var mapping models.UserOrganisationMapping
+	err := r.db.Get(
+		&mapping,
+		"SELECT id, uuid, user_id, organisation_id, role, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_organisation_mapping WHERE user_id = $1 AND organisation_id = $2 AND deleted_at IS NULL",
+		userID, orgID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapping, nil
+}
+
+func (r *userOrganisationMappingRepository) UpdateRole(userID, orgID int, role string, updatedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE user_organisation_mapping SET role = $1, updated_at = $2, updated_by = $3 WHERE user_id = $4 AND organisation_id = $5 AND deleted_at IS NULL",
+		role, now, updatedBy, userID, orgID,
+	)
+	return err
+}
+
 func (r *userOrganisationMappingRepository) CheckUserInOrganisation(userID, orgID int) (bool, error) {
 	var count int
 	err := r.db.Get(
@@ -55,3 +99,21 @@ func (r *userOrganisationMappingRepository) CheckUserInOrganisation(userID, orgI
 
 	return count > 0, nil
 }
+
+func (r *userOrganisationMappingRepository) Delete(userID, orgID int, deletedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE user_organisation_mapping SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE user_id = $3 AND organisation_id = $4 AND deleted_at IS NULL",
+		now, deletedBy, userID, orgID,
+	)
+	return err
+}
+
+func (r *userOrganisationMappingRepository) DeleteByOrganisationID(orgID int, deletedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE user_organisation_mapping SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE organisation_id = $3 AND deleted_at IS NULL",
+		now, deletedBy, orgID,
+	)
+	return err
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userSessionRepository struct {
+	db DBTX
+}
+
+func NewUserSessionRepository(db DBTX) UserSessionRepository {
+	return &userSessionRepository{db: db}
+}
+
+func (r *userSessionRepository) Create(session *models.UserSession) error {
+	return r.db.QueryRowx(
+		"INSERT INTO user_sessions (user_id, refresh_token_hash, expires_at, last_used_at, ip_address, user_agent, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9) RETURNING id, uuid",
+		session.UserID, session.RefreshTokenHash, session.ExpiresAt, session.LastUsedAt, session.IPAddress, session.UserAgent, session.CreatedAt, session.UpdatedAt, session.CreatedBy.String,
+	).StructScan(session)
+}
+
+func (r *userSessionRepository) GetByUUID(uuid string) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.db.Get(
+		&session,
+		"SELECT id, uuid, user_id, refresh_token_hash, previous_refresh_token_hash, expires_at, last_used_at, ip_address, user_agent, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_sessions WHERE uuid = $1 AND deleted_at IS NULL",
+		uuid,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *userSessionRepository) GetByRefreshTokenHash(tokenHash string) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.db.Get(
+		&session,
+		"SELECT id, uuid, user_id, refresh_token_hash, previous_refresh_token_hash, expires_at, last_used_at, ip_address, user_agent, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_sessions WHERE refresh_token_hash = $1 AND deleted_at IS NULL",
+		tokenHash,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *userSessionRepository) GetByPreviousRefreshTokenHash(tokenHash string) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.db.Get(
+		&session,
+		"SELECT id, uuid, user_id, refresh_token_hash, previous_refresh_token_hash, expires_at, last_used_at, ip_address, user_agent, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_sessions WHERE previous_refresh_token_hash = $1 AND deleted_at IS NULL",
+		tokenHash,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Rotate replaces the session's refresh token, remembering the previous one so
+// that its reuse can be detected. It only succeeds while oldHash is still the
+// current token of a live session and returns sql.ErrNoRows otherwise, so of
+// two concurrent refreshes with the same token only one rotates.
+func (r *userSessionRepository) Rotate(id int, oldHash, refreshTokenHash string, expiresAt time.Time) error {
+	now := time.Now()
+	result, err := r.db.Exec(
+		"UPDATE user_sessions SET previous_refresh_token_hash = refresh_token_hash, refresh_token_hash = $1, expires_at = $2, last_used_at = $3, updated_at = $3 WHERE id = $4 AND refresh_token_hash = $5 AND deleted_at IS NULL",
+		refreshTokenHash, expiresAt, now, id, oldHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	rotated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rotated == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *userSessionRepository) Revoke(id int, revokedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE user_sessions SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE id = $3 AND deleted_at IS NULL",
+		now, revokedBy, id,
+	)
+	return err
+}
+
+func (r *userSessionRepository) RevokeByUserID(userID int, revokedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE user_sessions SET deleted_at = $1, deleted_by = $2, updated_at = $1, updated_by = $2 WHERE user_id = $3 AND deleted_at IS NULL",
+		now, revokedBy, userID,
+	)
+	return err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/models"
+)
+
+type userTokenRepository struct {
+	db DBTX
+}
+
+func NewUserTokenRepository(db DBTX) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(token *models.UserToken) error {
+	return r.db.QueryRowx(
+		"INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $7) RETURNING id, uuid",
+		token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt, token.UpdatedAt, token.CreatedBy.String,
+	).StructScan(token)
+}
+
+// GetByTokenHash returns the unused token with the given hash and purpose.
+// Expiry is left to the caller.
+func (r *userTokenRepository) GetByTokenHash(tokenHash, purpose string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.Get(
+		&token,
+		"SELECT id, uuid, user_id, purpose, token_hash, expires_at, used_at, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by FROM user_tokens WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND deleted_at IS NULL",
+		tokenHash, purpose,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed claims an unused token. It returns sql.ErrNoRows when the token was
+// already used, so of two concurrent requests with the same link only one wins.
+func (r *userTokenRepository) MarkUsed(id int, usedAt time.Time) error {
+	result, err := r.db.Exec(
+		"UPDATE user_tokens SET used_at = $1, updated_at = $1 WHERE id = $2 AND used_at IS NULL",
+		usedAt, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// InvalidateByUserID retires every outstanding token of the user for purpose,
+// so only the most recently mailed link works.
+func (r *userTokenRepository) InvalidateByUserID(userID int, purpose string) error {
+	now := time.Now()
+	_, err := r.db.Exec(
+		"UPDATE user_tokens SET deleted_at = $1, deleted_by = 'system', updated_at = $1 WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL AND deleted_at IS NULL",
+		now, userID, purpose,
+	)
+	return err
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+// API token scopes. Read-only tokens may only issue safe (GET/HEAD) requests.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// lastUsedResolution limits how often last_used_at is written for a busy token.
+const lastUsedResolution = time.Minute
+
+var ErrInvalidAPIToken = errors.New("invalid or expired api token")
+
+type apiTokenService struct {
+	repo     repository.APITokenRepository
+	userRepo repository.UserRepository
+}
+
+func NewAPITokenService(repo repository.APITokenRepository, userRepo repository.UserRepository) APITokenService {
+	return &apiTokenService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *apiTokenService) CreateToken(req *contracts.CreateAPITokenRequest, userID int) (*contracts.CreatedAPIToken, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, _, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	token := contracts.APITokenPrefix + secret
+
+	now := time.Now()
+	dbToken := &models.APIToken{
+		UserID:      userID,
+		Name:        req.Name,
+		TokenPrefix: token[:len(contracts.APITokenPrefix)+8],
+		TokenHash:   hashToken(token),
+		Scopes:      strings.Join(normalizeScopes(req.Scopes), ","),
+		ExpiresAt:   req.ExpiresAt,
+		Base: models.Base{
+			CreatedAt: &now,
+			UpdatedAt: &now,
+			CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+			UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+		},
+	}
+
+	if err := s.repo.Create(dbToken); err != nil {
+		return nil, err
+	}
+
+	return &contracts.CreatedAPIToken{
+		APIToken: toAPITokenContract(dbToken),
+		Token:    token,
+	}, nil
+}
+
+func (s *apiTokenService) GetTokens(userID int) ([]*contracts.APIToken, error) {
+	tokens, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*contracts.APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		contract := toAPITokenContract(token)
+		result = append(result, &contract)
+	}
+
+	return result, nil
+}
+
+func (s *apiTokenService) RevokeToken(tokenUUID string, userID int) error {
+	token, err := s.repo.GetByUUIDForUser(tokenUUID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("token not found")
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Revoke(token.ID, user.UUID)
+}
+
+// AuthenticateAPIToken resolves a raw token presented by a client to its user,
+// its UUID and its scopes. It satisfies middleware.APITokenAuthenticator.
+func (s *apiTokenService) AuthenticateAPIToken(rawToken string) (int, string, string, []string, error) {
+	token, err := s.repo.GetByTokenHash(hashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", "", nil, ErrInvalidAPIToken
+		}
+		return 0, "", "", nil, err
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return 0, "", "", nil, ErrInvalidAPIToken
+	}
+
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", "", nil, ErrInvalidAPIToken
+		}
+		return 0, "", "", nil, err
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.UpdateLastUsed(token.ID, now); err != nil {
+			return 0, "", "", nil, err
+		}
+	}
+
+	return user.ID, user.UUID, token.UUID, strings.Split(token.Scopes, ","), nil
+}
+
+// normalizeScopes removes duplicates and stores scopes in a fixed order.
+func normalizeScopes(scopes []string) []string {
+	var result []string
+	for _, scope := range []string{ScopeRead, ScopeWrite} {
+		if slices.Contains(scopes, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+func toAPITokenContract(token *models.APIToken) contracts.APIToken {
+	return contracts.APIToken{
+		UUID:        token.UUID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      strings.Split(token.Scopes, ","),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+// Actions and entity types recorded in the audit log.
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+
+	AuditEntityOrganisation = "organisation"
+	AuditEntityProject      = "project"
+	AuditEntityEndpoint     = "endpoint"
+	AuditEntityVersion      = "version"
+	AuditEntityEnvironment  = "environment"
+	AuditEntityMember       = "member"
+)
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 200
+)
+
+var ErrInvalidAuditCursor = errors.New("invalid audit log cursor")
+
+// auditFields is the state of an entity as shown in the audit log. Only
+// configuration users can change is included, not bookkeeping columns.
+type auditFields map[string]any
+
+func organisationAuditFields(org *models.Organisation, ownerUUID string) auditFields {
+	return auditFields{
+		"name":       org.Name,
+		"owner_uuid": ownerUUID,
+	}
+}
+
+func memberAuditFields(member *models.User, role string) auditFields {
+	return auditFields{
+		"email": member.Email,
+		"role":  role,
+	}
+}
+
+func projectAuditFields(project *models.Project, orgUUID string) auditFields {
+	return auditFields{
+		"name":              project.Name,
+		"code":              project.Code,
+		"hostname":          project.Hostname.String,
+		"organisation_uuid": orgUUID,
+	}
+}
+
+func endpointAuditFields(endpoint *models.Endpoint) auditFields {
+	return auditFields{
+		"method":           endpoint.Method,
+		"path":             endpoint.Path,
+		"response_body":    endpoint.ResponseBody,
+		"response_status":  endpoint.ResponseStatus,
+		"response_headers": endpoint.ResponseHeaders,
+	}
+}
+
+// diffAuditFields returns the fields whose value differs between before and
+// after. A nil before describes a creation and a nil after a deletion.
+func diffAuditFields(before, after auditFields) map[string]contracts.AuditChange {
+	changes := make(map[string]contracts.AuditChange)
+	for field, value := range before {
+		if next, ok := after[field]; !ok || next != value {
+			changes[field] = contracts.AuditChange{Before: value, After: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = contracts.AuditChange{Before: nil, After: value}
+		}
+	}
+	return changes
+}
+
+// auditEvent describes one change. ProjectUUID is set for projects and their
+// endpoints so the log can be filtered by project.
+type auditEvent struct {
+	OrganisationID int
+	ProjectUUID    string
+	Action         string
+	EntityType     string
+	EntityUUID     string
+	Before         auditFields
+	After          auditFields
+}
+
+// auditor appends to the audit log. Services create it with the repositories
+// of the transaction making the change, so a change and its entry are committed
+// together or not at all.
+type auditor struct {
+	repo repository.AuditLogRepository
+}
+
+// record writes event on behalf of actor. Updates that change nothing visible
+// are not recorded.
+func (a auditor) record(actor *models.User, client contracts.ClientInfo, event auditEvent) error {
+	changes := diffAuditFields(event.Before, event.After)
+	if event.Action == AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	return a.repo.Create(&models.AuditLog{
+		OrganisationID: event.OrganisationID,
+		ProjectUUID:    sql.NullString{String: event.ProjectUUID, Valid: event.ProjectUUID != ""},
+		ActorUUID:      actor.UUID,
+		ActorEmail:     actor.Email,
+		Action:         event.Action,
+		EntityType:     event.EntityType,
+		EntityUUID:     event.EntityUUID,
+		Changes:        string(encoded),
+		IPAddress:      sql.NullString{String: client.IPAddress, Valid: client.IPAddress != ""},
+		CreatedAt:      time.Now(),
+	})
+}
+
+// GetAuditLog lists the changes made within an organisation, newest first, a
+// page at a time going back from the cursor. Every member can see who changed
+// what; the IP address a change came from needs manage rights.
+func (s *organisationService) GetAuditLog(orgUUID string, query *contracts.AuditLogQuery, userID int) (*contracts.AuditLogPage, error) {
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+	showIPAddresses := s.auth.authorize(userID, org.ID, ActionManage) == nil
+
+	filter := repository.AuditLogFilter{
+		OrganisationID: org.ID,
+		ProjectUUID:    query.ProjectUUID,
+		EntityType:     query.EntityType,
+		EntityUUID:     query.EntityUUID,
+		ActorUUID:      query.ActorUUID,
+		Action:         query.Action,
+		Since:          query.Since,
+		Until:          query.Until,
+		Limit:          defaultAuditLogLimit,
+	}
+	if query.Limit > 0 {
+		filter.Limit = min(query.Limit, maxAuditLogLimit)
+	}
+	if query.Cursor != "" {
+		beforeID, err := strconv.Atoi(query.Cursor)
+		if err != nil || beforeID <= 0 {
+			return nil, ErrInvalidAuditCursor
+		}
+		filter.BeforeID = beforeID
+	}
+
+	entries, err := s.auditRepo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &contracts.AuditLogPage{Entries: make([]*contracts.AuditEntry, 0, len(entries))}
+	for _, entry := range entries {
+		var changes map[string]contracts.AuditChange
+		if err := json.Unmarshal([]byte(entry.Changes), &changes); err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry %s: %w", entry.UUID, err)
+		}
+
+		auditEntry := &contracts.AuditEntry{
+			UUID:        entry.UUID,
+			Action:      entry.Action,
+			EntityType:  entry.EntityType,
+			EntityUUID:  entry.EntityUUID,
+			ProjectUUID: entry.ProjectUUID.String,
+			ActorUUID:   entry.ActorUUID,
+			ActorEmail:  entry.ActorEmail,
+			Changes:     changes,
+			CreatedAt:   entry.CreatedAt,
+		}
+		if showIPAddresses {
+			auditEntry.IPAddress = entry.IPAddress.String
+		}
+		page.Entries = append(page.Entries, auditEntry)
+	}
+
+	if len(entries) == filter.Limit {
+		page.NextCursor = strconv.Itoa(entries[len(entries)-1].ID)
+	}
+
+	return page, nil
+}
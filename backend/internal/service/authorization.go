@@ -0,0 +1,102 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+// Roles a member can hold within an organisation, from most to least privileged.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+var roleRank = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+	RoleOwner:  4,
+}
+
+// Action is something a member can do inside an organisation. Every action
+// requires a minimum role.
+type Action int
+
+const (
+	// ActionView covers reading projects, endpoints and members.
+	ActionView Action = iota
+	// ActionEdit covers creating and changing projects and endpoints.
+	ActionEdit
+	// ActionManage covers deleting projects and managing members and invitations.
+	ActionManage
+	// ActionOwn covers changes only the owner may make to the organisation itself.
+	ActionOwn
+)
+
+var actionMinimumRole = map[Action]string{
+	ActionView:   RoleViewer,
+	ActionEdit:   RoleEditor,
+	ActionManage: RoleAdmin,
+	ActionOwn:    RoleOwner,
+}
+
+var (
+	ErrNotMember               = errors.New("user does not belong to the specified organisation")
+	ErrInsufficientPermissions = errors.New("insufficient permissions")
+	ErrInvalidRole             = errors.New("invalid role")
+)
+
+func isValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// authorizer is the single place where organisation roles are checked. Services
+// resolve the organisation a resource belongs to and ask it whether the user may
+// perform an action there.
+type authorizer struct {
+	userOrgRepo repository.UserOrganisationMappingRepository
+}
+
+// role returns the role of userID in the organisation, or ErrNotMember.
+func (a authorizer) role(userID, orgID int) (string, error) {
+	mapping, err := a.userOrgRepo.GetByUserIDAndOrganisationID(userID, orgID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotMember
+		}
+		return "", err
+	}
+	return mapping.Role, nil
+}
+
+// authorize returns ErrNotMember if userID is not part of the organisation and
+// ErrInsufficientPermissions if their role does not allow action.
+func (a authorizer) authorize(userID, orgID int, action Action) error {
+	role, err := a.role(userID, orgID)
+	if err != nil {
+		return err
+	}
+	if roleRank[role] < roleRank[actionMinimumRole[action]] {
+		return ErrInsufficientPermissions
+	}
+	return nil
+}
+
+// authorizeProject checks action against the organisation that owns project.
+// Users outside that organisation get "project not found" so that projects of
+// other organisations are not disclosed.
+func (a authorizer) authorizeProject(project *models.Project, userID int, action Action) error {
+	if err := a.authorize(userID, project.OrganisationID, action); err != nil {
+		if errors.Is(err, ErrNotMember) {
+			return errors.New("project not found")
+		}
+		return err
+	}
+	return nil
+}
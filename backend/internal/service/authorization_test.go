@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+var testRoles = []string{RoleViewer, RoleEditor, RoleAdmin, RoleOwner}
+
+func TestAuthorizeMatrix(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	outsider := env.createUser("outsider@example.com")
+	auth := authorizer{userOrgRepo: env.repos.UserOrgMapping}
+
+	// allowed lists the roles that may perform each action
+	allowed := map[Action][]string{
+		ActionView:   {RoleViewer, RoleEditor, RoleAdmin, RoleOwner},
+		ActionEdit:   {RoleEditor, RoleAdmin, RoleOwner},
+		ActionManage: {RoleAdmin, RoleOwner},
+		ActionOwn:    {RoleOwner},
+	}
+
+	for action, roles := range allowed {
+		for _, role := range testRoles {
+			err := auth.authorize(team.members[role].ID, team.org.ID, action)
+			if want := slices.Contains(roles, role); want && err != nil {
+				t.Errorf("%s: action %d: error = %v, want allowed", role, action, err)
+			} else if !want && !errors.Is(err, ErrInsufficientPermissions) {
+				t.Errorf("%s: action %d: error = %v, want ErrInsufficientPermissions", role, action, err)
+			}
+		}
+
+		if err := auth.authorize(outsider.ID, team.org.ID, action); !errors.Is(err, ErrNotMember) {
+			t.Errorf("outsider: action %d: error = %v, want ErrNotMember", action, err)
+		}
+	}
+}
+
+func TestProjectPermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		run     func(env *testEnv, project *contracts.Project, userID int) error
+	}{
+		{
+			name:    "list endpoints",
+			allowed: []string{RoleViewer, RoleEditor, RoleAdmin, RoleOwner},
+			run: func(env *testEnv, project *contracts.Project, userID int) error {
+				_, err := env.services.Endpoint.GetByProjectUUID(project.UUID, userID)
+				return err
+			},
+		},
+		{
+			name:    "create endpoint",
+			allowed: []string{RoleEditor, RoleAdmin, RoleOwner},
+			run: func(env *testEnv, project *contracts.Project, userID int) error {
+				_, err := env.services.Endpoint.CreateEndpoint(&contracts.CreateEndpointRequest{Method: "GET", Path: "/users", ResponseStatus: 200}, project.UUID, userID, testClient)
+				return err
+			},
+		},
+		{
+			name:    "delete project",
+			allowed: []string{RoleAdmin, RoleOwner},
+			run: func(env *testEnv, project *contracts.Project, userID int) error {
+				return env.services.Project.DeleteProject(project.UUID, userID, testClient)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, role := range testRoles {
+			t.Run(tt.name+"/"+role, func(t *testing.T) {
+				env := newTestEnv(t)
+				team := env.createTeam()
+				project := env.createProject(team.org, team.members[RoleOwner])
+
+				err := tt.run(env, project, team.members[role].ID)
+				if slices.Contains(tt.allowed, role) {
+					if err != nil {
+						t.Errorf("error = %v, want allowed", err)
+					}
+				} else if !errors.Is(err, ErrInsufficientPermissions) {
+					t.Errorf("error = %v, want ErrInsufficientPermissions", err)
+				}
+			})
+		}
+
+		t.Run(tt.name+"/outsider", func(t *testing.T) {
+			env := newTestEnv(t)
+			team := env.createTeam()
+			project := env.createProject(team.org, team.members[RoleOwner])
+			outsider := env.createUser("outsider@example.com")
+
+			// Projects of other organisations are not disclosed
+			err := tt.run(env, project, outsider.ID)
+			if err == nil || errors.Is(err, ErrInsufficientPermissions) {
+				t.Errorf("error = %v, want a not found error", err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -19,23 +20,52 @@ import (
 )
 
 type endpointService struct {
-	repo        repository.EndpointRepository
-	projectRepo repository.ProjectRepository
-	userRepo    repository.UserRepository
+	repo         repository.EndpointRepository
+	revisionRepo repository.EndpointRevisionRepository
+	projectRepo  repository.ProjectRepository
+	userRepo     repository.UserRepository
+	transactor   repository.Transactor
+	auth         authorizer
 }
 
-var ErrInvalidOpenAPIDocument = errors.New("invalid openapi document")
+var (
+	ErrInvalidOpenAPIDocument = errors.New("invalid openapi document")
+	ErrInvalidEndpoint        = errors.New("invalid endpoint")
+)
+
+// endpointMethodPattern accepts methods such as GET or PROPFIND. Mocks are
+// matched against the method exactly as clients send it, which is upper case.
+var endpointMethodPattern = regexp.MustCompile(`^[A-Z]+$`)
+
+// validateEndpoint checks the method, path and status of an endpoint before it
+// is created, changed or synced, so every stored endpoint can be served.
+func validateEndpoint(method, path string, status int) error {
+	if !endpointMethodPattern.MatchString(method) {
+		return fmt.Errorf("%w: method %q must be an upper-case HTTP method", ErrInvalidEndpoint, method)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("%w: path %q must start with /", ErrInvalidEndpoint, path)
+	}
+	if status < 100 || status > 999 {
+		return fmt.Errorf("%w: status %d must be between 100 and 999", ErrInvalidEndpoint, status)
+	}
+	return nil
+}
 
-func NewEndpointService(repo repository.EndpointRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository) EndpointService {
+func NewEndpointService(repo repository.EndpointRepository, revisionRepo repository.EndpointRevisionRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository, userOrgRepo repository.UserOrganisationMappingRepository, transactor repository.Transactor) EndpointService {
 	return &endpointService{
-		repo:        repo,
-		projectRepo: projectRepo,
-		userRepo:    userRepo,
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		projectRepo:  projectRepo,
+		userRepo:     userRepo,
+		transactor:   transactor,
+		auth:         authorizer{userOrgRepo: userOrgRepo},
 	}
 }
 
-func (s *endpointService) CreateEndpoint(req *contracts.CreateEndpointRequest, projectUUID string, userID int) (*contracts.Endpoint, error) {
-	project, err := s.projectRepo.GetByUUIDForUser(projectUUID, userID)
+// getProjectForUser loads a project and checks that userID may perform action on it.
+func (s *endpointService) getProjectForUser(projectUUID string, userID int, action Action) (*models.Project, error) {
+	project, err := s.projectRepo.GetByUUID(projectUUID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("project not found")
@@ -43,15 +73,61 @@ func (s *endpointService) CreateEndpoint(req *contracts.CreateEndpointRequest, p
 		return nil, err
 	}
 
+	if err := s.auth.authorizeProject(project, userID, action); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// getEndpointForUser loads an endpoint with its project and checks that userID
+// may perform action on it.
+func (s *endpointService) getEndpointForUser(endpointUUID string, userID int, action Action) (*models.Endpoint, *models.Project, error) {
+	endpoint, err := s.repo.GetByUUID(endpointUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errors.New("endpoint not found")
+		}
+		return nil, nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(endpoint.ProjectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errors.New("endpoint not found")
+		}
+		return nil, nil, err
+	}
+
+	if err := s.auth.authorizeProject(project, userID, action); err != nil {
+		if err.Error() == "project not found" {
+			return nil, nil, errors.New("endpoint not found")
+		}
+		return nil, nil, err
+	}
+
+	return endpoint, project, nil
+}
+
+func (s *endpointService) CreateEndpoint(req *contracts.CreateEndpointRequest, projectUUID string, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.createEndpointRecord(project, user, req)
+	return s.createEndpointRecord(project, user, req, client)
 }
 
-func (s *endpointService) createEndpointRecord(project *models.Project, user *models.User, req *contracts.CreateEndpointRequest) (*contracts.Endpoint, error) {
+func (s *endpointService) createEndpointRecord(project *models.Project, user *models.User, req *contracts.CreateEndpointRequest, client contracts.ClientInfo) (*contracts.Endpoint, error) {
+	if err := validateEndpoint(req.Method, req.Path, req.ResponseStatus); err != nil {
+		return nil, err
+	}
+
 	existingEndpoint, err := s.repo.GetByProjectIDAndPath(project.ID, req.Path, req.Method)
 	if err == nil && existingEndpoint != nil {
 		return nil, errors.New("endpoint with same method and path already exists")
@@ -76,7 +152,23 @@ func (s *endpointService) createEndpointRecord(project *models.Project, user *mo
 		},
 	}
 
-	if err := s.repo.Create(endpoint); err != nil {
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Endpoint.Create(endpoint); err != nil {
+			return err
+		}
+		if err := recordRevision(repos.Revision, endpoint, user, 0); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionCreate,
+			EntityType:     AuditEntityEndpoint,
+			EntityUUID:     endpoint.UUID,
+			After:          endpointAuditFields(endpoint),
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -119,19 +211,12 @@ func (s *endpointService) GetByProjectIDAndPath(projectID int, path, method stri
 	}, nil
 }
 
-func (s *endpointService) UpdateEndpoint(endpointUUID string, req *contracts.UpdateEndpointRequest, userID int) (*contracts.Endpoint, error) {
-	endpoint, err := s.repo.GetByUUIDForUser(endpointUUID, userID)
+func (s *endpointService) UpdateEndpoint(endpointUUID string, req *contracts.UpdateEndpointRequest, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error) {
+	endpoint, project, err := s.getEndpointForUser(endpointUUID, userID, ActionEdit)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("endpoint not found")
-		}
 		return nil, err
 	}
-
-	project, err := s.projectRepo.GetByID(endpoint.ProjectID)
-	if err != nil {
-		return nil, errors.New("project not found")
-	}
+	before := endpointAuditFields(endpoint)
 
 	// Check for potential duplicate if method or path is being updated
 	newMethod := endpoint.Method
@@ -143,6 +228,13 @@ func (s *endpointService) UpdateEndpoint(endpointUUID string, req *contracts.Upd
 	if req.Path != nil {
 		newPath = *req.Path
 	}
+	newStatus := endpoint.ResponseStatus
+	if req.ResponseStatus != nil {
+		newStatus = *req.ResponseStatus
+	}
+	if err := validateEndpoint(newMethod, newPath, newStatus); err != nil {
+		return nil, err
+	}
 
 	// Only check for duplicates if method or path is actually changing
 	if (req.Method != nil && *req.Method != endpoint.Method) || (req.Path != nil && *req.Path != endpoint.Path) {
@@ -177,7 +269,26 @@ func (s *endpointService) UpdateEndpoint(endpointUUID string, req *contracts.Upd
 	endpoint.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
 	endpoint.UpdatedAt = &now
 
-	err = s.repo.Update(endpoint)
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Endpoint.Update(endpoint); err != nil {
+			return err
+		}
+		// Saving without changes leaves the history alone
+		if len(diffAuditFields(before, endpointAuditFields(endpoint))) > 0 {
+			if err := recordRevision(repos.Revision, endpoint, user, 0); err != nil {
+				return err
+			}
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionUpdate,
+			EntityType:     AuditEntityEndpoint,
+			EntityUUID:     endpoint.UUID,
+			Before:         before,
+			After:          endpointAuditFields(endpoint),
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -198,12 +309,9 @@ func (s *endpointService) UpdateEndpoint(endpointUUID string, req *contracts.Upd
 	}, nil
 }
 
-func (s *endpointService) DeleteEndpoint(endpointUUID string, userID int) error {
-	endpoint, err := s.repo.GetByUUIDForUser(endpointUUID, userID)
+func (s *endpointService) DeleteEndpoint(endpointUUID string, userID int, client contracts.ClientInfo) error {
+	endpoint, project, err := s.getEndpointForUser(endpointUUID, userID, ActionEdit)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return errors.New("endpoint not found")
-		}
 		return err
 	}
 
@@ -215,20 +323,24 @@ func (s *endpointService) DeleteEndpoint(endpointUUID string, userID int) error
 	endpoint.DeletedAt = &currentTime
 	endpoint.DeletedBy = sql.NullString{String: user.UUID, Valid: true}
 
-	err = s.repo.Update(endpoint)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Endpoint.Update(endpoint); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionDelete,
+			EntityType:     AuditEntityEndpoint,
+			EntityUUID:     endpoint.UUID,
+			Before:         endpointAuditFields(endpoint),
+		})
+	})
 }
 
 func (s *endpointService) GetByProjectUUID(projectUUID string, userID int) ([]*contracts.Endpoint, error) {
-	project, err := s.projectRepo.GetByUUIDForUser(projectUUID, userID)
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("project not found")
-		}
 		return nil, err
 	}
 
@@ -258,12 +370,9 @@ func (s *endpointService) GetByProjectUUID(projectUUID string, userID int) ([]*c
 	return result, nil
 }
 
-func (s *endpointService) CreateEndpointsBulk(projectUUID string, requests []contracts.CreateEndpointRequest, userID int) (*contracts.BulkCreateEndpointsResult, error) {
-	project, err := s.projectRepo.GetByUUIDForUser(projectUUID, userID)
+func (s *endpointService) CreateEndpointsBulk(projectUUID string, requests []contracts.CreateEndpointRequest, userID int, client contracts.ClientInfo) (*contracts.BulkCreateEndpointsResult, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("project not found")
-		}
 		return nil, err
 	}
 
@@ -276,7 +385,7 @@ func (s *endpointService) CreateEndpointsBulk(projectUUID string, requests []con
 
 	for _, req := range requests {
 		payload := req
-		endpoint, createErr := s.createEndpointRecord(project, user, &payload)
+		endpoint, createErr := s.createEndpointRecord(project, user, &payload, client)
 		if createErr != nil {
 			result.Skipped = append(result.Skipped, contracts.BulkCreateSkippedEndpoint{
 				Method: req.Method,
@@ -293,11 +402,8 @@ func (s *endpointService) CreateEndpointsBulk(projectUUID string, requests []con
 }
 
 func (s *endpointService) PreviewOpenAPIYAML(projectUUID string, data []byte, userID int) (*contracts.OpenAPIImportPreview, error) {
-	project, err := s.projectRepo.GetByUUIDForUser(projectUUID, userID)
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("project not found")
-		}
 		return nil, err
 	}
 
@@ -620,11 +726,8 @@ func wrapOpenAPIError(err error) error {
 }
 
 func (s *endpointService) GetEndpoint(endpointUUID string, userID int) (*contracts.Endpoint, error) {
-	endpoint, err := s.repo.GetByUUIDForUser(endpointUUID, userID)
+	endpoint, project, err := s.getEndpointForUser(endpointUUID, userID, ActionView)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("endpoint not found")
-		}
 		return nil, err
 	}
 
@@ -636,6 +739,7 @@ func (s *endpointService) GetEndpoint(endpointUUID string, userID int) (*contrac
 		ResponseBody:    endpoint.ResponseBody,
 		ResponseStatus:  endpoint.ResponseStatus,
 		ResponseHeaders: endpoint.ResponseHeaders,
+		ProjectUUID:     project.UUID,
 		CreatedAt:       endpoint.CreatedAt,
 		UpdatedAt:       endpoint.UpdatedAt,
 		CreatedBy:       endpoint.CreatedBy.String,
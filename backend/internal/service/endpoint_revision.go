@@ -0,0 +1,223 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+	"github.com/crudboxin/crudbox/internal/textdiff"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// recordRevision appends the current state of endpoint to its history. It runs
+// in the transaction that saves the endpoint so the newest revision always
+// matches the endpoint row.
+func recordRevision(repo repository.EndpointRevisionRepository, endpoint *models.Endpoint, author *models.User, restoredFrom int) error {
+	now := time.Now()
+	return repo.Create(&models.EndpointRevision{
+		EndpointID:      endpoint.ID,
+		Method:          endpoint.Method,
+		Path:            endpoint.Path,
+		ResponseBody:    endpoint.ResponseBody,
+		ResponseStatus:  endpoint.ResponseStatus,
+		ResponseHeaders: endpoint.ResponseHeaders,
+		RestoredFrom:    sql.NullInt64{Int64: int64(restoredFrom), Valid: restoredFrom != 0},
+		CreatedAt:       &now,
+		CreatedBy:       sql.NullString{String: author.UUID, Valid: true},
+	})
+}
+
+// revisionEndpoint returns the endpoint as it was saved in revision.
+func revisionEndpoint(revision *models.EndpointRevision) *models.Endpoint {
+	return &models.Endpoint{
+		Method:          revision.Method,
+		Path:            revision.Path,
+		ResponseBody:    revision.ResponseBody,
+		ResponseStatus:  revision.ResponseStatus,
+		ResponseHeaders: revision.ResponseHeaders,
+	}
+}
+
+func (s *endpointService) getRevision(endpointID, revision int) (*models.EndpointRevision, error) {
+	endpointRevision, err := s.revisionRepo.GetByEndpointIDAndRevision(endpointID, revision)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return endpointRevision, nil
+}
+
+func (s *endpointService) GetRevisions(endpointUUID string, userID int) ([]*contracts.EndpointRevision, error) {
+	endpoint, _, err := s.getEndpointForUser(endpointUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.GetByEndpointID(endpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make(map[string]string)
+	result := make([]*contracts.EndpointRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		authorUUID := revision.CreatedBy.String
+		if _, seen := authors[authorUUID]; !seen && authorUUID != "" {
+			author, err := s.userRepo.GetByUUID(authorUUID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+			if author != nil {
+				authors[authorUUID] = author.Email
+			}
+		}
+
+		var restoredFrom *int
+		if revision.RestoredFrom.Valid {
+			from := int(revision.RestoredFrom.Int64)
+			restoredFrom = &from
+		}
+
+		result = append(result, &contracts.EndpointRevision{
+			UUID:            revision.UUID,
+			Revision:        revision.Revision,
+			Method:          revision.Method,
+			Path:            revision.Path,
+			ResponseBody:    revision.ResponseBody,
+			ResponseStatus:  revision.ResponseStatus,
+			ResponseHeaders: revision.ResponseHeaders,
+			RestoredFrom:    restoredFrom,
+			AuthorUUID:      authorUUID,
+			AuthorEmail:     authors[authorUUID],
+			CreatedAt:       revision.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// DiffRevisions compares two revisions of an endpoint, showing how from turned
+// into to. Either may be the older one.
+func (s *endpointService) DiffRevisions(endpointUUID string, from, to int, userID int) (*contracts.EndpointRevisionDiff, error) {
+	endpoint, _, err := s.getEndpointForUser(endpointUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.getRevision(endpoint.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.getRevision(endpoint.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &contracts.EndpointRevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: diffAuditFields(endpointAuditFields(revisionEndpoint(fromRevision)), endpointAuditFields(revisionEndpoint(toRevision))),
+	}
+	if fromRevision.ResponseBody != toRevision.ResponseBody {
+		result.ResponseBodyDiff = diffLines(fromRevision.ResponseBody, toRevision.ResponseBody)
+	}
+	if fromRevision.ResponseHeaders != toRevision.ResponseHeaders {
+		result.ResponseHeadersDiff = diffLines(fromRevision.ResponseHeaders, toRevision.ResponseHeaders)
+	}
+
+	return result, nil
+}
+
+func diffLines(a, b string) []contracts.DiffLine {
+	lines := textdiff.Lines(a, b)
+	result := make([]contracts.DiffLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, contracts.DiffLine{Op: string(line.Op), Text: line.Text})
+	}
+	return result
+}
+
+// RestoreRevision puts the endpoint back into the state saved in revision. The
+// restore is itself saved as a new revision, so it can be undone as well.
+func (s *endpointService) RestoreRevision(endpointUUID string, revision int, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error) {
+	endpoint, project, err := s.getEndpointForUser(endpointUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.getRevision(endpoint.ID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if restored.Method != endpoint.Method || restored.Path != endpoint.Path {
+		existingEndpoint, err := s.repo.GetByProjectIDAndPath(endpoint.ProjectID, restored.Path, restored.Method)
+		if err == nil && existingEndpoint.ID != endpoint.ID {
+			return nil, errors.New("endpoint with same method and path already exists")
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := endpointAuditFields(endpoint)
+	endpoint.Method = restored.Method
+	endpoint.Path = restored.Path
+	endpoint.ResponseBody = restored.ResponseBody
+	endpoint.ResponseStatus = restored.ResponseStatus
+	endpoint.ResponseHeaders = restored.ResponseHeaders
+	after := endpointAuditFields(endpoint)
+
+	if len(diffAuditFields(before, after)) > 0 {
+		now := time.Now()
+		endpoint.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
+		endpoint.UpdatedAt = &now
+
+		err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+			if err := repos.Endpoint.Update(endpoint); err != nil {
+				return err
+			}
+			if err := recordRevision(repos.Revision, endpoint, user, revision); err != nil {
+				return err
+			}
+			return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+				OrganisationID: project.OrganisationID,
+				ProjectUUID:    project.UUID,
+				Action:         AuditActionUpdate,
+				EntityType:     AuditEntityEndpoint,
+				EntityUUID:     endpoint.UUID,
+				Before:         before,
+				After:          after,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &contracts.Endpoint{
+		ID:              endpoint.ID,
+		UUID:            endpoint.UUID,
+		Method:          endpoint.Method,
+		Path:            endpoint.Path,
+		ResponseBody:    endpoint.ResponseBody,
+		ResponseStatus:  endpoint.ResponseStatus,
+		ResponseHeaders: endpoint.ResponseHeaders,
+		ProjectUUID:     project.UUID,
+		CreatedAt:       endpoint.CreatedAt,
+		UpdatedAt:       endpoint.UpdatedAt,
+		CreatedBy:       endpoint.CreatedBy.String,
+		UpdatedBy:       endpoint.UpdatedBy.String,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+func TestValidateEndpoint(t *testing.T) {
+	tests := []struct {
+		method, path string
+		status       int
+		valid        bool
+	}{
+		{method: "GET", path: "/users", status: 200, valid: true},
+		{method: "PROPFIND", path: "/", status: 207, valid: true},
+		{method: "DELETE", path: "/users/1", status: 999, valid: true},
+		{method: "get", path: "/users", status: 200},
+		{method: "", path: "/users", status: 200},
+		{method: "GET /x", path: "/users", status: 200},
+		{method: "GET", path: "users", status: 200},
+		{method: "GET", path: "", status: 200},
+		{method: "GET", path: "/users", status: 0},
+		{method: "GET", path: "/users", status: 99},
+		{method: "GET", path: "/users", status: 1000},
+	}
+	for _, tt := range tests {
+		err := validateEndpoint(tt.method, tt.path, tt.status)
+		if tt.valid && err != nil {
+			t.Errorf("validateEndpoint(%q, %q, %d) error = %v, want nil", tt.method, tt.path, tt.status, err)
+		} else if !tt.valid && !errors.Is(err, ErrInvalidEndpoint) {
+			t.Errorf("validateEndpoint(%q, %q, %d) error = %v, want ErrInvalidEndpoint", tt.method, tt.path, tt.status, err)
+		}
+	}
+}
+
+func TestCreateAndUpdateEndpointValidate(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor]
+	project := env.createProject(team.org, editor)
+
+	_, err := env.services.Endpoint.CreateEndpoint(&contracts.CreateEndpointRequest{Method: "GET", Path: "users", ResponseStatus: 200}, project.UUID, editor.ID, testClient)
+	if !errors.Is(err, ErrInvalidEndpoint) {
+		t.Errorf("CreateEndpoint(relative path) error = %v, want ErrInvalidEndpoint", err)
+	}
+
+	endpoint := env.createEndpoint(project, editor, "GET", "/users", "[]")
+	status := 0
+	_, err = env.services.Endpoint.UpdateEndpoint(endpoint.UUID, &contracts.UpdateEndpointRequest{ResponseStatus: &status}, editor.ID, testClient)
+	if !errors.Is(err, ErrInvalidEndpoint) {
+		t.Errorf("UpdateEndpoint(status 0) error = %v, want ErrInvalidEndpoint", err)
+	}
+
+	result, err := env.services.Endpoint.CreateEndpointsBulk(project.UUID, []contracts.CreateEndpointRequest{
+		{Method: "post", Path: "/users", ResponseStatus: 201},
+		{Method: "POST", Path: "/users", ResponseStatus: 201},
+	}, editor.ID, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Created) != 1 || len(result.Skipped) != 1 || result.Skipped[0].Method != "post" {
+		t.Errorf("bulk result = %d created, skipped %+v, want the lower-case method skipped", len(result.Created), result.Skipped)
+	}
+}
@@ -1,38 +1,109 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/jwtkeys"
+	"github.com/crudboxin/crudbox/internal/mail"
+	"github.com/crudboxin/crudbox/internal/oidc"
 	"github.com/crudboxin/crudbox/internal/repository"
 )
 
 type UserService interface {
-	SignUp(req *contracts.SignUpRequest) error
-	Login(req *contracts.LoginRequest) (string, error)
+	SignUp(req *contracts.SignUpRequest, client contracts.ClientInfo) error
+	Login(req *contracts.LoginRequest, client contracts.ClientInfo) (*contracts.AuthTokens, error)
+	RefreshToken(req *contracts.RefreshTokenRequest, client contracts.ClientInfo) (*contracts.AuthTokens, error)
+	Logout(sessionUUID string, userID int) error
+	LogoutAll(userID int) error
+	ValidateSession(sessionUUID string, userID int) error
+	RequestPasswordReset(req *contracts.ForgotPasswordRequest) error
+	ResetPassword(req *contracts.ResetPasswordRequest) error
+	VerifyEmail(req *contracts.VerifyEmailRequest) error
+	ResendVerificationEmail(req *contracts.ResendVerificationRequest) error
 	GetByID(id int) (*contracts.User, error)
 }
 
 type OrganisationService interface {
 	GetByUserID(userID int) ([]*contracts.Organisation, error)
-	CreateOrganisation(req *contracts.CreateOrganisationRequest, userID int) (*contracts.Organisation, error)
+	CreateOrganisation(req *contracts.CreateOrganisationRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error)
+	UpdateOrganisation(orgUUID string, req *contracts.UpdateOrganisationRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error)
+	DeleteOrganisation(orgUUID string, userID int, client contracts.ClientInfo) error
+	TransferOwnership(orgUUID string, req *contracts.TransferOwnershipRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error)
+	GetAuditLog(orgUUID string, query *contracts.AuditLogQuery, userID int) (*contracts.AuditLogPage, error)
+	GetMembers(orgUUID string, userID int) ([]*contracts.Member, error)
+	RemoveMember(orgUUID, memberUUID string, userID int, client contracts.ClientInfo) error
+	UpdateMemberRole(orgUUID, memberUUID string, req *contracts.UpdateMemberRoleRequest, userID int, client contracts.ClientInfo) (*contracts.Member, error)
+	CreateInvitation(orgUUID string, req *contracts.CreateInvitationRequest, userID int) (*contracts.CreatedInvitation, error)
+	GetPendingInvitations(orgUUID string, userID int) ([]*contracts.Invitation, error)
+	RevokeInvitation(orgUUID, invitationUUID string, userID int) error
+	AcceptInvitation(req *contracts.AcceptInvitationRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error)
 }
 
 type ProjectService interface {
 	GetByUserID(userID int) ([]*contracts.Project, error)
-	CreateProject(req *contracts.CreateProjectRequest, userID int) (*contracts.Project, error)
+	CreateProject(req *contracts.CreateProjectRequest, userID int, client contracts.ClientInfo) (*contracts.Project, error)
 	GetByCode(code string) (*contracts.Project, error)
+	GetBySubdomain(label string) (*contracts.Project, error)
+	GetByHostname(hostname string) (*contracts.Project, error)
+	ResolveCodeRedirect(oldCode string) (string, error)
 	GetByUUID(uuid string) (*contracts.Project, error)
-	DeleteProject(uuid string, userID int) error
+	UpdateProject(uuid string, req *contracts.UpdateProjectRequest, userID int, client contracts.ClientInfo) (*contracts.Project, error)
+	DeleteProject(uuid string, userID int, client contracts.ClientInfo) error
+	ExportProject(uuid string, userID int) (*contracts.ProjectBundle, error)
+	ImportProject(orgUUID string, bundle *contracts.ProjectBundle, userID int, client contracts.ClientInfo) (*contracts.Project, error)
+	CloneProject(uuid string, req *contracts.CloneProjectRequest, userID int, client contracts.ClientInfo) (*contracts.Project, error)
+	GetTrash(orgUUID string, userID int) (*contracts.OrganisationTrash, error)
+	RestoreProject(uuid string, userID int, client contracts.ClientInfo) (*contracts.Project, error)
+	PurgeTrash(retention time.Duration) (projects, endpoints int64, err error)
+	CreateVersion(projectUUID string, req *contracts.CreateProjectVersionRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectVersion, error)
+	GetVersions(projectUUID string, userID int) ([]*contracts.ProjectVersion, error)
+	GetVersion(projectUUID, name string, userID int) (*contracts.ProjectVersion, error)
+	DeleteVersion(projectUUID, name string, userID int, client contracts.ClientInfo) error
+	RestoreVersion(projectUUID, name string, userID int, client contracts.ClientInfo) (*contracts.ProjectVersionRestoreResult, error)
+	GetVersionEndpoint(projectID int, name, path, method string) (*contracts.Endpoint, error)
+	CreateEnvironment(projectUUID string, req *contracts.CreateProjectEnvironmentRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectEnvironment, error)
+	GetEnvironments(projectUUID string, userID int) ([]*contracts.ProjectEnvironment, error)
+	UpdateEnvironment(projectUUID, name string, req *contracts.UpdateProjectEnvironmentRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectEnvironment, error)
+	DeleteEnvironment(projectUUID, name string, userID int, client contracts.ClientInfo) error
+	GetMockVariables(projectID int, environment string) (map[string]string, error)
+	CreateBranch(projectUUID string, req *contracts.CreateProjectBranchRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectBranch, error)
+	GetBranches(projectUUID string, userID int) ([]*contracts.ProjectBranch, error)
+	GetMergePreview(projectUUID string, userID int) (*contracts.ProjectMergePreview, error)
+	MergeBranch(projectUUID string, req *contracts.MergeProjectBranchRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectMergeResult, error)
+	GetSyncStatus(projectUUID string, userID int) (*contracts.ProjectSyncStatus, error)
+	SyncProject(projectUUID string, req *contracts.SyncProjectRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectSyncPlan, error)
+	RecordRequest(projectID int, req *contracts.MockRequest, client contracts.ClientInfo)
+	GetRequestLog(projectUUID string, query *contracts.RequestLogQuery, userID int) (*contracts.RequestLogPage, error)
 }
 
 type EndpointService interface {
 	GetByProjectUUID(projectUUID string, userID int) ([]*contracts.Endpoint, error)
-	CreateEndpoint(req *contracts.CreateEndpointRequest, projectUUID string, userID int) (*contracts.Endpoint, error)
-	UpdateEndpoint(endpointUUID string, req *contracts.UpdateEndpointRequest, userID int) (*contracts.Endpoint, error)
-	DeleteEndpoint(endpointUUID string, userID int) error
+	CreateEndpoint(req *contracts.CreateEndpointRequest, projectUUID string, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error)
+	UpdateEndpoint(endpointUUID string, req *contracts.UpdateEndpointRequest, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error)
+	DeleteEndpoint(endpointUUID string, userID int, client contracts.ClientInfo) error
 	GetByProjectIDAndPath(projectID int, path, method string) (*contracts.Endpoint, error)
 	PreviewOpenAPIYAML(projectUUID string, data []byte, userID int) (*contracts.OpenAPIImportPreview, error)
-	CreateEndpointsBulk(projectUUID string, requests []contracts.CreateEndpointRequest, userID int) (*contracts.BulkCreateEndpointsResult, error)
+	CreateEndpointsBulk(projectUUID string, requests []contracts.CreateEndpointRequest, userID int, client contracts.ClientInfo) (*contracts.BulkCreateEndpointsResult, error)
 	GetEndpoint(endpointUUID string, userID int) (*contracts.Endpoint, error)
+	GetRevisions(endpointUUID string, userID int) ([]*contracts.EndpointRevision, error)
+	DiffRevisions(endpointUUID string, from, to int, userID int) (*contracts.EndpointRevisionDiff, error)
+	RestoreRevision(endpointUUID string, revision int, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error)
+	GetTrash(projectUUID string, userID int) ([]*contracts.Endpoint, error)
+	RestoreEndpoint(endpointUUID string, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error)
+}
+
+type APITokenService interface {
+	CreateToken(req *contracts.CreateAPITokenRequest, userID int) (*contracts.CreatedAPIToken, error)
+	GetTokens(userID int) ([]*contracts.APIToken, error)
+	RevokeToken(tokenUUID string, userID int) error
+	AuthenticateAPIToken(token string) (userID int, userUUID, tokenUUID string, scopes []string, err error)
+}
+
+type OIDCService interface {
+	BeginLogin(ctx context.Context) (*contracts.OIDCLogin, error)
+	CompleteLogin(ctx context.Context, code, state, flow string, client contracts.ClientInfo) (*contracts.AuthTokens, error)
 }
 
 type Services struct {
@@ -40,13 +111,30 @@ type Services struct {
 	Organisation OrganisationService
 	Project      ProjectService
 	Endpoint     EndpointService
+	APIToken     APITokenService
+	OIDC         OIDCService
+}
+
+// AuthConfig controls how login sessions and their tokens are issued.
+type AuthConfig struct {
+	Keys            *jwtkeys.KeySet
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// RequireEmailVerification blocks login until the user's email is verified.
+	RequireEmailVerification bool
+	// AppBaseURL is the frontend URL that links in emails point to.
+	AppBaseURL string
 }
 
-func NewServices(repos *repository.Repositories, jwtSecret []byte) *Services {
+// NewServices wires the services. oidcProvider may be nil when single sign-on
+// is not configured.
+func NewServices(repos *repository.Repositories, authConfig AuthConfig, hosts MockHosts, mailer mail.Sender, oidcProvider *oidc.Provider) *Services {
 	return &Services{
-		User:         NewUserService(repos.User, repos.Organisation, repos.UserOrgMapping, jwtSecret),
-		Organisation: NewOrganisationService(repos.Organisation, repos.User, repos.UserOrgMapping),
-		Project:      NewProjectService(repos.Project, repos.User, repos.Organisation, repos.UserOrgMapping, repos.Endpoint),
-		Endpoint:     NewEndpointService(repos.Endpoint, repos.Project, repos.User),
+		User:         NewUserService(repos.User, repos.Organisation, repos.UserOrgMapping, repos.Session, repos.UserToken, repos.AuthThrottle, repos.Transactor, mailer, authConfig),
+		Organisation: NewOrganisationService(repos.Organisation, repos.User, repos.UserOrgMapping, repos.Invitation, repos.AuditLog, repos.Transactor),
+		Project:      NewProjectService(repos.Project, repos.User, repos.Organisation, repos.UserOrgMapping, repos.Endpoint, repos.CodeRedirect, repos.Version, repos.Environment, repos.Branch, repos.Sync, repos.RequestLog, repos.Transactor, hosts),
+		Endpoint:     NewEndpointService(repos.Endpoint, repos.Revision, repos.Project, repos.User, repos.UserOrgMapping, repos.Transactor),
+		APIToken:     NewAPITokenService(repos.APIToken, repos.User),
+		OIDC:         NewOIDCService(oidcProvider, repos.User, repos.UserIdentity, repos.Session, repos.Transactor, authConfig),
 	}
 }
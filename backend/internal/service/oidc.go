@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/oidc"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+// oidcFlowTTL bounds how long a user may take to log in at the provider.
+const oidcFlowTTL = 10 * time.Minute
+
+// oidcFlowAudience keeps flow tokens from being mistaken for access tokens.
+const oidcFlowAudience = "crudbox-oidc-flow"
+
+var (
+	ErrOIDCNotConfigured = errors.New("single sign-on is not configured")
+	ErrOIDCLoginFailed   = errors.New("single sign-on login failed")
+)
+
+type oidcService struct {
+	provider         *oidc.Provider
+	userRepo         repository.UserRepository
+	userIdentityRepo repository.UserIdentityRepository
+	transactor       repository.Transactor
+	authConfig       AuthConfig
+	sessions         sessionIssuer
+}
+
+func NewOIDCService(provider *oidc.Provider, userRepo repository.UserRepository, userIdentityRepo repository.UserIdentityRepository, sessionRepo repository.UserSessionRepository, transactor repository.Transactor, authConfig AuthConfig) OIDCService {
+	return &oidcService{
+		provider:         provider,
+		userRepo:         userRepo,
+		userIdentityRepo: userIdentityRepo,
+		transactor:       transactor,
+		authConfig:       authConfig,
+		sessions:         sessionIssuer{sessionRepo: sessionRepo, authConfig: authConfig},
+	}
+}
+
+// BeginLogin prepares an authorization code request with PKCE. The state,
+// nonce and code verifier are kept in a signed flow token instead of the
+// database so that the callback can be served by any instance.
+func (s *oidcService) BeginLogin(ctx context.Context) (*contracts.OIDCLogin, error) {
+	if s.provider == nil {
+		return nil, ErrOIDCNotConfigured
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := s.provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	flow, err := s.authConfig.Keys.Sign(jwt.MapClaims{
+		"aud":           oidcFlowAudience,
+		"state":         state,
+		"nonce":         nonce,
+		"code_verifier": codeVerifier,
+		"exp":           time.Now().Add(oidcFlowTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.OIDCLogin{
+		AuthorizationURL: authorizationURL,
+		Flow:             flow,
+	}, nil
+}
+
+// CompleteLogin redeems the authorization code, verifies the ID token and logs
+// in the user it identifies. Provider accounts are linked to users by issuer and
+// subject; the first login links by verified email or creates a new user.
+func (s *oidcService) CompleteLogin(ctx context.Context, code, state, flow string, client contracts.ClientInfo) (*contracts.AuthTokens, error) {
+	if s.provider == nil {
+		return nil, ErrOIDCNotConfigured
+	}
+
+	flowClaims := jwt.MapClaims{}
+	if _, err := s.authConfig.Keys.Parse(flow, flowClaims); err != nil || !flowClaims.VerifyAudience(oidcFlowAudience, true) {
+		return nil, fmt.Errorf("%w: login attempt expired", ErrOIDCLoginFailed)
+	}
+	if expected, _ := flowClaims["state"].(string); expected == "" || expected != state {
+		return nil, fmt.Errorf("%w: state mismatch", ErrOIDCLoginFailed)
+	}
+	nonce, _ := flowClaims["nonce"].(string)
+	codeVerifier, _ := flowClaims["code_verifier"].(string)
+
+	rawIDToken, err := s.provider.Exchange(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+
+	claims, err := s.provider.VerifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+
+	user, err := s.resolveUser(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sessions.startSession(user, client)
+}
+
+// resolveUser finds the user linked to the provider account, linking or
+// creating one on first login.
+func (s *oidcService) resolveUser(claims *oidc.IDTokenClaims) (*models.User, error) {
+	identity, err := s.userIdentityRepo.GetByIssuerAndSubject(claims.Issuer, claims.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(identity.UserID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if email == "" {
+		return nil, fmt.Errorf("%w: the identity provider did not share an email address", ErrOIDCLoginFailed)
+	}
+	// Linking by email is only safe when the provider vouches for the address.
+	// Providers that leave out email_verified do not.
+	verified := claims.EmailVerified != nil && *claims.EmailVerified
+
+	var user *models.User
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		now := time.Now()
+
+		user, err = repos.User.GetByEmail(email)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			// Users created through single sign-on have no password until they reset one
+			user = &models.User{
+				Email: email,
+				Base: models.Base{
+					CreatedAt: &now,
+					UpdatedAt: &now,
+					CreatedBy: sql.NullString{String: "system", Valid: true},
+					UpdatedBy: sql.NullString{String: "system", Valid: true},
+				},
+			}
+			if verified {
+				user.EmailVerifiedAt = &now
+			}
+			if err := repos.User.Create(user); err != nil {
+				return err
+			}
+		} else if !verified {
+			return fmt.Errorf("%w: the identity provider did not verify the email address, so it cannot be linked to the existing account", ErrOIDCLoginFailed)
+		} else if user.EmailVerifiedAt == nil {
+			if err := repos.User.MarkEmailVerified(user.ID, now); err != nil {
+				return err
+			}
+			user.EmailVerifiedAt = &now
+		}
+
+		return repos.UserIdentity.Create(&models.UserIdentity{
+			UserID:  user.ID,
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+			Email:   sql.NullString{String: email, Valid: true},
+			Base: models.Base{
+				CreatedAt: &now,
+				UpdatedAt: &now,
+				CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+				UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
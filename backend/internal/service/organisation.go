@@ -2,6 +2,7 @@ package service
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/crudboxin/crudbox/internal/contracts"
@@ -10,20 +11,28 @@ import (
 )
 
 type organisationService struct {
-	repo        repository.OrganisationRepository
-	userRepo    repository.UserRepository
-	userOrgRepo repository.UserOrganisationMappingRepository
+	repo           repository.OrganisationRepository
+	userRepo       repository.UserRepository
+	userOrgRepo    repository.UserOrganisationMappingRepository
+	invitationRepo repository.OrganisationInvitationRepository
+	auditRepo      repository.AuditLogRepository
+	transactor     repository.Transactor
+	auth           authorizer
 }
 
-func NewOrganisationService(repo repository.OrganisationRepository, userRepo repository.UserRepository, userOrgRepo repository.UserOrganisationMappingRepository) OrganisationService {
+func NewOrganisationService(repo repository.OrganisationRepository, userRepo repository.UserRepository, userOrgRepo repository.UserOrganisationMappingRepository, invitationRepo repository.OrganisationInvitationRepository, auditRepo repository.AuditLogRepository, transactor repository.Transactor) OrganisationService {
 	return &organisationService{
-		repo:        repo,
-		userRepo:    userRepo,
-		userOrgRepo: userOrgRepo,
+		repo:           repo,
+		userRepo:       userRepo,
+		userOrgRepo:    userOrgRepo,
+		invitationRepo: invitationRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+		auth:           authorizer{userOrgRepo: userOrgRepo},
 	}
 }
 
-func (s *organisationService) CreateOrganisation(req *contracts.CreateOrganisationRequest, userID int) (*contracts.Organisation, error) {
+func (s *organisationService) CreateOrganisation(req *contracts.CreateOrganisationRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, err
@@ -41,23 +50,35 @@ func (s *organisationService) CreateOrganisation(req *contracts.CreateOrganisati
 		},
 	}
 
-	err = s.repo.Create(dbOrg)
-	if err != nil {
-		return nil, err
-	}
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Organisation.Create(dbOrg); err != nil {
+			return err
+		}
 
-	// Create mapping between user and organisation
-	mapping := &models.UserOrganisationMapping{
-		UserID:         userID,
-		OrganisationID: dbOrg.ID,
-		Base: models.Base{
-			CreatedAt: &now,
-			UpdatedAt: &now,
-			CreatedBy: sql.NullString{String: user.UUID, Valid: true},
-			UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
-		},
-	}
-	err = s.userOrgRepo.Create(mapping)
+		// Create mapping between user and organisation
+		mapping := &models.UserOrganisationMapping{
+			UserID:         userID,
+			OrganisationID: dbOrg.ID,
+			Role:           RoleOwner,
+			Base: models.Base{
+				CreatedAt: &now,
+				UpdatedAt: &now,
+				CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+				UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+			},
+		}
+		if err := repos.UserOrgMapping.Create(mapping); err != nil {
+			return err
+		}
+
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: dbOrg.ID,
+			Action:         AuditActionCreate,
+			EntityType:     AuditEntityOrganisation,
+			EntityUUID:     dbOrg.UUID,
+			After:          organisationAuditFields(dbOrg, user.UUID),
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +88,7 @@ func (s *organisationService) CreateOrganisation(req *contracts.CreateOrganisati
 		UUID:      dbOrg.UUID,
 		Name:      dbOrg.Name,
 		UserUUID:  user.UUID,
+		Role:      RoleOwner,
 		CreatedAt: dbOrg.CreatedAt,
 		UpdatedAt: dbOrg.UpdatedAt,
 	}, nil
@@ -93,6 +115,7 @@ func (s *organisationService) GetByUserID(userID int) ([]*contracts.Organisation
 			UUID:      org.UUID,
 			Name:      org.Name,
 			UserUUID:  owner.UUID,
+			Role:      mapping.Role,
 			CreatedAt: org.CreatedAt,
 			UpdatedAt: org.UpdatedAt,
 		})
@@ -100,3 +123,169 @@ func (s *organisationService) GetByUserID(userID int) ([]*contracts.Organisation
 
 	return organisations, nil
 }
+
+func (s *organisationService) UpdateOrganisation(orgUUID string, req *contracts.UpdateOrganisationRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error) {
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(org.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := organisationAuditFields(org, owner.UUID)
+	now := time.Now()
+	org.Name = req.Name
+	org.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
+	org.UpdatedAt = &now
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Organisation.Update(org); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			Action:         AuditActionUpdate,
+			EntityType:     AuditEntityOrganisation,
+			EntityUUID:     org.UUID,
+			Before:         before,
+			After:          organisationAuditFields(org, owner.UUID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.auth.role(userID, org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Organisation{
+		ID:        org.ID,
+		UUID:      org.UUID,
+		Name:      org.Name,
+		UserUUID:  owner.UUID,
+		Role:      role,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}, nil
+}
+
+// DeleteOrganisation soft-deletes the organisation together with its projects,
+// their endpoints and all memberships.
+func (s *organisationService) DeleteOrganisation(orgUUID string, userID int, client contracts.ClientInfo) error {
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionOwn)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	owner, err := s.userRepo.GetByID(org.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Endpoints share their project's deletion time, which is how restoring the
+	// project finds the endpoints deleted along with it.
+	now := time.Now()
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Endpoint.DeleteByOrganisationID(org.ID, user.UUID, now); err != nil {
+			return err
+		}
+		if err := repos.Project.DeleteByOrganisationID(org.ID, user.UUID, now); err != nil {
+			return err
+		}
+		if err := repos.UserOrgMapping.DeleteByOrganisationID(org.ID, user.UUID); err != nil {
+			return err
+		}
+		if err := repos.Organisation.DeleteByID(org.ID, user.UUID); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			Action:         AuditActionDelete,
+			EntityType:     AuditEntityOrganisation,
+			EntityUUID:     org.UUID,
+			Before:         organisationAuditFields(org, owner.UUID),
+		})
+	})
+}
+
+// TransferOwnership hands the organisation to another member. The new owner and
+// the previous owner (who becomes an admin) are updated in one transaction so
+// the organisation always has exactly one owner.
+func (s *organisationService) TransferOwnership(orgUUID string, req *contracts.TransferOwnershipRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error) {
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionOwn)
+	if err != nil {
+		return nil, err
+	}
+
+	newOwner, _, err := s.getMember(org, req.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+	if newOwner.ID == org.UserID {
+		return nil, errors.New("user is already the organisation owner")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousOwner, err := s.userRepo.GetByID(org.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := organisationAuditFields(org, previousOwner.UUID)
+	now := time.Now()
+	org.UserID = newOwner.ID
+	org.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
+	org.UpdatedAt = &now
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.UserOrgMapping.UpdateRole(newOwner.ID, org.ID, RoleOwner, user.UUID); err != nil {
+			return err
+		}
+		if err := repos.UserOrgMapping.UpdateRole(previousOwner.ID, org.ID, RoleAdmin, user.UUID); err != nil {
+			return err
+		}
+		if err := repos.Organisation.Update(org); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			Action:         AuditActionUpdate,
+			EntityType:     AuditEntityOrganisation,
+			EntityUUID:     org.UUID,
+			Before:         before,
+			After:          organisationAuditFields(org, newOwner.UUID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Organisation{
+		ID:        org.ID,
+		UUID:      org.UUID,
+		Name:      org.Name,
+		UserUUID:  newOwner.UUID,
+		Role:      RoleAdmin,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}, nil
+}
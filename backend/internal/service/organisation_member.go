@@ -0,0 +1,395 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+var ErrAlreadyMember = errors.New("user is already a member of the organisation")
+
+// getOrganisationForUser loads the organisation and checks that userID may
+// perform action in it.
+func (s *organisationService) getOrganisationForUser(orgUUID string, userID int, action Action) (*models.Organisation, error) {
+	org, err := s.repo.GetByUUID(orgUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("organisation not found")
+		}
+		return nil, err
+	}
+
+	if err := s.auth.authorize(userID, org.ID, action); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// getMember resolves memberUUID to a user that belongs to org.
+func (s *organisationService) getMember(org *models.Organisation, memberUUID string) (*models.User, *models.UserOrganisationMapping, error) {
+	member, err := s.userRepo.GetByUUID(memberUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errors.New("member not found")
+		}
+		return nil, nil, err
+	}
+
+	mapping, err := s.userOrgRepo.GetByUserIDAndOrganisationID(member.ID, org.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errors.New("member not found")
+		}
+		return nil, nil, err
+	}
+
+	return member, mapping, nil
+}
+
+func (s *organisationService) GetMembers(orgUUID string, userID int) ([]*contracts.Member, error) {
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, err := s.userOrgRepo.GetByOrganisationID(org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*contracts.Member, 0, len(mappings))
+	for _, mapping := range mappings {
+		user, err := s.userRepo.GetByID(mapping.UserID)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &contracts.Member{
+			UserUUID: user.UUID,
+			Email:    user.Email,
+			Role:     mapping.Role,
+			IsOwner:  user.ID == org.UserID,
+			JoinedAt: mapping.CreatedAt,
+		})
+	}
+
+	return members, nil
+}
+
+// RemoveMember removes a member from the organisation. Admins can remove anyone
+// but the owner; every member can remove themselves to leave the organisation.
+func (s *organisationService) RemoveMember(orgUUID, memberUUID string, userID int, client contracts.ClientInfo) error {
+	action := ActionManage
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.UUID == memberUUID {
+		action = ActionView
+	}
+
+	org, err := s.getOrganisationForUser(orgUUID, userID, action)
+	if err != nil {
+		return err
+	}
+
+	member, mapping, err := s.getMember(org, memberUUID)
+	if err != nil {
+		return err
+	}
+
+	if member.ID == org.UserID {
+		return errors.New("the organisation owner cannot be removed")
+	}
+
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.UserOrgMapping.Delete(member.ID, org.ID, user.UUID); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			Action:         AuditActionDelete,
+			EntityType:     AuditEntityMember,
+			EntityUUID:     member.UUID,
+			Before:         memberAuditFields(member, mapping.Role),
+		})
+	})
+}
+
+func (s *organisationService) UpdateMemberRole(orgUUID, memberUUID string, req *contracts.UpdateMemberRoleRequest, userID int, client contracts.ClientInfo) (*contracts.Member, error) {
+	if !isValidRole(req.Role) || req.Role == RoleOwner {
+		return nil, ErrInvalidRole
+	}
+
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	member, mapping, err := s.getMember(org, memberUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.ID == org.UserID {
+		return nil, errors.New("the organisation owner's role cannot be changed")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.UserOrgMapping.UpdateRole(member.ID, org.ID, req.Role, user.UUID); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			Action:         AuditActionUpdate,
+			EntityType:     AuditEntityMember,
+			EntityUUID:     member.UUID,
+			Before:         memberAuditFields(member, mapping.Role),
+			After:          memberAuditFields(member, req.Role),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Member{
+		UserUUID: member.UUID,
+		Email:    member.Email,
+		Role:     req.Role,
+		IsOwner:  false,
+		JoinedAt: mapping.CreatedAt,
+	}, nil
+}
+
+func (s *organisationService) CreateInvitation(orgUUID string, req *contracts.CreateInvitationRequest, userID int) (*contracts.CreatedInvitation, error) {
+	role := req.Role
+	if role == "" {
+		role = RoleEditor
+	}
+	if !isValidRole(role) || role == RoleOwner {
+		return nil, ErrInvalidRole
+	}
+
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if invitee, err := s.userRepo.GetByEmail(email); err == nil {
+		belongs, err := s.userOrgRepo.CheckUserInOrganisation(invitee.ID, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		if belongs {
+			return nil, ErrAlreadyMember
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &models.OrganisationInvitation{
+		OrganisationID: org.ID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      tokenHash,
+		InvitedBy:      userID,
+		ExpiresAt:      now.Add(invitationTTL),
+		Base: models.Base{
+			CreatedAt: &now,
+			UpdatedAt: &now,
+			CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+			UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+		},
+	}
+
+	// Inviting the same address again replaces the previous invitation, which
+	// doubles as "resend".
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Invitation.RevokePendingByEmail(org.ID, email, user.UUID); err != nil {
+			return err
+		}
+		return repos.Invitation.Create(invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.CreatedInvitation{
+		Invitation: contracts.Invitation{
+			UUID:             invitation.UUID,
+			Email:            invitation.Email,
+			Role:             invitation.Role,
+			OrganisationUUID: org.UUID,
+			InvitedByUUID:    user.UUID,
+			ExpiresAt:        invitation.ExpiresAt,
+			CreatedAt:        invitation.CreatedAt,
+		},
+		Token: token,
+	}, nil
+}
+
+func (s *organisationService) GetPendingInvitations(orgUUID string, userID int) ([]*contracts.Invitation, error) {
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.GetPendingByOrganisationID(org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*contracts.Invitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		inviter, err := s.userRepo.GetByID(invitation.InvitedBy)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &contracts.Invitation{
+			UUID:             invitation.UUID,
+			Email:            invitation.Email,
+			Role:             invitation.Role,
+			OrganisationUUID: org.UUID,
+			InvitedByUUID:    inviter.UUID,
+			ExpiresAt:        invitation.ExpiresAt,
+			CreatedAt:        invitation.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *organisationService) RevokeInvitation(orgUUID, invitationUUID string, userID int) error {
+	org, err := s.getOrganisationForUser(orgUUID, userID, ActionManage)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.GetByUUID(invitationUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("invitation not found")
+		}
+		return err
+	}
+	if invitation.OrganisationID != org.ID || invitation.AcceptedAt != nil {
+		return errors.New("invitation not found")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.invitationRepo.Revoke(invitation.ID, user.UUID)
+}
+
+func (s *organisationService) AcceptInvitation(req *contracts.AcceptInvitationRequest, userID int, client contracts.ClientInfo) (*contracts.Organisation, error) {
+	invitation, err := s.invitationRepo.GetByTokenHash(hashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, errors.New("invitation has expired or was already used")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.New("invitation was issued to a different email address")
+	}
+
+	org, err := s.repo.GetByID(invitation.OrganisationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("organisation not found")
+		}
+		return nil, err
+	}
+
+	// The invitation stays pending, so a member who joined another way does
+	// not lose their current role by accepting it.
+	belongs, err := s.userOrgRepo.CheckUserInOrganisation(userID, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	if belongs {
+		return nil, ErrAlreadyMember
+	}
+
+	now := time.Now()
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.UserOrgMapping.Create(&models.UserOrganisationMapping{
+			UserID:         userID,
+			OrganisationID: org.ID,
+			Role:           invitation.Role,
+			Base: models.Base{
+				CreatedAt: &now,
+				UpdatedAt: &now,
+				CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+				UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+			},
+		}); err != nil {
+			return err
+		}
+		if err := repos.Invitation.MarkAccepted(invitation.ID, userID, user.UUID); err != nil {
+			// Accepted or revoked since it was read; the membership is rolled back
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.New("invitation has expired or was already used")
+			}
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			Action:         AuditActionCreate,
+			EntityType:     AuditEntityMember,
+			EntityUUID:     user.UUID,
+			After:          memberAuditFields(user, invitation.Role),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(org.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Organisation{
+		ID:        org.ID,
+		UUID:      org.UUID,
+		Name:      org.Name,
+		UserUUID:  owner.UUID,
+		Role:      invitation.Role,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+	"github.com/crudboxin/crudbox/internal/repository/memory"
+)
+
+func TestMemberPermissions(t *testing.T) {
+	managers := []string{RoleAdmin, RoleOwner}
+	tests := []struct {
+		name    string
+		allowed []string
+		run     func(env *testEnv, team *team, userID int) error
+	}{
+		{
+			name:    "list members",
+			allowed: testRoles,
+			run: func(env *testEnv, team *team, userID int) error {
+				_, err := env.services.Organisation.GetMembers(team.org.UUID, userID)
+				return err
+			},
+		},
+		{
+			name:    "invite",
+			allowed: managers,
+			run: func(env *testEnv, team *team, userID int) error {
+				_, err := env.services.Organisation.CreateInvitation(team.org.UUID, &contracts.CreateInvitationRequest{Email: "new@example.com"}, userID)
+				return err
+			},
+		},
+		{
+			name:    "list invitations",
+			allowed: managers,
+			run: func(env *testEnv, team *team, userID int) error {
+				_, err := env.services.Organisation.GetPendingInvitations(team.org.UUID, userID)
+				return err
+			},
+		},
+		{
+			name:    "revoke invitation",
+			allowed: managers,
+			run: func(env *testEnv, team *team, userID int) error {
+				invitation, err := env.services.Organisation.CreateInvitation(team.org.UUID, &contracts.CreateInvitationRequest{Email: "new@example.com"}, team.members[RoleOwner].ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return env.services.Organisation.RevokeInvitation(team.org.UUID, invitation.UUID, userID)
+			},
+		},
+		{
+			name:    "change role",
+			allowed: managers,
+			run: func(env *testEnv, team *team, userID int) error {
+				target := env.addMember(team.org, team.members[RoleOwner], "target@example.com", RoleViewer)
+				_, err := env.services.Organisation.UpdateMemberRole(team.org.UUID, target.UUID, &contracts.UpdateMemberRoleRequest{Role: RoleEditor}, userID, testClient)
+				return err
+			},
+		},
+		{
+			name:    "remove member",
+			allowed: managers,
+			run: func(env *testEnv, team *team, userID int) error {
+				target := env.addMember(team.org, team.members[RoleOwner], "target@example.com", RoleViewer)
+				return env.services.Organisation.RemoveMember(team.org.UUID, target.UUID, userID, testClient)
+			},
+		},
+		{
+			name:    "delete organisation",
+			allowed: []string{RoleOwner},
+			run: func(env *testEnv, team *team, userID int) error {
+				return env.services.Organisation.DeleteOrganisation(team.org.UUID, userID, testClient)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, role := range testRoles {
+			t.Run(tt.name+"/"+role, func(t *testing.T) {
+				env := newTestEnv(t)
+				team := env.createTeam()
+
+				err := tt.run(env, team, team.members[role].ID)
+				if slices.Contains(tt.allowed, role) {
+					if err != nil {
+						t.Errorf("error = %v, want allowed", err)
+					}
+				} else if !errors.Is(err, ErrInsufficientPermissions) {
+					t.Errorf("error = %v, want ErrInsufficientPermissions", err)
+				}
+			})
+		}
+
+		t.Run(tt.name+"/outsider", func(t *testing.T) {
+			env := newTestEnv(t)
+			team := env.createTeam()
+			outsider := env.createUser("outsider@example.com")
+
+			if err := tt.run(env, team, outsider.ID); !errors.Is(err, ErrNotMember) {
+				t.Errorf("error = %v, want ErrNotMember", err)
+			}
+		})
+	}
+}
+
+func TestMembersCanLeave(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	viewer := team.members[RoleViewer]
+
+	if err := env.services.Organisation.RemoveMember(team.org.UUID, viewer.UUID, viewer.ID, testClient); err != nil {
+		t.Fatalf("RemoveMember(self) error = %v", err)
+	}
+	if _, err := env.services.Organisation.GetMembers(team.org.UUID, viewer.ID); !errors.Is(err, ErrNotMember) {
+		t.Errorf("GetMembers() after leaving error = %v, want ErrNotMember", err)
+	}
+}
+
+func TestOwnerCannotBeRemovedOrDemoted(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	owner, admin := team.members[RoleOwner], team.members[RoleAdmin]
+
+	if err := env.services.Organisation.RemoveMember(team.org.UUID, owner.UUID, admin.ID, testClient); err == nil {
+		t.Error("RemoveMember(owner) succeeded, want an error")
+	}
+	if _, err := env.services.Organisation.UpdateMemberRole(team.org.UUID, owner.UUID, &contracts.UpdateMemberRoleRequest{Role: RoleViewer}, admin.ID, testClient); err == nil {
+		t.Error("UpdateMemberRole(owner) succeeded, want an error")
+	}
+	if _, err := env.services.Organisation.UpdateMemberRole(team.org.UUID, admin.UUID, &contracts.UpdateMemberRoleRequest{Role: RoleOwner}, owner.ID, testClient); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("UpdateMemberRole(role owner) error = %v, want ErrInvalidRole", err)
+	}
+}
+
+func TestInvitingAMemberConflicts(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+
+	_, err := env.services.Organisation.CreateInvitation(team.org.UUID, &contracts.CreateInvitationRequest{Email: "viewer@example.com"}, team.members[RoleOwner].ID)
+	if !errors.Is(err, ErrAlreadyMember) {
+		t.Errorf("CreateInvitation() error = %v, want ErrAlreadyMember", err)
+	}
+}
+
+func TestAcceptInvitationAsMemberConflicts(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	owner := team.members[RoleOwner]
+	user := env.createUser("late@example.com")
+
+	invitation, err := env.services.Organisation.CreateInvitation(team.org.UUID, &contracts.CreateInvitationRequest{Email: user.Email, Role: RoleAdmin}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The user joins another way while the invitation is pending
+	now := time.Now()
+	if err := env.repos.UserOrgMapping.Create(&models.UserOrganisationMapping{
+		UserID:         user.ID,
+		OrganisationID: team.org.ID,
+		Role:           RoleViewer,
+		Base:           models.Base{CreatedAt: &now, UpdatedAt: &now, CreatedBy: sql.NullString{String: owner.UUID, Valid: true}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.services.Organisation.AcceptInvitation(&contracts.AcceptInvitationRequest{Token: invitation.Token}, user.ID, testClient)
+	if !errors.Is(err, ErrAlreadyMember) {
+		t.Fatalf("AcceptInvitation() error = %v, want ErrAlreadyMember", err)
+	}
+
+	mapping, err := env.repos.UserOrgMapping.GetByUserIDAndOrganisationID(user.ID, team.org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.Role != RoleViewer {
+		t.Errorf("role after the rejected invitation = %s, want %s", mapping.Role, RoleViewer)
+	}
+}
+
+func TestAcceptInvitationChecksEmail(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	other := env.createUser("other@example.com")
+
+	invitation, err := env.services.Organisation.CreateInvitation(team.org.UUID, &contracts.CreateInvitationRequest{Email: "invited@example.com"}, team.members[RoleOwner].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.services.Organisation.AcceptInvitation(&contracts.AcceptInvitationRequest{Token: invitation.Token}, other.ID, testClient); err == nil {
+		t.Error("AcceptInvitation() by another user succeeded, want an error")
+	}
+}
+
+// racingInvitations lets the invitation change between its lookup and its
+// acceptance, as a concurrent request could.
+type racingInvitations struct {
+	repository.OrganisationInvitationRepository
+	race func()
+}
+
+func (r *racingInvitations) GetByTokenHash(tokenHash string) (*models.OrganisationInvitation, error) {
+	invitation, err := r.OrganisationInvitationRepository.GetByTokenHash(tokenHash)
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+	return invitation, err
+}
+
+func TestAcceptRevokedInvitationFails(t *testing.T) {
+	repos := memory.NewRepositories()
+	invitations := &racingInvitations{OrganisationInvitationRepository: repos.Invitation}
+	repos.Invitation = invitations
+	env := newTestEnvWithRepos(t, repos, NewMockHosts(""))
+	team := env.createTeam()
+	owner := team.members[RoleOwner]
+	user := env.createUser("invited@example.com")
+
+	invitation, err := env.services.Organisation.CreateInvitation(team.org.UUID, &contracts.CreateInvitationRequest{Email: user.Email}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invitations.race = func() {
+		if err := env.services.Organisation.RevokeInvitation(team.org.UUID, invitation.UUID, owner.ID); err != nil {
+			t.Fatalf("RevokeInvitation() error = %v", err)
+		}
+	}
+	if _, err := env.services.Organisation.AcceptInvitation(&contracts.AcceptInvitationRequest{Token: invitation.Token}, user.ID, testClient); err == nil {
+		t.Fatal("AcceptInvitation() of a revoked invitation succeeded, want an error")
+	}
+
+	belongs, err := env.repos.UserOrgMapping.CheckUserInOrganisation(user.ID, team.org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if belongs {
+		t.Error("user joined through a revoked invitation")
+	}
+}
+
+func TestMemberChangesAreAudited(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	owner, viewer, editor := team.members[RoleOwner], team.members[RoleViewer], team.members[RoleEditor]
+
+	if _, err := env.services.Organisation.UpdateMemberRole(team.org.UUID, viewer.UUID, &contracts.UpdateMemberRoleRequest{Role: RoleEditor}, owner.ID, testClient); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.services.Organisation.RemoveMember(team.org.UUID, editor.UUID, owner.ID, testClient); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := env.services.Organisation.GetAuditLog(team.org.UUID, &contracts.AuditLogQuery{EntityType: AuditEntityMember}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct{ action, entity string }
+	var got []event
+	for _, entry := range page.Entries {
+		got = append(got, event{entry.Action, entry.EntityUUID})
+	}
+	// Newest first: the removal, the role change, then the three invitations
+	// accepted by createTeam
+	want := []event{
+		{AuditActionDelete, editor.UUID},
+		{AuditActionUpdate, viewer.UUID},
+		{AuditActionCreate, viewer.UUID},
+		{AuditActionCreate, editor.UUID},
+		{AuditActionCreate, team.members[RoleAdmin].UUID},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("member audit entries = %v, want %v", got, want)
+	}
+
+	roleChange := page.Entries[1].Changes["role"]
+	if roleChange.Before != RoleViewer || roleChange.After != RoleEditor {
+		t.Errorf("role change = %v -> %v, want %s -> %s", roleChange.Before, roleChange.After, RoleViewer, RoleEditor)
+	}
+	if page.Entries[0].IPAddress != testClient.IPAddress {
+		t.Errorf("IP address = %q, want %q", page.Entries[0].IPAddress, testClient.IPAddress)
+	}
+}
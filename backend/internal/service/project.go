@@ -12,27 +12,76 @@ import (
 	"github.com/crudboxin/crudbox/internal/repository"
 )
 
-const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+// charset only has characters valid in project codes, so generated codes can be
+// served on subdomains like chosen ones.
+const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+var (
+	ErrUnsupportedBundleVersion = errors.New("unsupported project bundle version")
+	ErrInvalidProjectBundle     = errors.New("invalid project bundle")
+)
 
 type projectService struct {
-	repo         repository.ProjectRepository
-	userRepo     repository.UserRepository
-	orgRepo      repository.OrganisationRepository
-	endpointRepo repository.EndpointRepository
-	userOrgRepo  repository.UserOrganisationMappingRepository
+	repo           repository.ProjectRepository
+	userRepo       repository.UserRepository
+	orgRepo        repository.OrganisationRepository
+	endpointRepo   repository.EndpointRepository
+	userOrgRepo    repository.UserOrganisationMappingRepository
+	redirectRepo   repository.ProjectCodeRedirectRepository
+	versionRepo    repository.ProjectVersionRepository
+	envRepo        repository.ProjectEnvironmentRepository
+	branchRepo     repository.ProjectBranchRepository
+	syncRepo       repository.ProjectSyncRepository
+	requestLogRepo repository.RequestLogRepository
+	requests       *requestRecorder
+	transactor     repository.Transactor
+	hosts          MockHosts
+	auth           authorizer
 }
 
-func NewProjectService(repo repository.ProjectRepository, userRepo repository.UserRepository, orgRepo repository.OrganisationRepository, userOrgRepo repository.UserOrganisationMappingRepository, endpointRepo repository.EndpointRepository) ProjectService {
-	return &projectService{
-		repo:         repo,
-		userRepo:     userRepo,
-		orgRepo:      orgRepo,
-		userOrgRepo:  userOrgRepo,
-		endpointRepo: endpointRepo,
+func NewProjectService(repo repository.ProjectRepository, userRepo repository.UserRepository, orgRepo repository.OrganisationRepository, userOrgRepo repository.UserOrganisationMappingRepository, endpointRepo repository.EndpointRepository, redirectRepo repository.ProjectCodeRedirectRepository, versionRepo repository.ProjectVersionRepository, envRepo repository.ProjectEnvironmentRepository, branchRepo repository.ProjectBranchRepository, syncRepo repository.ProjectSyncRepository, requestLogRepo repository.RequestLogRepository, transactor repository.Transactor, hosts MockHosts) ProjectService {
+	s := &projectService{
+		repo:           repo,
+		redirectRepo:   redirectRepo,
+		userRepo:       userRepo,
+		orgRepo:        orgRepo,
+		userOrgRepo:    userOrgRepo,
+		endpointRepo:   endpointRepo,
+		versionRepo:    versionRepo,
+		envRepo:        envRepo,
+		branchRepo:     branchRepo,
+		syncRepo:       syncRepo,
+		requestLogRepo: requestLogRepo,
+		transactor:     transactor,
+		hosts:          hosts,
+		auth:           authorizer{userOrgRepo: userOrgRepo},
+	}
+	if requestLogRepo != nil {
+		s.requests = newRequestRecorder(requestLogRepo)
+	}
+	return s
+}
+
+// getProjectForUser loads a project and checks that userID may perform action on it.
+func (s *projectService) getProjectForUser(projectUUID string, userID int, action Action) (*models.Project, error) {
+	project, err := s.repo.GetByUUID(projectUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+
+	if err := s.auth.authorizeProject(project, userID, action); err != nil {
+		return nil, err
 	}
+
+	return project, nil
 }
 
-func (s *projectService) generateUniqueCode(repo repository.ProjectRepository) string {
+// generateUniqueCode picks a random code that is neither reserved nor used by a
+// project or redirect. Callers inside a transaction pass its repositories.
+func generateUniqueCode(projects repository.ProjectRepository, redirects repository.ProjectCodeRedirectRepository) string {
 	for {
 		code := make([]byte, 5)
 		rand.Read(code)
@@ -40,16 +89,22 @@ func (s *projectService) generateUniqueCode(repo repository.ProjectRepository) s
 			code[i] = charset[code[i]%byte(len(charset))]
 		}
 		codeStr := string(code)
+		if isReservedProjectCode(codeStr) {
+			continue
+		}
+		if _, err := redirects.GetByCode(codeStr); err == nil {
+			continue
+		}
 
 		// Check if code exists
-		_, err := repo.GetByCode(codeStr)
+		_, err := projects.GetByCode(codeStr)
 		if err != nil {
 			return codeStr
 		}
 	}
 }
 
-func (s *projectService) CreateProject(req *contracts.CreateProjectRequest, userID int) (*contracts.Project, error) {
+func (s *projectService) CreateProject(req *contracts.CreateProjectRequest, userID int, client contracts.ClientInfo) (*contracts.Project, error) {
 	org, err := s.orgRepo.GetByUUID(req.OrganisationUUID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -58,12 +113,24 @@ func (s *projectService) CreateProject(req *contracts.CreateProjectRequest, user
 		return nil, err
 	}
 
-	belongs, err := s.userOrgRepo.CheckUserInOrganisation(userID, org.ID)
-	if err != nil {
+	if err := s.auth.authorize(userID, org.ID, ActionEdit); err != nil {
 		return nil, err
 	}
-	if !belongs {
-		return nil, errors.New("user does not belong to the specified organisation")
+
+	code := req.Code
+	if code != "" {
+		if err := validateProjectCode(code); err != nil {
+			return nil, err
+		}
+		available, err := s.projectCodeAvailable(code, 0)
+		if err != nil {
+			return nil, err
+		}
+		if !available {
+			return nil, ErrProjectCodeTaken
+		}
+	} else {
+		code = generateUniqueCode(s.repo, s.redirectRepo)
 	}
 
 	user, err := s.userRepo.GetByID(userID)
@@ -74,7 +141,7 @@ func (s *projectService) CreateProject(req *contracts.CreateProjectRequest, user
 	now := time.Now()
 	dbProject := &models.Project{
 		Name:           req.Name,
-		Code:           s.generateUniqueCode(s.repo),
+		Code:           code,
 		UserID:         userID,
 		OrganisationID: org.ID,
 		Base: models.Base{
@@ -85,7 +152,19 @@ func (s *projectService) CreateProject(req *contracts.CreateProjectRequest, user
 		},
 	}
 
-	err = s.repo.Create(dbProject)
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Project.Create(dbProject); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			ProjectUUID:    dbProject.UUID,
+			Action:         AuditActionCreate,
+			EntityType:     AuditEntityProject,
+			EntityUUID:     dbProject.UUID,
+			After:          projectAuditFields(dbProject, org.UUID),
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +174,7 @@ func (s *projectService) CreateProject(req *contracts.CreateProjectRequest, user
 		UUID:             dbProject.UUID,
 		Name:             dbProject.Name,
 		Code:             dbProject.Code,
+		Hostname:         dbProject.Hostname.String,
 		UserUUID:         user.UUID,
 		OrganisationUUID: org.UUID,
 		CreatedAt:        dbProject.CreatedAt,
@@ -123,6 +203,7 @@ func (s *projectService) GetByCode(code string) (*contracts.Project, error) {
 		UUID:             dbProject.UUID,
 		Name:             dbProject.Name,
 		Code:             dbProject.Code,
+		Hostname:         dbProject.Hostname.String,
 		UserUUID:         user.UUID,
 		OrganisationUUID: org.UUID,
 		CreatedAt:        dbProject.CreatedAt,
@@ -130,6 +211,52 @@ func (s *projectService) GetByCode(code string) (*contracts.Project, error) {
 	}, nil
 }
 
+// GetBySubdomain returns the project served on the subdomain label. Projects
+// whose generated code predates lowercase codes cannot be told apart by a
+// case-insensitive host name and get ErrCodeNotSubdomainSafe instead.
+func (s *projectService) GetBySubdomain(label string) (*contracts.Project, error) {
+	project, err := s.GetByCode(label)
+	if err == nil || !errors.Is(err, sql.ErrNoRows) {
+		return project, err
+	}
+
+	if _, legacyErr := s.repo.GetByCodeIgnoringCase(label); legacyErr == nil {
+		return nil, ErrCodeNotSubdomainSafe
+	}
+	return nil, err
+}
+
+func (s *projectService) GetByHostname(hostname string) (*contracts.Project, error) {
+	dbProject, err := s.repo.GetByHostname(NormalizeHostname(hostname))
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Project{
+		ID:       dbProject.ID,
+		UUID:     dbProject.UUID,
+		Name:     dbProject.Name,
+		Code:     dbProject.Code,
+		Hostname: dbProject.Hostname.String,
+	}, nil
+}
+
+// ResolveCodeRedirect returns the current code of the project that used to be
+// served under oldCode.
+func (s *projectService) ResolveCodeRedirect(oldCode string) (string, error) {
+	redirect, err := s.redirectRepo.GetByCode(oldCode)
+	if err != nil {
+		return "", err
+	}
+
+	project, err := s.repo.GetByID(redirect.ProjectID)
+	if err != nil {
+		return "", err
+	}
+
+	return project.Code, nil
+}
+
 func (s *projectService) GetByUUID(uuid string) (*contracts.Project, error) {
 	dbProject, err := s.repo.GetByUUID(uuid)
 	if err != nil {
@@ -154,6 +281,7 @@ func (s *projectService) GetByUUID(uuid string) (*contracts.Project, error) {
 		UUID:             dbProject.UUID,
 		Name:             dbProject.Name,
 		Code:             dbProject.Code,
+		Hostname:         dbProject.Hostname.String,
 		UserUUID:         user.UUID,
 		OrganisationUUID: org.UUID,
 		CreatedAt:        dbProject.CreatedAt,
@@ -187,6 +315,7 @@ func (s *projectService) GetByUserID(userID int) ([]*contracts.Project, error) {
 				UUID:             proj.UUID,
 				Name:             proj.Name,
 				Code:             proj.Code,
+				Hostname:         proj.Hostname.String,
 				UserUUID:         owner.UUID,
 				OrganisationUUID: org.UUID,
 				CreatedAt:        proj.CreatedAt,
@@ -198,24 +327,404 @@ func (s *projectService) GetByUserID(userID int) ([]*contracts.Project, error) {
 	return projects, nil
 }
 
-func (s *projectService) DeleteProject(projectUUID string, userID int) error {
-	project, err := s.repo.GetByUUIDForUser(projectUUID, userID)
+func (s *projectService) UpdateProject(projectUUID string, req *contracts.UpdateProjectRequest, userID int, client contracts.ClientInfo) (*contracts.Project, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(project.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousOrgID := org.ID
+	before := projectAuditFields(project, org.UUID)
+
+	if req.OrganisationUUID != nil && *req.OrganisationUUID != org.UUID {
+		// Moving a project removes it from one organisation and adds it to
+		// another, so it needs the right to manage projects in both.
+		if err := s.auth.authorize(userID, org.ID, ActionManage); err != nil {
+			return nil, err
+		}
+
+		targetOrg, err := s.orgRepo.GetByUUID(*req.OrganisationUUID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, errors.New("organisation not found")
+			}
+			return nil, err
+		}
+
+		if err := s.auth.authorize(userID, targetOrg.ID, ActionManage); err != nil {
+			return nil, err
+		}
+
+		org = targetOrg
+		project.OrganisationID = targetOrg.ID
+	}
+
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+
+	oldCode := project.Code
+	if req.Code != nil && *req.Code != project.Code {
+		if req.RegenerateCode {
+			return nil, fmt.Errorf("%w: code and regenerate_code cannot be combined", ErrInvalidProjectCode)
+		}
+		if err := validateProjectCode(*req.Code); err != nil {
+			return nil, err
+		}
+		available, err := s.projectCodeAvailable(*req.Code, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !available {
+			return nil, ErrProjectCodeTaken
+		}
+		project.Code = *req.Code
+	}
+	if req.RegenerateCode {
+		project.Code = generateUniqueCode(s.repo, s.redirectRepo)
+	}
+
+	if req.Hostname != nil {
+		// A hostname answers for every path on the host, so only admins set it
+		if err := s.auth.authorize(userID, project.OrganisationID, ActionManage); err != nil {
+			return nil, err
+		}
+
+		hostname := NormalizeHostname(*req.Hostname)
+		if hostname == "" {
+			project.Hostname = sql.NullString{}
+		} else {
+			if err := s.hosts.validateHostname(hostname); err != nil {
+				return nil, err
+			}
+			existing, err := s.repo.GetByHostname(hostname)
+			if err == nil && existing.ID != project.ID {
+				return nil, ErrHostnameTaken
+			}
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+			project.Hostname = sql.NullString{String: hostname, Valid: true}
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	project.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
+	project.UpdatedAt = &now
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		switch {
+		case req.RegenerateCode:
+			// A regenerated code is meant to retire leaked URLs, so every
+			// previous code of the project stops resolving.
+			if err := repos.CodeRedirect.DeleteByProjectID(project.ID); err != nil {
+				return err
+			}
+		case project.Code != oldCode:
+			if err := repos.CodeRedirect.DeleteByCode(project.Code); err != nil {
+				return err
+			}
+			if err := repos.CodeRedirect.Create(&models.ProjectCodeRedirect{
+				Code:      oldCode,
+				ProjectID: project.ID,
+				Base: models.Base{
+					CreatedAt: &now,
+					UpdatedAt: &now,
+					CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+					UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.Project.Update(project); err != nil {
+			return err
+		}
+
+		// A project moved between organisations shows up in the log of both.
+		event := auditEvent{
+			OrganisationID: org.ID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionUpdate,
+			EntityType:     AuditEntityProject,
+			EntityUUID:     project.UUID,
+			Before:         before,
+			After:          projectAuditFields(project, org.UUID),
+		}
+		audit := auditor{repo: repos.AuditLog}
+		if err := audit.record(user, client, event); err != nil {
+			return err
+		}
+		if previousOrgID != org.ID {
+			event.OrganisationID = previousOrgID
+			return audit.record(user, client, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(project.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Project{
+		ID:               project.ID,
+		UUID:             project.UUID,
+		Name:             project.Name,
+		Code:             project.Code,
+		Hostname:         project.Hostname.String,
+		UserUUID:         owner.UUID,
+		OrganisationUUID: org.UUID,
+		CreatedAt:        project.CreatedAt,
+		UpdatedAt:        project.UpdatedAt,
+	}, nil
+}
+
+func (s *projectService) DeleteProject(projectUUID string, userID int, client contracts.ClientInfo) error {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionManage)
+	if err != nil {
+		if err.Error() == "project not found" {
 			return fmt.Errorf("no project found")
 		}
 		return err
 	}
 
-	err = s.endpointRepo.DeleteByProjectID(project.ID, userID)
+	org, err := s.orgRepo.GetByID(project.OrganisationID)
 	if err != nil {
 		return err
 	}
 
-	err = s.repo.DeleteByUUID(projectUUID, userID)
+	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// The endpoints share the project's deletion time so that restoring the
+	// project brings back exactly these endpoints
+	now := time.Now()
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Endpoint.DeleteByProjectID(project.ID, user.UUID, now); err != nil {
+			return err
+		}
+		if err := repos.Project.DeleteByUUID(projectUUID, user.UUID, now); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionDelete,
+			EntityType:     AuditEntityProject,
+			EntityUUID:     project.UUID,
+			Before:         projectAuditFields(project, org.UUID),
+		})
+	})
+}
+
+func (s *projectService) ExportProject(projectUUID string, userID int) (*contracts.ProjectBundle, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := s.endpointRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bundle := &contracts.ProjectBundle{
+		Version:    contracts.ProjectBundleVersion,
+		ExportedAt: &now,
+		Project: contracts.ProjectBundleProject{
+			Name: project.Name,
+			Code: project.Code,
+		},
+		Endpoints: make([]contracts.ProjectBundleEndpoint, 0, len(endpoints)),
+	}
+	for _, endpoint := range endpoints {
+		bundle.Endpoints = append(bundle.Endpoints, contracts.ProjectBundleEndpoint{
+			Method:          endpoint.Method,
+			Path:            endpoint.Path,
+			ResponseBody:    endpoint.ResponseBody,
+			ResponseStatus:  endpoint.ResponseStatus,
+			ResponseHeaders: endpoint.ResponseHeaders,
+		})
+	}
+
+	return bundle, nil
+}
+
+func (s *projectService) ImportProject(orgUUID string, bundle *contracts.ProjectBundle, userID int, client contracts.ClientInfo) (*contracts.Project, error) {
+	if bundle.Version != contracts.ProjectBundleVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedBundleVersion, bundle.Version)
+	}
+
+	seen := make(map[string]struct{}, len(bundle.Endpoints))
+	for _, endpoint := range bundle.Endpoints {
+		key := endpoint.Method + "::" + endpoint.Path
+		if _, exists := seen[key]; exists {
+			return nil, fmt.Errorf("%w: duplicate endpoint %s %s", ErrInvalidProjectBundle, endpoint.Method, endpoint.Path)
+		}
+		seen[key] = struct{}{}
+	}
+
+	endpoints := make([]*models.Endpoint, 0, len(bundle.Endpoints))
+	for _, endpoint := range bundle.Endpoints {
+		endpoints = append(endpoints, &models.Endpoint{
+			Method:          endpoint.Method,
+			Path:            endpoint.Path,
+			ResponseBody:    endpoint.ResponseBody,
+			ResponseStatus:  endpoint.ResponseStatus,
+			ResponseHeaders: endpoint.ResponseHeaders,
+		})
+	}
+
+	return s.createProjectWithEndpoints(orgUUID, bundle.Project.Name, endpoints, userID, client, nil)
+}
+
+func (s *projectService) CloneProject(projectUUID string, req *contracts.CloneProjectRequest, userID int, client contracts.ClientInfo) (*contracts.Project, error) {
+	source, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	orgUUID := req.OrganisationUUID
+	if orgUUID == "" {
+		org, err := s.orgRepo.GetByID(source.OrganisationID)
+		if err != nil {
+			return nil, err
+		}
+		orgUUID = org.UUID
+	}
+
+	name := req.Name
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+
+	endpoints, err := s.endpointRepo.GetByProjectID(source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createProjectWithEndpoints(orgUUID, name, endpoints, userID, client, nil)
+}
+
+// createProjectWithEndpoints creates a new project with a fresh code in the given
+// organisation and copies the method, path and response of each endpoint into it.
+// Everything is written in a single transaction, including whatever a non-nil
+// afterCreate writes for the new project.
+func (s *projectService) createProjectWithEndpoints(orgUUID, name string, endpoints []*models.Endpoint, userID int, client contracts.ClientInfo, afterCreate func(repos *repository.Repositories, project *models.Project, user *models.User) error) (*contracts.Project, error) {
+	org, err := s.orgRepo.GetByUUID(orgUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("organisation not found")
+		}
+		return nil, err
+	}
+
+	if err := s.auth.authorize(userID, org.ID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	base := models.Base{
+		CreatedAt: &now,
+		UpdatedAt: &now,
+		CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+		UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+	}
+
+	dbProject := &models.Project{
+		Name:           name,
+		UserID:         userID,
+		OrganisationID: org.ID,
+		Base:           base,
+	}
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		dbProject.Code = generateUniqueCode(repos.Project, repos.CodeRedirect)
+		if err := repos.Project.Create(dbProject); err != nil {
+			return err
+		}
+
+		audit := auditor{repo: repos.AuditLog}
+		if err := audit.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			ProjectUUID:    dbProject.UUID,
+			Action:         AuditActionCreate,
+			EntityType:     AuditEntityProject,
+			EntityUUID:     dbProject.UUID,
+			After:          projectAuditFields(dbProject, org.UUID),
+		}); err != nil {
+			return err
+		}
+
+		for _, endpoint := range endpoints {
+			copied := &models.Endpoint{
+				Method:          endpoint.Method,
+				Path:            endpoint.Path,
+				ResponseBody:    endpoint.ResponseBody,
+				ResponseStatus:  endpoint.ResponseStatus,
+				ResponseHeaders: endpoint.ResponseHeaders,
+				ProjectID:       dbProject.ID,
+				Base:            base,
+			}
+			if err := repos.Endpoint.Create(copied); err != nil {
+				return err
+			}
+			if err := recordRevision(repos.Revision, copied, user, 0); err != nil {
+				return err
+			}
+			if err := audit.record(user, client, auditEvent{
+				OrganisationID: org.ID,
+				ProjectUUID:    dbProject.UUID,
+				Action:         AuditActionCreate,
+				EntityType:     AuditEntityEndpoint,
+				EntityUUID:     copied.UUID,
+				After:          endpointAuditFields(copied),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if afterCreate != nil {
+			return afterCreate(repos, dbProject, user)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Project{
+		ID:               dbProject.ID,
+		UUID:             dbProject.UUID,
+		Name:             dbProject.Name,
+		Code:             dbProject.Code,
+		Hostname:         dbProject.Hostname.String,
+		UserUUID:         user.UUID,
+		OrganisationUUID: org.UUID,
+		CreatedAt:        dbProject.CreatedAt,
+		UpdatedAt:        dbProject.UpdatedAt,
+	}, nil
 }
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+var (
+	ErrNotABranch           = errors.New("project is not a branch")
+	ErrUnresolvedConflicts  = errors.New("merge has unresolved conflicts")
+	ErrParentProjectMissing = errors.New("parent project not found")
+)
+
+// Statuses of a ProjectMergeEntry.
+const (
+	// MergeStatusBranch means only the branch changed the endpoint; merging
+	// applies the change to the parent.
+	MergeStatusBranch = "branch_changed"
+	// MergeStatusParent means only the parent changed the endpoint; the parent
+	// keeps its version.
+	MergeStatusParent = "parent_changed"
+	// MergeStatusSame means both sides made the same change.
+	MergeStatusSame = "same_change"
+	// MergeStatusConflict means both sides changed the endpoint differently.
+	// Merging needs a resolution.
+	MergeStatusConflict = "conflict"
+)
+
+func bundleEndpoint(method, path, body string, status int, headers string) *contracts.ProjectBundleEndpoint {
+	return &contracts.ProjectBundleEndpoint{
+		Method:          method,
+		Path:            path,
+		ResponseBody:    body,
+		ResponseStatus:  status,
+		ResponseHeaders: headers,
+	}
+}
+
+func sameBundleEndpoint(a, b *contracts.ProjectBundleEndpoint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sideDiffs returns the line diffs of the response body and headers from the
+// branch point to one side, or nil where they did not change.
+func sideDiffs(base, side *contracts.ProjectBundleEndpoint) (body, headers []contracts.DiffLine) {
+	var baseBody, baseHeaders, sideBody, sideHeaders string
+	if base != nil {
+		baseBody, baseHeaders = base.ResponseBody, base.ResponseHeaders
+	}
+	if side != nil {
+		sideBody, sideHeaders = side.ResponseBody, side.ResponseHeaders
+	}
+	if baseBody != sideBody {
+		body = diffLines(baseBody, sideBody)
+	}
+	if baseHeaders != sideHeaders {
+		headers = diffLines(baseHeaders, sideHeaders)
+	}
+	return body, headers
+}
+
+// mergeState holds both sides' endpoints and the preview built from them.
+// MergeBranch loads it inside its transaction, so it applies what it compared.
+type mergeState struct {
+	parentEndpoints map[string]*models.Endpoint
+	branchEndpoints map[string]*models.Endpoint
+	preview         *contracts.ProjectMergePreview
+}
+
+// loadMerge compares the endpoints of the branch project and its parent with
+// the branch point. Endpoints nobody changed are left out of the preview.
+func loadMerge(branchRepo repository.ProjectBranchRepository, endpointRepo repository.EndpointRepository, branch *models.ProjectBranch, branchProject, parent *models.Project) (*mergeState, error) {
+	baseEndpoints, err := branchRepo.GetBaseEndpoints(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	parentEndpoints, err := endpointRepo.GetByProjectID(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	branchEndpoints, err := endpointRepo.GetByProjectID(branchProject.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &mergeState{
+		parentEndpoints: make(map[string]*models.Endpoint, len(parentEndpoints)),
+		branchEndpoints: make(map[string]*models.Endpoint, len(branchEndpoints)),
+		preview: &contracts.ProjectMergePreview{
+			BranchProjectUUID: branchProject.UUID,
+			ParentProjectUUID: parent.UUID,
+			Entries:           []contracts.ProjectMergeEntry{},
+		},
+	}
+
+	keys := make(map[string]struct{})
+	base := make(map[string]*contracts.ProjectBundleEndpoint, len(baseEndpoints))
+	for _, endpoint := range baseEndpoints {
+		key := endpointKey(endpoint.Method, endpoint.Path)
+		base[key] = bundleEndpoint(endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders)
+		keys[key] = struct{}{}
+	}
+	for _, endpoint := range parentEndpoints {
+		key := endpointKey(endpoint.Method, endpoint.Path)
+		state.parentEndpoints[key] = endpoint
+		keys[key] = struct{}{}
+	}
+	for _, endpoint := range branchEndpoints {
+		key := endpointKey(endpoint.Method, endpoint.Path)
+		state.branchEndpoints[key] = endpoint
+		keys[key] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		entry := contracts.ProjectMergeEntry{Base: base[key]}
+		entry.Method, entry.Path, _ = strings.Cut(key, " ")
+		if endpoint, ok := state.parentEndpoints[key]; ok {
+			entry.Parent = bundleEndpoint(endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders)
+		}
+		if endpoint, ok := state.branchEndpoints[key]; ok {
+			entry.Branch = bundleEndpoint(endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders)
+		}
+
+		parentChanged := !sameBundleEndpoint(entry.Base, entry.Parent)
+		branchChanged := !sameBundleEndpoint(entry.Base, entry.Branch)
+		switch {
+		case !parentChanged && !branchChanged:
+			continue
+		case !parentChanged:
+			entry.Status = MergeStatusBranch
+		case !branchChanged:
+			entry.Status = MergeStatusParent
+		case sameBundleEndpoint(entry.Parent, entry.Branch):
+			entry.Status = MergeStatusSame
+		default:
+			entry.Status = MergeStatusConflict
+			state.preview.Conflicts++
+		}
+
+		entry.ParentBodyDiff, entry.ParentHeadersDiff = sideDiffs(entry.Base, entry.Parent)
+		entry.BranchBodyDiff, entry.BranchHeadersDiff = sideDiffs(entry.Base, entry.Branch)
+		state.preview.Entries = append(state.preview.Entries, entry)
+	}
+
+	return state, nil
+}
+
+// getBranchForUser loads a branch project, its branch record and its parent,
+// checking that userID may view the branch and perform parentAction on the
+// parent.
+func (s *projectService) getBranchForUser(projectUUID string, userID int, parentAction Action) (*models.Project, *models.ProjectBranch, *models.Project, error) {
+	branchProject, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	branch, err := s.branchRepo.GetByProjectID(branchProject.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, nil, ErrNotABranch
+		}
+		return nil, nil, nil, err
+	}
+
+	parent, err := s.repo.GetByID(branch.ParentProjectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, nil, ErrParentProjectMissing
+		}
+		return nil, nil, nil, err
+	}
+
+	if err := s.auth.authorizeProject(parent, userID, parentAction); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return branchProject, branch, parent, nil
+}
+
+func (s *projectService) projectBranchContract(branch *models.ProjectBranch, project *models.Project, parentUUID string) (*contracts.ProjectBranch, error) {
+	org, err := s.orgRepo.GetByID(project.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := s.userRepo.GetByID(project.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.ProjectBranch{
+		Project: &contracts.Project{
+			ID:               project.ID,
+			UUID:             project.UUID,
+			Name:             project.Name,
+			Code:             project.Code,
+			Hostname:         project.Hostname.String,
+			UserUUID:         owner.UUID,
+			OrganisationUUID: org.UUID,
+			CreatedAt:        project.CreatedAt,
+			UpdatedAt:        project.UpdatedAt,
+		},
+		ParentProjectUUID: parentUUID,
+		CreatedAt:         branch.CreatedAt,
+		CreatedBy:         branch.CreatedBy.String,
+		MergedAt:          branch.MergedAt,
+		MergedBy:          branch.MergedBy.String,
+	}, nil
+}
+
+// recordBranchPoint stores endpoints as the state both sides of branch are
+// compared with in the next merge.
+func recordBranchPoint(repo repository.ProjectBranchRepository, branchID int, endpoints []*models.Endpoint) error {
+	for _, endpoint := range endpoints {
+		if err := repo.CreateBaseEndpoint(&models.ProjectBranchEndpoint{
+			BranchID:        branchID,
+			Method:          endpoint.Method,
+			Path:            endpoint.Path,
+			ResponseBody:    endpoint.ResponseBody,
+			ResponseStatus:  endpoint.ResponseStatus,
+			ResponseHeaders: endpoint.ResponseHeaders,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateBranch copies a project into a new project of the same organisation,
+// served at its own code, that can later be merged back.
+func (s *projectService) CreateBranch(projectUUID string, req *contracts.CreateProjectBranchRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectBranch, error) {
+	parent, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(parent.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = parent.Name + " (branch)"
+	}
+
+	endpoints, err := s.endpointRepo.GetByProjectID(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var branch *models.ProjectBranch
+	var branchProject *models.Project
+	_, err = s.createProjectWithEndpoints(org.UUID, name, endpoints, userID, client, func(repos *repository.Repositories, project *models.Project, user *models.User) error {
+		branchProject = project
+		branch = &models.ProjectBranch{
+			ProjectID:       project.ID,
+			ParentProjectID: parent.ID,
+			CreatedAt:       project.CreatedAt,
+			CreatedBy:       sql.NullString{String: user.UUID, Valid: true},
+		}
+		if err := repos.Branch.Create(branch); err != nil {
+			return err
+		}
+		return recordBranchPoint(repos.Branch, branch.ID, endpoints)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.projectBranchContract(branch, branchProject, parent.UUID)
+}
+
+// GetBranches lists the live branches of a project.
+func (s *projectService) GetBranches(projectUUID string, userID int) ([]*contracts.ProjectBranch, error) {
+	parent, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := s.branchRepo.GetByParentProjectID(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*contracts.ProjectBranch, 0, len(branches))
+	for _, branch := range branches {
+		project, err := s.repo.GetByID(branch.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		contract, err := s.projectBranchContract(branch, project, parent.UUID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, contract)
+	}
+
+	return result, nil
+}
+
+// GetMergePreview shows what merging a branch into its parent would do, as a
+// three-way comparison against the branch point.
+func (s *projectService) GetMergePreview(projectUUID string, userID int) (*contracts.ProjectMergePreview, error) {
+	branchProject, branch, parent, err := s.getBranchForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadMerge(s.branchRepo, s.endpointRepo, branch, branchProject, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.preview, nil
+}
+
+// MergeBranch applies the endpoints the branch changed since the branch point to
+// its parent. Endpoints both sides changed differently are conflicts and need a
+// resolution keyed by "METHOD /path"; the merge fails if any is missing. The
+// merge becomes the new branch point, so the branch can be merged again later.
+func (s *projectService) MergeBranch(projectUUID string, req *contracts.MergeProjectBranchRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectMergeResult, error) {
+	branchProject, branch, parent, err := s.getBranchForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &contracts.ProjectMergeResult{}
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		state, err := loadMerge(repos.Branch, repos.Endpoint, branch, branchProject, parent)
+		if err != nil {
+			return err
+		}
+
+		var unresolved []string
+		for _, entry := range state.preview.Entries {
+			key := endpointKey(entry.Method, entry.Path)
+			if entry.Status == MergeStatusConflict && req.Resolutions[key] == "" {
+				unresolved = append(unresolved, key)
+			}
+		}
+		if len(unresolved) > 0 {
+			return fmt.Errorf("%w: %s", ErrUnresolvedConflicts, strings.Join(unresolved, ", "))
+		}
+
+		now := time.Now()
+		for _, entry := range state.preview.Entries {
+			key := endpointKey(entry.Method, entry.Path)
+			take := entry.Status == MergeStatusBranch || (entry.Status == MergeStatusConflict && req.Resolutions[key] == "branch")
+			if !take {
+				continue
+			}
+
+			action, err := syncEndpoint(repos, parent, user, client, state.parentEndpoints[key], entry.Branch, now)
+			if err != nil {
+				return err
+			}
+			switch action {
+			case AuditActionCreate:
+				result.Created++
+			case AuditActionUpdate:
+				result.Updated++
+			case AuditActionDelete:
+				result.Deleted++
+			}
+		}
+
+		// Conflicts resolved for the parent count as merged too, so the branch's
+		// current endpoints become the new branch point.
+		if err := repos.Branch.DeleteBaseEndpoints(branch.ID); err != nil {
+			return err
+		}
+		branchEndpoints := make([]*models.Endpoint, 0, len(state.branchEndpoints))
+		for _, endpoint := range state.branchEndpoints {
+			branchEndpoints = append(branchEndpoints, endpoint)
+		}
+		if err := recordBranchPoint(repos.Branch, branch.ID, branchEndpoints); err != nil {
+			return err
+		}
+		return repos.Branch.MarkMerged(branch.ID, user.UUID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+// liveEndpoints returns the endpoints of a project keyed like endpointKey.
+func (env *testEnv) liveEndpoints(project *contracts.Project, userID int) map[string]*contracts.Endpoint {
+	env.t.Helper()
+	endpoints, err := env.services.Endpoint.GetByProjectUUID(project.UUID, userID)
+	if err != nil {
+		env.t.Fatal(err)
+	}
+	live := make(map[string]*contracts.Endpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		live[endpointKey(endpoint.Method, endpoint.Path)] = endpoint
+	}
+	return live
+}
+
+func (env *testEnv) setBody(endpoint *contracts.Endpoint, userID int, body string) {
+	env.t.Helper()
+	if _, err := env.services.Endpoint.UpdateEndpoint(endpoint.UUID, &contracts.UpdateEndpointRequest{ResponseBody: &body}, userID, testClient); err != nil {
+		env.t.Fatal(err)
+	}
+}
+
+func TestMergeBranch(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor].ID
+	parent := env.createProject(team.org, team.members[RoleEditor])
+	for _, path := range []string{"/a", "/b", "/c"} {
+		env.createEndpoint(parent, team.members[RoleEditor], "GET", path, "base")
+	}
+
+	branch, err := env.services.Project.CreateBranch(parent.UUID, &contracts.CreateProjectBranchRequest{}, editor, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parentLive := env.liveEndpoints(parent, editor)
+	branchLive := env.liveEndpoints(branch.Project, editor)
+	env.setBody(branchLive["GET /a"], editor, "branch")
+	env.createEndpoint(branch.Project, team.members[RoleEditor], "POST", "/d", "branch")
+	env.setBody(parentLive["GET /b"], editor, "parent")
+	env.setBody(branchLive["GET /c"], editor, "branch")
+	env.setBody(parentLive["GET /c"], editor, "parent")
+
+	preview, err := env.services.Project.GetMergePreview(branch.Project.UUID, editor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantStatuses := []struct{ key, status string }{
+		{"GET /a", MergeStatusBranch},
+		{"GET /b", MergeStatusParent},
+		{"GET /c", MergeStatusConflict},
+		{"POST /d", MergeStatusBranch},
+	}
+	if len(preview.Entries) != len(wantStatuses) || preview.Conflicts != 1 {
+		t.Fatalf("preview = %+v, want %d entries and 1 conflict", preview, len(wantStatuses))
+	}
+	for i, want := range wantStatuses {
+		entry := preview.Entries[i]
+		if key := endpointKey(entry.Method, entry.Path); key != want.key || entry.Status != want.status {
+			t.Errorf("entry %d = %s %s, want %s %s", i, key, entry.Status, want.key, want.status)
+		}
+	}
+
+	// A conflict without a resolution fails the merge and changes nothing
+	_, err = env.services.Project.MergeBranch(branch.Project.UUID, &contracts.MergeProjectBranchRequest{}, editor, testClient)
+	if !errors.Is(err, ErrUnresolvedConflicts) {
+		t.Fatalf("MergeBranch() error = %v, want ErrUnresolvedConflicts", err)
+	}
+	if live := env.liveEndpoints(parent, editor); len(live) != 3 || live["GET /a"].ResponseBody != "base" {
+		t.Fatalf("failed merge changed the parent: %d endpoints, GET /a = %q", len(live), live["GET /a"].ResponseBody)
+	}
+
+	result, err := env.services.Project.MergeBranch(branch.Project.UUID, &contracts.MergeProjectBranchRequest{
+		Resolutions: map[string]string{"GET /c": "branch"},
+	}, editor, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 1 || result.Updated != 2 || result.Deleted != 0 {
+		t.Errorf("result = %+v, want 1 created and 2 updated", result)
+	}
+
+	wantBodies := map[string]string{"GET /a": "branch", "GET /b": "parent", "GET /c": "branch", "POST /d": "branch"}
+	live := env.liveEndpoints(parent, editor)
+	if len(live) != len(wantBodies) {
+		t.Fatalf("parent has %d endpoints after the merge, want %d", len(live), len(wantBodies))
+	}
+	for key, body := range wantBodies {
+		if live[key].ResponseBody != body {
+			t.Errorf("%s body = %q, want %q", key, live[key].ResponseBody, body)
+		}
+	}
+
+	// The merge is the new branch point; only the parent's own change remains
+	preview, err = env.services.Project.GetMergePreview(branch.Project.UUID, editor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Entries) != 1 || preview.Entries[0].Path != "/b" || preview.Entries[0].Status != MergeStatusParent {
+		t.Errorf("preview after the merge = %+v, want only GET /b changed by the parent", preview.Entries)
+	}
+}
+
+func TestMergeBranchRequiresEditOnParent(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	parent := env.createProject(team.org, team.members[RoleEditor])
+	branch, err := env.services.Project.CreateBranch(parent.UUID, &contracts.CreateProjectBranchRequest{}, team.members[RoleEditor].ID, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.services.Project.MergeBranch(branch.Project.UUID, &contracts.MergeProjectBranchRequest{}, team.members[RoleViewer].ID, testClient)
+	if !errors.Is(err, ErrInsufficientPermissions) {
+		t.Errorf("MergeBranch() as viewer error = %v, want ErrInsufficientPermissions", err)
+	}
+	if _, err := env.services.Project.GetMergePreview(parent.UUID, team.members[RoleEditor].ID); !errors.Is(err, ErrNotABranch) {
+		t.Errorf("GetMergePreview(parent) error = %v, want ErrNotABranch", err)
+	}
+}
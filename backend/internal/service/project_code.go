@@ -0,0 +1,152 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidProjectCode   = errors.New("invalid project code")
+	ErrProjectCodeTaken     = errors.New("project code already in use")
+	ErrCodeNotSubdomainSafe = errors.New("project code contains uppercase letters and cannot be served on a subdomain")
+)
+
+// projectCodePattern accepts lowercase slugs such as "payments-api": 3 to 63
+// characters of letters, digits and hyphens, starting and ending with a letter or digit.
+var projectCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+// reservedProjectCodes are the first path segments used by the API routes in
+// handler.SetupRoutes. A project with one of these codes would be shadowed by the
+// API, so they can never be chosen or generated.
+var reservedProjectCodes = map[string]struct{}{
+	"ping":          {},
+	"signup":        {},
+	"login":         {},
+	"logout":        {},
+	"token":         {},
+	"password":      {},
+	"email":         {},
+	"auth":          {},
+	".well-known":   {},
+	"user":          {},
+	"organisation":  {},
+	"organisations": {},
+	"project":       {},
+	"projects":      {},
+	"endpoint":      {},
+	"endpoints":     {},
+	"invitations":   {},
+	"api":           {},
+	"admin":         {},
+}
+
+func isReservedProjectCode(code string) bool {
+	_, reserved := reservedProjectCodes[code]
+	return reserved
+}
+
+func validateProjectCode(code string) error {
+	if !projectCodePattern.MatchString(code) {
+		return fmt.Errorf("%w: must be 3-63 lowercase letters, digits or hyphens and start and end with a letter or digit", ErrInvalidProjectCode)
+	}
+	if isReservedProjectCode(code) {
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidProjectCode, code)
+	}
+	return nil
+}
+
+// projectCodeAvailable reports whether code is free to be assigned to the project
+// with the given ID. A project may take back one of its own redirect codes.
+func (s *projectService) projectCodeAvailable(code string, projectID int) (bool, error) {
+	exists, err := s.repo.CodeExists(code)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	redirect, err := s.redirectRepo.GetByCode(code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return redirect.ProjectID == projectID, nil
+}
+
+var (
+	ErrInvalidHostname = errors.New("invalid hostname")
+	ErrHostnameTaken   = errors.New("hostname already in use")
+)
+
+// hostnamePattern accepts fully qualified hostnames with at least two labels.
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// NormalizeHostname lowercases host and strips any port and trailing dot so it
+// can be compared with the hostnames stored on projects.
+func NormalizeHostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// MockHosts describes the host names mocks are served on: subdomains of
+// BaseDomain, named by project code, and the custom hostnames of projects.
+type MockHosts struct {
+	BaseDomain string
+	// CustomHostnames enables projects to claim hostnames of their own. Any
+	// admin can claim any host, so it is only safe where the hosts that reach
+	// the server are controlled, and is off unless enabled.
+	CustomHostnames bool
+	// ReservedHosts are the hosts the API and dashboard are served on. Requests
+	// for them never reach a mock, and no project can register them.
+	ReservedHosts []string
+}
+
+// NewMockHosts normalizes the base domain and reserved hosts for comparison
+// with request hosts.
+func NewMockHosts(baseDomain string, reservedHosts ...string) MockHosts {
+	hosts := MockHosts{BaseDomain: NormalizeHostname(baseDomain)}
+	for _, host := range reservedHosts {
+		if host = NormalizeHostname(host); host != "" {
+			hosts.ReservedHosts = append(hosts.ReservedHosts, host)
+		}
+	}
+	return hosts
+}
+
+// IsReserved reports whether host belongs to the API or dashboard.
+func (h MockHosts) IsReserved(host string) bool {
+	for _, reserved := range h.ReservedHosts {
+		if host == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// validateHostname rejects hostnames that are malformed or would take over
+// requests meant for the API, the dashboard or subdomain routing.
+func (h MockHosts) validateHostname(hostname string) error {
+	if !h.CustomHostnames {
+		return fmt.Errorf("%w: custom hostnames are not enabled on this server", ErrInvalidHostname)
+	}
+	if len(hostname) > 253 || !hostnamePattern.MatchString(hostname) {
+		return fmt.Errorf("%w: %q", ErrInvalidHostname, hostname)
+	}
+	if h.IsReserved(hostname) {
+		return fmt.Errorf("%w: %q is reserved for crudbox itself", ErrInvalidHostname, hostname)
+	}
+	if h.BaseDomain != "" && (hostname == h.BaseDomain || strings.HasSuffix(hostname, "."+h.BaseDomain)) {
+		return fmt.Errorf("%w: %q is under the mock base domain, where projects are served by code", ErrInvalidHostname, hostname)
+	}
+	return nil
+}
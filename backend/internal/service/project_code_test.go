@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+func TestUpdateProjectHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		hostname string
+		want     error
+	}{
+		{name: "disabled", hostname: "shop.example.test", want: ErrInvalidHostname},
+		{name: "disabled for the API host", hostname: "api.example.com", want: ErrInvalidHostname},
+		{name: "API host", enabled: true, hostname: "api.example.com", want: ErrInvalidHostname},
+		{name: "under the mock base domain", enabled: true, hostname: "shop.mock.example.com", want: ErrInvalidHostname},
+		{name: "single label", enabled: true, hostname: "shop", want: ErrInvalidHostname},
+		{name: "enabled", enabled: true, hostname: "Shop.Example.Test.", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hosts := NewMockHosts("mock.example.com", "api.example.com")
+			hosts.CustomHostnames = tt.enabled
+			env := newTestEnvWithHosts(t, hosts)
+			team := env.createTeam()
+			project := env.createProject(team.org, team.members[RoleAdmin])
+
+			updated, err := env.services.Project.UpdateProject(project.UUID, &contracts.UpdateProjectRequest{Hostname: &tt.hostname}, team.members[RoleAdmin].ID, testClient)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("UpdateProject(hostname %q) error = %v, want %v", tt.hostname, err, tt.want)
+			}
+			if err == nil && updated.Hostname != "shop.example.test" {
+				t.Errorf("hostname = %q, want it normalized to shop.example.test", updated.Hostname)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+var (
+	ErrEnvironmentNotFound    = errors.New("environment not found")
+	ErrEnvironmentNameTaken   = errors.New("environment name already in use")
+	ErrInvalidEnvironmentName = errors.New("invalid environment name")
+	ErrInvalidVariableName    = errors.New("invalid variable name")
+)
+
+// environmentNamePattern accepts names such as "dev" or "qa-eu". Names can be
+// selected in mock URLs after a "~", so they follow the rules of version names.
+var environmentNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+var (
+	variableNamePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	variableReferencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// ExpandVariables replaces ${NAME} references in text with the value of the
+// variable NAME. References to unknown variables are left untouched.
+func ExpandVariables(text string, variables map[string]string) string {
+	if len(variables) == 0 {
+		return text
+	}
+	return variableReferencePattern.ReplaceAllStringFunc(text, func(reference string) string {
+		if value, ok := variables[reference[2:len(reference)-1]]; ok {
+			return value
+		}
+		return reference
+	})
+}
+
+func validateVariables(variables map[string]string) error {
+	for name := range variables {
+		if !variableNamePattern.MatchString(name) {
+			return fmt.Errorf("%w %q: use letters, digits and underscores, not starting with a digit", ErrInvalidVariableName, name)
+		}
+	}
+	return nil
+}
+
+func encodeVariables(variables map[string]string) (string, error) {
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	encoded, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func decodeVariables(environment *models.ProjectEnvironment) (map[string]string, error) {
+	variables := map[string]string{}
+	if err := json.Unmarshal([]byte(environment.Variables), &variables); err != nil {
+		return nil, fmt.Errorf("failed to decode variables of environment %s: %w", environment.UUID, err)
+	}
+	return variables, nil
+}
+
+// environmentAuditFields keeps the variables as their JSON encoding, which is
+// sorted by name and can be compared as a whole.
+func environmentAuditFields(environment *models.ProjectEnvironment) auditFields {
+	return auditFields{
+		"name":       environment.Name,
+		"variables":  environment.Variables,
+		"is_default": environment.IsDefault,
+	}
+}
+
+func projectEnvironmentContract(environment *models.ProjectEnvironment) (*contracts.ProjectEnvironment, error) {
+	variables, err := decodeVariables(environment)
+	if err != nil {
+		return nil, err
+	}
+	return &contracts.ProjectEnvironment{
+		UUID:      environment.UUID,
+		Name:      environment.Name,
+		Variables: variables,
+		IsDefault: environment.IsDefault,
+		CreatedAt: environment.CreatedAt,
+		UpdatedAt: environment.UpdatedAt,
+	}, nil
+}
+
+func (s *projectService) getEnvironment(projectID int, name string) (*models.ProjectEnvironment, error) {
+	environment, err := s.envRepo.GetByProjectIDAndName(projectID, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEnvironmentNotFound
+		}
+		return nil, err
+	}
+	return environment, nil
+}
+
+// ensureEnvironmentNameFree fails if another environment of the project uses name.
+func (s *projectService) ensureEnvironmentNameFree(projectID int, name string) error {
+	if !environmentNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: use up to 64 letters, digits, dots, hyphens or underscores", ErrInvalidEnvironmentName)
+	}
+	if _, err := s.envRepo.GetByProjectIDAndName(projectID, name); err == nil {
+		return ErrEnvironmentNameTaken
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+// CreateEnvironment adds an environment to a project. The first environment of
+// a project becomes its default.
+func (s *projectService) CreateEnvironment(projectUUID string, req *contracts.CreateProjectEnvironmentRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectEnvironment, error) {
+	if err := validateVariables(req.Variables); err != nil {
+		return nil, err
+	}
+
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureEnvironmentNameFree(project.ID, req.Name); err != nil {
+		return nil, err
+	}
+
+	variables, err := encodeVariables(req.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	environment := &models.ProjectEnvironment{
+		ProjectID: project.ID,
+		Name:      req.Name,
+		Variables: variables,
+		IsDefault: req.IsDefault,
+		Base: models.Base{
+			CreatedAt: &now,
+			UpdatedAt: &now,
+			CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+			UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+		},
+	}
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if environment.IsDefault {
+			if err := repos.Environment.ClearDefault(project.ID); err != nil {
+				return err
+			}
+		} else if _, err := repos.Environment.GetDefault(project.ID); errors.Is(err, sql.ErrNoRows) {
+			environment.IsDefault = true
+		} else if err != nil {
+			return err
+		}
+
+		if err := repos.Environment.Create(environment); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionCreate,
+			EntityType:     AuditEntityEnvironment,
+			EntityUUID:     environment.UUID,
+			After:          environmentAuditFields(environment),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return projectEnvironmentContract(environment)
+}
+
+func (s *projectService) GetEnvironments(projectUUID string, userID int) ([]*contracts.ProjectEnvironment, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	environments, err := s.envRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*contracts.ProjectEnvironment, 0, len(environments))
+	for _, environment := range environments {
+		env, err := projectEnvironmentContract(environment)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, env)
+	}
+
+	return result, nil
+}
+
+// UpdateEnvironment renames an environment, replaces its variables or makes it
+// the default. An environment stops being the default only when another one
+// takes its place.
+func (s *projectService) UpdateEnvironment(projectUUID, name string, req *contracts.UpdateProjectEnvironmentRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectEnvironment, error) {
+	if err := validateVariables(req.Variables); err != nil {
+		return nil, err
+	}
+
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	environment, err := s.getEnvironment(project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := environmentAuditFields(environment)
+	if req.Name != nil && *req.Name != environment.Name {
+		if err := s.ensureEnvironmentNameFree(project.ID, *req.Name); err != nil {
+			return nil, err
+		}
+		environment.Name = *req.Name
+	}
+	if req.Variables != nil {
+		if environment.Variables, err = encodeVariables(req.Variables); err != nil {
+			return nil, err
+		}
+	}
+	makeDefault := req.IsDefault != nil && *req.IsDefault && !environment.IsDefault
+	if makeDefault {
+		environment.IsDefault = true
+	}
+	after := environmentAuditFields(environment)
+
+	if len(diffAuditFields(before, after)) > 0 {
+		now := time.Now()
+		environment.UpdatedAt = &now
+		environment.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
+
+		err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+			if makeDefault {
+				if err := repos.Environment.ClearDefault(project.ID); err != nil {
+					return err
+				}
+			}
+			if err := repos.Environment.Update(environment); err != nil {
+				return err
+			}
+			return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+				OrganisationID: project.OrganisationID,
+				ProjectUUID:    project.UUID,
+				Action:         AuditActionUpdate,
+				EntityType:     AuditEntityEnvironment,
+				EntityUUID:     environment.UUID,
+				Before:         before,
+				After:          after,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return projectEnvironmentContract(environment)
+}
+
+// DeleteEnvironment removes an environment. Deleting the default environment
+// leaves the project without one until another is made the default.
+func (s *projectService) DeleteEnvironment(projectUUID, name string, userID int, client contracts.ClientInfo) error {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return err
+	}
+
+	environment, err := s.getEnvironment(project.ID, name)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Environment.Delete(environment.ID, user.UUID); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionDelete,
+			EntityType:     AuditEntityEnvironment,
+			EntityUUID:     environment.UUID,
+			Before:         environmentAuditFields(environment),
+		})
+	})
+}
+
+// GetMockVariables returns the variables mock responses of a project are
+// expanded with. An empty environment selects the project's default; projects
+// without a default get no variables.
+func (s *projectService) GetMockVariables(projectID int, environment string) (map[string]string, error) {
+	var env *models.ProjectEnvironment
+	var err error
+	if environment == "" {
+		env, err = s.envRepo.GetDefault(projectID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+	} else {
+		env, err = s.getEnvironment(projectID, environment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeVariables(env)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+func TestExpandVariables(t *testing.T) {
+	variables := map[string]string{"HOST": "api.example.com", "ID": "42", "EMPTY": ""}
+
+	tests := []struct {
+		text      string
+		variables map[string]string
+		want      string
+	}{
+		{text: `{"url":"https://${HOST}/users/${ID}"}`, variables: variables, want: `{"url":"https://api.example.com/users/42"}`},
+		{text: "${ID}${ID}", variables: variables, want: "4242"},
+		{text: "[${EMPTY}]", variables: variables, want: "[]"},
+		{text: "${UNKNOWN} stays", variables: variables, want: "${UNKNOWN} stays"},
+		{text: "$ID and ${ID and ${1D}", variables: variables, want: "$ID and ${ID and ${1D}"},
+		{text: "${HOST}", variables: nil, want: "${HOST}"},
+		// Values are not expanded again
+		{text: "${A}", variables: map[string]string{"A": "${B}", "B": "b"}, want: "${B}"},
+	}
+	for _, tt := range tests {
+		if got := ExpandVariables(tt.text, tt.variables); got != tt.want {
+			t.Errorf("ExpandVariables(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestGetMockVariables(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor]
+	project := env.createProject(team.org, editor)
+
+	variables, err := env.services.Project.GetMockVariables(project.ID, "")
+	if err != nil || variables != nil {
+		t.Fatalf("GetMockVariables() without environments = %v, %v, want no variables", variables, err)
+	}
+
+	dev := map[string]string{"HOST": "dev.example.com"}
+	qa := map[string]string{"HOST": "qa.example.com"}
+	for name, vars := range map[string]map[string]string{"dev": dev, "qa": qa} {
+		if _, err := env.services.Project.CreateEnvironment(project.UUID, &contracts.CreateProjectEnvironmentRequest{Name: name, Variables: vars, IsDefault: name == "dev"}, editor.ID, testClient); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		environment string
+		want        map[string]string
+	}{
+		{environment: "", want: dev},
+		{environment: "dev", want: dev},
+		{environment: "qa", want: qa},
+	}
+	for _, tt := range tests {
+		got, err := env.services.Project.GetMockVariables(project.ID, tt.environment)
+		if err != nil {
+			t.Errorf("GetMockVariables(%q) error = %v", tt.environment, err)
+		} else if !maps.Equal(got, tt.want) {
+			t.Errorf("GetMockVariables(%q) = %v, want %v", tt.environment, got, tt.want)
+		}
+	}
+
+	if _, err := env.services.Project.GetMockVariables(project.ID, "prod"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Errorf("GetMockVariables(prod) error = %v, want ErrEnvironmentNotFound", err)
+	}
+}
+
+func TestCreateEnvironmentValidates(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor]
+	project := env.createProject(team.org, editor)
+
+	tests := []struct {
+		req  contracts.CreateProjectEnvironmentRequest
+		want error
+	}{
+		{req: contracts.CreateProjectEnvironmentRequest{Name: "dev", Variables: map[string]string{"1HOST": "x"}}, want: ErrInvalidVariableName},
+		{req: contracts.CreateProjectEnvironmentRequest{Name: "dev", Variables: map[string]string{"HOST-NAME": "x"}}, want: ErrInvalidVariableName},
+		{req: contracts.CreateProjectEnvironmentRequest{Name: "~dev"}, want: ErrInvalidEnvironmentName},
+		{req: contracts.CreateProjectEnvironmentRequest{Name: "dev"}, want: nil},
+		{req: contracts.CreateProjectEnvironmentRequest{Name: "dev"}, want: ErrEnvironmentNameTaken},
+	}
+	for _, tt := range tests {
+		_, err := env.services.Project.CreateEnvironment(project.UUID, &tt.req, editor.ID, testClient)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("CreateEnvironment(%q, %v) error = %v, want %v", tt.req.Name, tt.req.Variables, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+var ErrInvalidSyncRequest = errors.New("invalid sync request")
+
+// syncState holds the live endpoints of a project and the ones it had after its
+// last sync, which a sync request is planned against. synced is nil for a
+// project that was never synced.
+type syncState struct {
+	sync   *models.ProjectSync
+	synced map[string]*contracts.ProjectBundleEndpoint
+	live   map[string]*models.Endpoint
+}
+
+func loadSync(syncRepo repository.ProjectSyncRepository, endpointRepo repository.EndpointRepository, project *models.Project) (*syncState, error) {
+	endpoints, err := endpointRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &syncState{live: make(map[string]*models.Endpoint, len(endpoints))}
+	for _, endpoint := range endpoints {
+		state.live[endpointKey(endpoint.Method, endpoint.Path)] = endpoint
+	}
+
+	sync, err := syncRepo.GetByProjectID(project.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return state, nil
+		}
+		return nil, err
+	}
+	syncedEndpoints, err := syncRepo.GetEndpoints(sync.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	state.sync = sync
+	state.synced = make(map[string]*contracts.ProjectBundleEndpoint, len(syncedEndpoints))
+	for _, endpoint := range syncedEndpoints {
+		state.synced[endpointKey(endpoint.Method, endpoint.Path)] = bundleEndpoint(endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders)
+	}
+
+	return state, nil
+}
+
+// liveEndpoint returns the live endpoint at key in bundle form, or nil.
+func (state *syncState) liveEndpoint(key string) *contracts.ProjectBundleEndpoint {
+	endpoint, ok := state.live[key]
+	if !ok {
+		return nil
+	}
+	return bundleEndpoint(endpoint.Method, endpoint.Path, endpoint.ResponseBody, endpoint.ResponseStatus, endpoint.ResponseHeaders)
+}
+
+// drifted reports whether the live endpoint at key was changed since the
+// latest sync.
+func (state *syncState) drifted(key string) bool {
+	return state.sync != nil && !sameBundleEndpoint(state.synced[key], state.liveEndpoint(key))
+}
+
+// plan compares the live endpoints with the declared ones, keyed like
+// endpointKey. Endpoints that already match are only counted.
+func (state *syncState) plan(project *models.Project, declared map[string]*contracts.ProjectBundleEndpoint) *contracts.ProjectSyncPlan {
+	plan := &contracts.ProjectSyncPlan{
+		ProjectUUID: project.UUID,
+		Entries:     []contracts.ProjectSyncEntry{},
+	}
+	if state.sync != nil {
+		syncedAt := state.sync.SyncedAt
+		plan.LastSyncedAt = &syncedAt
+	}
+
+	keys := make([]string, 0, len(state.live)+len(declared))
+	for key := range state.live {
+		keys = append(keys, key)
+	}
+	for key := range declared {
+		if _, ok := state.live[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entry := contracts.ProjectSyncEntry{Live: state.liveEndpoint(key), Declared: declared[key]}
+		entry.Method, entry.Path, _ = strings.Cut(key, " ")
+
+		switch {
+		case sameBundleEndpoint(entry.Live, entry.Declared):
+			plan.Unchanged++
+			continue
+		case entry.Live == nil:
+			entry.Action = AuditActionCreate
+			plan.Created++
+		case entry.Declared == nil:
+			entry.Action = AuditActionDelete
+			plan.Deleted++
+		default:
+			entry.Action = AuditActionUpdate
+			plan.Updated++
+		}
+
+		if state.drifted(key) {
+			entry.Drifted = true
+			plan.Drifted++
+		}
+		entry.BodyDiff, entry.HeadersDiff = sideDiffs(entry.Live, entry.Declared)
+		plan.Entries = append(plan.Entries, entry)
+	}
+
+	return plan
+}
+
+// declaredEndpoints keys the endpoints of a sync request like endpointKey. They
+// are validated like endpoints created by hand, before anything is planned.
+func declaredEndpoints(endpoints []contracts.ProjectBundleEndpoint) (map[string]*contracts.ProjectBundleEndpoint, error) {
+	declared := make(map[string]*contracts.ProjectBundleEndpoint, len(endpoints))
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if err := validateEndpoint(endpoint.Method, endpoint.Path, endpoint.ResponseStatus); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSyncRequest, err)
+		}
+		key := endpointKey(endpoint.Method, endpoint.Path)
+		if _, exists := declared[key]; exists {
+			return nil, fmt.Errorf("%w: duplicate endpoint %s", ErrInvalidSyncRequest, key)
+		}
+		declared[key] = endpoint
+	}
+	return declared, nil
+}
+
+// GetSyncStatus reports when a project was last synced and which endpoints were
+// created, updated or deleted outside sync since then.
+func (s *projectService) GetSyncStatus(projectUUID string, userID int) (*contracts.ProjectSyncStatus, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadSync(s.syncRepo, s.endpointRepo, project)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &contracts.ProjectSyncStatus{
+		ProjectUUID: project.UUID,
+		Drift:       []contracts.ProjectDriftEntry{},
+	}
+	if state.sync == nil {
+		return status, nil
+	}
+	syncedAt := state.sync.SyncedAt
+	status.LastSyncedAt = &syncedAt
+	status.LastSyncedBy = state.sync.SyncedBy.String
+
+	keys := make([]string, 0, len(state.synced)+len(state.live))
+	for key := range state.synced {
+		keys = append(keys, key)
+	}
+	for key := range state.live {
+		if _, ok := state.synced[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !state.drifted(key) {
+			continue
+		}
+
+		entry := contracts.ProjectDriftEntry{Synced: state.synced[key], Live: state.liveEndpoint(key)}
+		entry.Method, entry.Path, _ = strings.Cut(key, " ")
+		switch {
+		case entry.Synced == nil:
+			entry.Action = AuditActionCreate
+		case entry.Live == nil:
+			entry.Action = AuditActionDelete
+		default:
+			entry.Action = AuditActionUpdate
+		}
+		entry.BodyDiff, entry.HeadersDiff = sideDiffs(entry.Synced, entry.Live)
+		status.Drift = append(status.Drift, entry)
+	}
+
+	return status, nil
+}
+
+// SyncProject makes a project's endpoints match the declared ones, creating,
+// updating and deleting endpoints as if by hand, and records the result as the
+// state later drift is measured against. A dry run only returns the plan.
+func (s *projectService) SyncProject(projectUUID string, req *contracts.SyncProjectRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectSyncPlan, error) {
+	action := ActionEdit
+	if req.DryRun {
+		action = ActionView
+	}
+	project, err := s.getProjectForUser(projectUUID, userID, action)
+	if err != nil {
+		return nil, err
+	}
+
+	declared, err := declaredEndpoints(req.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		state, err := loadSync(s.syncRepo, s.endpointRepo, project)
+		if err != nil {
+			return nil, err
+		}
+		plan := state.plan(project, declared)
+		plan.DryRun = true
+		return plan, nil
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan *contracts.ProjectSyncPlan
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		state, err := loadSync(repos.Sync, repos.Endpoint, project)
+		if err != nil {
+			return err
+		}
+		plan = state.plan(project, declared)
+
+		now := time.Now()
+		for _, entry := range plan.Entries {
+			key := endpointKey(entry.Method, entry.Path)
+			if _, err := syncEndpoint(repos, project, user, client, state.live[key], entry.Declared, now); err != nil {
+				return err
+			}
+		}
+
+		sync := &models.ProjectSync{
+			ProjectID: project.ID,
+			SyncedAt:  now,
+			SyncedBy:  sql.NullString{String: user.UUID, Valid: true},
+		}
+		if err := repos.Sync.Save(sync); err != nil {
+			return err
+		}
+		if err := repos.Sync.DeleteEndpoints(sync.ID); err != nil {
+			return err
+		}
+		for _, endpoint := range req.Endpoints {
+			if err := repos.Sync.CreateEndpoint(&models.ProjectSyncEndpoint{
+				SyncID:          sync.ID,
+				Method:          endpoint.Method,
+				Path:            endpoint.Path,
+				ResponseBody:    endpoint.ResponseBody,
+				ResponseStatus:  endpoint.ResponseStatus,
+				ResponseHeaders: endpoint.ResponseHeaders,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+)
+
+func declare(method, path, body string) contracts.ProjectBundleEndpoint {
+	return contracts.ProjectBundleEndpoint{Method: method, Path: path, ResponseBody: body, ResponseStatus: 200, ResponseHeaders: "{}"}
+}
+
+func TestSyncProjectPlan(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor]
+	project := env.createProject(team.org, editor)
+	env.createEndpoint(project, editor, "GET", "/users", `{"users":[]}`)
+	env.createEndpoint(project, editor, "GET", "/orders", `{"orders":[]}`)
+	env.createEndpoint(project, editor, "DELETE", "/orders", `{}`)
+
+	req := &contracts.SyncProjectRequest{
+		Endpoints: []contracts.ProjectBundleEndpoint{
+			declare("GET", "/users", `{"users":[]}`),
+			declare("GET", "/orders", `{"orders":[1]}`),
+			declare("POST", "/users", `{"id":1}`),
+		},
+		DryRun: true,
+	}
+
+	plan, err := env.services.Project.SyncProject(project.UUID, req, team.members[RoleViewer].ID, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.DryRun || plan.Created != 1 || plan.Updated != 1 || plan.Deleted != 1 || plan.Unchanged != 1 || plan.Drifted != 0 {
+		t.Errorf("plan = %+v, want 1 created, updated, deleted and unchanged", plan)
+	}
+	wantEntries := []struct{ action, method, path string }{
+		{AuditActionDelete, "DELETE", "/orders"},
+		{AuditActionUpdate, "GET", "/orders"},
+		{AuditActionCreate, "POST", "/users"},
+	}
+	if len(plan.Entries) != len(wantEntries) {
+		t.Fatalf("plan has %d entries, want %d", len(plan.Entries), len(wantEntries))
+	}
+	for i, want := range wantEntries {
+		entry := plan.Entries[i]
+		if entry.Action != want.action || entry.Method != want.method || entry.Path != want.path {
+			t.Errorf("entry %d = %s %s %s, want %s %s %s", i, entry.Action, entry.Method, entry.Path, want.action, want.method, want.path)
+		}
+	}
+
+	endpoints, err := env.services.Endpoint.GetByProjectUUID(project.UUID, editor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 3 {
+		t.Fatalf("dry run changed the endpoints: %d live, want 3", len(endpoints))
+	}
+
+	req.DryRun = false
+	if _, err := env.services.Project.SyncProject(project.UUID, req, team.members[RoleViewer].ID, testClient); !errors.Is(err, ErrInsufficientPermissions) {
+		t.Errorf("SyncProject() as viewer error = %v, want ErrInsufficientPermissions", err)
+	}
+	if _, err := env.services.Project.SyncProject(project.UUID, req, editor.ID, testClient); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints, err = env.services.Endpoint.GetByProjectUUID(project.UUID, editor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := map[string]string{}
+	for _, endpoint := range endpoints {
+		live[endpointKey(endpoint.Method, endpoint.Path)] = endpoint.ResponseBody
+	}
+	want := map[string]string{
+		"GET /users":  `{"users":[]}`,
+		"GET /orders": `{"orders":[1]}`,
+		"POST /users": `{"id":1}`,
+	}
+	if len(live) != len(want) {
+		t.Fatalf("live endpoints after sync = %v, want %v", live, want)
+	}
+	for key, body := range want {
+		if live[key] != body {
+			t.Errorf("%s body = %q, want %q", key, live[key], body)
+		}
+	}
+
+	// Syncing the same declaration again changes nothing
+	req.DryRun = true
+	plan, err = env.services.Project.SyncProject(project.UUID, req, editor.ID, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Entries) != 0 || plan.Unchanged != 3 {
+		t.Errorf("second plan = %+v, want 3 unchanged and no entries", plan)
+	}
+}
+
+func TestSyncProjectReportsDrift(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor]
+	project := env.createProject(team.org, editor)
+
+	req := &contracts.SyncProjectRequest{Endpoints: []contracts.ProjectBundleEndpoint{declare("GET", "/users", `[]`)}}
+	if _, err := env.services.Project.SyncProject(project.UUID, req, editor.ID, testClient); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints, err := env.services.Endpoint.GetByProjectUUID(project.UUID, editor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := `["edited by hand"]`
+	if _, err := env.services.Endpoint.UpdateEndpoint(endpoints[0].UUID, &contracts.UpdateEndpointRequest{ResponseBody: &changed}, editor.ID, testClient); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := env.services.Project.GetSyncStatus(project.UUID, editor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Drift) != 1 || status.Drift[0].Action != AuditActionUpdate || status.Drift[0].Path != "/users" {
+		t.Fatalf("drift = %+v, want an update of GET /users", status.Drift)
+	}
+
+	req.DryRun = true
+	plan, err := env.services.Project.SyncProject(project.UUID, req, editor.ID, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Drifted != 1 || len(plan.Entries) != 1 || !plan.Entries[0].Drifted {
+		t.Errorf("plan = %+v, want the hand edit flagged as drifted", plan)
+	}
+}
+
+func TestSyncProjectRejectsDuplicateEndpoints(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor]
+	project := env.createProject(team.org, editor)
+
+	req := &contracts.SyncProjectRequest{Endpoints: []contracts.ProjectBundleEndpoint{
+		declare("GET", "/users", `[]`),
+		declare("GET", "/users", `[1]`),
+	}}
+	if _, err := env.services.Project.SyncProject(project.UUID, req, editor.ID, testClient); !errors.Is(err, ErrInvalidSyncRequest) {
+		t.Errorf("SyncProject() error = %v, want ErrInvalidSyncRequest", err)
+	}
+}
+
+func TestSyncProjectValidatesEndpoints(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	editor := team.members[RoleEditor]
+	project := env.createProject(team.org, editor)
+
+	lowercase := declare("get", "/users", `[]`)
+	relative := declare("GET", "users", `[]`)
+	noStatus := declare("GET", "/users", `[]`)
+	noStatus.ResponseStatus = 0
+
+	for _, endpoint := range []contracts.ProjectBundleEndpoint{lowercase, relative, noStatus} {
+		for _, dryRun := range []bool{true, false} {
+			req := &contracts.SyncProjectRequest{Endpoints: []contracts.ProjectBundleEndpoint{endpoint}, DryRun: dryRun}
+			_, err := env.services.Project.SyncProject(project.UUID, req, editor.ID, testClient)
+			if !errors.Is(err, ErrInvalidSyncRequest) {
+				t.Errorf("SyncProject(%s %q %d, dry run %t) error = %v, want ErrInvalidSyncRequest", endpoint.Method, endpoint.Path, endpoint.ResponseStatus, dryRun, err)
+			}
+		}
+	}
+
+	if endpoints := env.liveEndpoints(project, editor.ID); len(endpoints) != 0 {
+		t.Errorf("invalid syncs created %d endpoints", len(endpoints))
+	}
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+var (
+	ErrVersionNotFound    = errors.New("version not found")
+	ErrVersionNameTaken   = errors.New("version name already in use")
+	ErrInvalidVersionName = errors.New("invalid version name")
+)
+
+// versionNamePattern accepts names such as "v1.2" or "before-refactor". Names
+// end up in mock URLs after the "@", so slashes and spaces are not allowed.
+var versionNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+func versionAuditFields(version *models.ProjectVersion) auditFields {
+	return auditFields{
+		"name":           version.Name,
+		"description":    version.Description.String,
+		"endpoint_count": version.EndpointCount,
+	}
+}
+
+func (s *projectService) getVersion(projectID int, name string) (*models.ProjectVersion, error) {
+	version, err := s.versionRepo.GetByProjectIDAndName(projectID, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+	return version, nil
+}
+
+func projectVersionContract(version *models.ProjectVersion) *contracts.ProjectVersion {
+	return &contracts.ProjectVersion{
+		UUID:          version.UUID,
+		Name:          version.Name,
+		Description:   version.Description.String,
+		EndpointCount: version.EndpointCount,
+		CreatedAt:     version.CreatedAt,
+		CreatedBy:     version.CreatedBy.String,
+	}
+}
+
+// CreateVersion freezes the current endpoints of a project under a name. Later
+// changes to the endpoints do not affect the version.
+func (s *projectService) CreateVersion(projectUUID string, req *contracts.CreateProjectVersionRequest, userID int, client contracts.ClientInfo) (*contracts.ProjectVersion, error) {
+	if !versionNamePattern.MatchString(req.Name) {
+		return nil, fmt.Errorf("%w: use up to 64 letters, digits, dots, hyphens or underscores", ErrInvalidVersionName)
+	}
+
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.versionRepo.GetByProjectIDAndName(project.ID, req.Name); err == nil {
+		return nil, ErrVersionNameTaken
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	version := &models.ProjectVersion{
+		ProjectID:   project.ID,
+		Name:        req.Name,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Base: models.Base{
+			CreatedAt: &now,
+			UpdatedAt: &now,
+			CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+			UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+		},
+	}
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		endpoints, err := repos.Endpoint.GetByProjectID(project.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Version.Create(version); err != nil {
+			return err
+		}
+		for _, endpoint := range endpoints {
+			if err := repos.Version.CreateEndpoint(&models.ProjectVersionEndpoint{
+				VersionID:       version.ID,
+				Method:          endpoint.Method,
+				Path:            endpoint.Path,
+				ResponseBody:    endpoint.ResponseBody,
+				ResponseStatus:  endpoint.ResponseStatus,
+				ResponseHeaders: endpoint.ResponseHeaders,
+			}); err != nil {
+				return err
+			}
+		}
+		version.EndpointCount = len(endpoints)
+
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionCreate,
+			EntityType:     AuditEntityVersion,
+			EntityUUID:     version.UUID,
+			After:          versionAuditFields(version),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return projectVersionContract(version), nil
+}
+
+func (s *projectService) GetVersions(projectUUID string, userID int) ([]*contracts.ProjectVersion, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.versionRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*contracts.ProjectVersion, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, projectVersionContract(version))
+	}
+
+	return result, nil
+}
+
+// GetVersion returns a version together with its endpoints.
+func (s *projectService) GetVersion(projectUUID, name string, userID int) (*contracts.ProjectVersion, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.getVersion(project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := s.versionRepo.GetEndpoints(version.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := projectVersionContract(version)
+	result.Endpoints = make([]contracts.ProjectBundleEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result.Endpoints = append(result.Endpoints, contracts.ProjectBundleEndpoint{
+			Method:          endpoint.Method,
+			Path:            endpoint.Path,
+			ResponseBody:    endpoint.ResponseBody,
+			ResponseStatus:  endpoint.ResponseStatus,
+			ResponseHeaders: endpoint.ResponseHeaders,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *projectService) DeleteVersion(projectUUID, name string, userID int, client contracts.ClientInfo) error {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return err
+	}
+
+	version, err := s.getVersion(project.ID, name)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Version.Delete(version.ID, user.UUID); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionDelete,
+			EntityType:     AuditEntityVersion,
+			EntityUUID:     version.UUID,
+			Before:         versionAuditFields(version),
+		})
+	})
+}
+
+// RestoreVersion makes the head endpoints of a project match a version again.
+// Endpoints are matched by method and path: missing ones are created, differing
+// ones updated and those not in the version deleted to the trash. Every change
+// gets a revision and an audit entry as if it was made by hand.
+func (s *projectService) RestoreVersion(projectUUID, name string, userID int, client contracts.ClientInfo) (*contracts.ProjectVersionRestoreResult, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.getVersion(project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &contracts.ProjectVersionRestoreResult{}
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		versionEndpoints, err := repos.Version.GetEndpoints(version.ID)
+		if err != nil {
+			return err
+		}
+		headEndpoints, err := repos.Endpoint.GetByProjectID(project.ID)
+		if err != nil {
+			return err
+		}
+
+		head := make(map[string]*models.Endpoint, len(headEndpoints))
+		for _, endpoint := range headEndpoints {
+			head[endpointKey(endpoint.Method, endpoint.Path)] = endpoint
+		}
+
+		now := time.Now()
+		count := func(action string) {
+			switch action {
+			case AuditActionCreate:
+				result.Created++
+			case AuditActionUpdate:
+				result.Updated++
+			case AuditActionDelete:
+				result.Deleted++
+			}
+		}
+
+		for _, frozen := range versionEndpoints {
+			key := endpointKey(frozen.Method, frozen.Path)
+			action, err := syncEndpoint(repos, project, user, client, head[key], &contracts.ProjectBundleEndpoint{
+				Method:          frozen.Method,
+				Path:            frozen.Path,
+				ResponseBody:    frozen.ResponseBody,
+				ResponseStatus:  frozen.ResponseStatus,
+				ResponseHeaders: frozen.ResponseHeaders,
+			}, now)
+			if err != nil {
+				return err
+			}
+			count(action)
+			delete(head, key)
+		}
+
+		// Whatever is left was added after the version was taken
+		for _, endpoint := range head {
+			action, err := syncEndpoint(repos, project, user, client, endpoint, nil, now)
+			if err != nil {
+				return err
+			}
+			count(action)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func endpointKey(method, path string) string {
+	return method + " " + path
+}
+
+// syncEndpoint makes the endpoint of project at one method and path match
+// target. current is the live endpoint there, if any; a nil target deletes it to
+// the trash. The change is saved with a revision and an audit entry as if it was
+// made by hand, and its audit action is returned, or "" if nothing changed.
+func syncEndpoint(repos *repository.Repositories, project *models.Project, user *models.User, client contracts.ClientInfo, current *models.Endpoint, target *contracts.ProjectBundleEndpoint, now time.Time) (string, error) {
+	event := auditEvent{
+		OrganisationID: project.OrganisationID,
+		ProjectUUID:    project.UUID,
+		EntityType:     AuditEntityEndpoint,
+	}
+	audit := auditor{repo: repos.AuditLog}
+
+	switch {
+	case current == nil && target == nil:
+		return "", nil
+
+	case current == nil:
+		endpoint := &models.Endpoint{
+			Method:          target.Method,
+			Path:            target.Path,
+			ResponseBody:    target.ResponseBody,
+			ResponseStatus:  target.ResponseStatus,
+			ResponseHeaders: target.ResponseHeaders,
+			ProjectID:       project.ID,
+			Base: models.Base{
+				CreatedAt: &now,
+				UpdatedAt: &now,
+				CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+				UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+			},
+		}
+		if err := repos.Endpoint.Create(endpoint); err != nil {
+			return "", err
+		}
+		if err := recordRevision(repos.Revision, endpoint, user, 0); err != nil {
+			return "", err
+		}
+		event.Action, event.EntityUUID, event.After = AuditActionCreate, endpoint.UUID, endpointAuditFields(endpoint)
+		return AuditActionCreate, audit.record(user, client, event)
+
+	case target == nil:
+		current.DeletedAt = &now
+		current.DeletedBy = sql.NullString{String: user.UUID, Valid: true}
+		if err := repos.Endpoint.Update(current); err != nil {
+			return "", err
+		}
+		event.Action, event.EntityUUID, event.Before = AuditActionDelete, current.UUID, endpointAuditFields(current)
+		return AuditActionDelete, audit.record(user, client, event)
+	}
+
+	before := endpointAuditFields(current)
+	current.ResponseBody = target.ResponseBody
+	current.ResponseStatus = target.ResponseStatus
+	current.ResponseHeaders = target.ResponseHeaders
+	after := endpointAuditFields(current)
+	if len(diffAuditFields(before, after)) == 0 {
+		return "", nil
+	}
+
+	current.UpdatedAt = &now
+	current.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
+	if err := repos.Endpoint.Update(current); err != nil {
+		return "", err
+	}
+	if err := recordRevision(repos.Revision, current, user, 0); err != nil {
+		return "", err
+	}
+	event.Action, event.EntityUUID, event.Before, event.After = AuditActionUpdate, current.UUID, before, after
+	return AuditActionUpdate, audit.record(user, client, event)
+}
+
+// GetVersionEndpoint returns the endpoint of a version that mock requests for
+// method and path are answered with.
+func (s *projectService) GetVersionEndpoint(projectID int, name, path, method string) (*contracts.Endpoint, error) {
+	version, err := s.getVersion(projectID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.versionRepo.GetEndpoint(version.ID, path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Endpoint{
+		Method:          endpoint.Method,
+		Path:            endpoint.Path,
+		ResponseBody:    endpoint.ResponseBody,
+		ResponseStatus:  endpoint.ResponseStatus,
+		ResponseHeaders: endpoint.ResponseHeaders,
+	}, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+const (
+	defaultRequestLogLimit = 100
+	maxRequestLogLimit     = 500
+
+	// requestLogSize is how many requests are kept per project. The log is for
+	// watching clients talk to mocks, not an archive.
+	requestLogSize = 1000
+
+	// maxRequestLogText caps the length of client-supplied values in the log.
+	maxRequestLogText = 2048
+)
+
+// requestLogTrimChance is the share of recorded requests that also trim their
+// project's log back to requestLogSize, so a log grows by about a hundred
+// entries past that before it is cut.
+const requestLogTrimChance = 0.01
+
+// requestLogQueueSize is how many requests can wait to be written. Requests
+// arriving while the queue is full are not logged rather than slowing mocks.
+const requestLogQueueSize = 1024
+
+var ErrInvalidRequestLogCursor = errors.New("invalid request log cursor")
+
+// requestRecorder writes request log entries in the background, so recording
+// never delays a mock response. It is best effort: entries are dropped when the
+// database falls behind, and on AWS Lambda, which freezes the process between
+// invocations, they are written during later requests or lost with the instance.
+type requestRecorder struct {
+	repo    repository.RequestLogRepository
+	queue   chan *models.RequestLog
+	pending sync.WaitGroup
+	dropped atomic.Int64
+}
+
+func newRequestRecorder(repo repository.RequestLogRepository) *requestRecorder {
+	r := &requestRecorder{repo: repo, queue: make(chan *models.RequestLog, requestLogQueueSize)}
+	go r.run()
+	return r
+}
+
+func (r *requestRecorder) record(entry *models.RequestLog) {
+	r.pending.Add(1)
+	select {
+	case r.queue <- entry:
+	default:
+		r.pending.Done()
+		r.dropped.Add(1)
+	}
+}
+
+func (r *requestRecorder) run() {
+	for entry := range r.queue {
+		if dropped := r.dropped.Swap(0); dropped > 0 {
+			log.Printf("request log queue was full; %d requests were not recorded", dropped)
+		}
+		if err := r.repo.Create(entry); err != nil {
+			log.Printf("failed to record request to project %d: %v", entry.ProjectID, err)
+		} else if rand.Float64() < requestLogTrimChance {
+			if err := r.repo.Trim(entry.ProjectID, requestLogSize); err != nil {
+				log.Printf("failed to trim request log of project %d: %v", entry.ProjectID, err)
+			}
+		}
+		r.pending.Done()
+	}
+}
+
+// wait blocks until every queued entry is written.
+func (r *requestRecorder) wait() {
+	r.pending.Wait()
+}
+
+// RecordRequest queues a request served by a project's mocks for its request
+// log and returns at once. Without a request log repository it does nothing.
+func (s *projectService) RecordRequest(projectID int, req *contracts.MockRequest, client contracts.ClientInfo) {
+	if s.requests == nil {
+		return
+	}
+	s.requests.record(&models.RequestLog{
+		ProjectID:      projectID,
+		Method:         requestLogText(req.Method),
+		Path:           requestLogText(req.Path),
+		Query:          requestLogText(req.Query),
+		Version:        nullRequestLogText(req.Version),
+		Environment:    nullRequestLogText(req.Environment),
+		Matched:        req.Matched,
+		EndpointUUID:   sql.NullString{String: req.EndpointUUID, Valid: req.EndpointUUID != ""},
+		ResponseStatus: req.ResponseStatus,
+		IPAddress:      nullRequestLogText(client.IPAddress),
+		UserAgent:      nullRequestLogText(client.UserAgent),
+		CreatedAt:      time.Now(),
+	})
+}
+
+// requestLogText makes a client-supplied value fit for the log: cut to
+// maxRequestLogText bytes and valid UTF-8, which Postgres insists on.
+func requestLogText(value string) string {
+	if len(value) > maxRequestLogText {
+		value = value[:maxRequestLogText]
+	}
+	return strings.ToValidUTF8(value, "\uFFFD")
+}
+
+func nullRequestLogText(value string) sql.NullString {
+	return sql.NullString{String: requestLogText(value), Valid: value != ""}
+}
+
+// GetRequestLog lists requests served by a project's mocks, oldest first, from
+// after the cursor so callers can poll for new ones. Mock clients are often
+// outside the organisation, so only members with manage rights see their IPs.
+func (s *projectService) GetRequestLog(projectUUID string, query *contracts.RequestLogQuery, userID int) (*contracts.RequestLogPage, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+	showIPAddresses := s.auth.authorize(userID, project.OrganisationID, ActionManage) == nil
+
+	filter := repository.RequestLogFilter{ProjectID: project.ID, Limit: defaultRequestLogLimit}
+	if query.Limit > 0 {
+		filter.Limit = min(query.Limit, maxRequestLogLimit)
+	}
+	cursor := "0"
+	if query.After != "" {
+		afterID, err := strconv.Atoi(query.After)
+		if err != nil || afterID < 0 {
+			return nil, ErrInvalidRequestLogCursor
+		}
+		filter.AfterID = &afterID
+		cursor = query.After
+	}
+
+	entries, err := s.requestLogRepo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &contracts.RequestLogPage{Entries: make([]*contracts.RequestLogEntry, 0, len(entries)), Cursor: cursor}
+	for _, entry := range entries {
+		logEntry := &contracts.RequestLogEntry{
+			ID:             entry.ID,
+			Method:         entry.Method,
+			Path:           entry.Path,
+			Query:          entry.Query,
+			Version:        entry.Version.String,
+			Environment:    entry.Environment.String,
+			Matched:        entry.Matched,
+			EndpointUUID:   entry.EndpointUUID.String,
+			ResponseStatus: entry.ResponseStatus,
+			UserAgent:      entry.UserAgent.String,
+			CreatedAt:      entry.CreatedAt,
+		}
+		if showIPAddresses {
+			logEntry.IPAddress = entry.IPAddress.String
+		}
+		page.Entries = append(page.Entries, logEntry)
+	}
+
+	if len(entries) > 0 {
+		page.Cursor = strconv.Itoa(entries[len(entries)-1].ID)
+	}
+
+	return page, nil
+}
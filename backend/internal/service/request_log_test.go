@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+	"github.com/crudboxin/crudbox/internal/repository/memory"
+)
+
+// recordRequest records a request and waits until it is written.
+func (env *testEnv) recordRequest(project *contracts.Project, req *contracts.MockRequest) {
+	env.services.Project.RecordRequest(project.ID, req, testClient)
+	env.services.Project.(*projectService).requests.wait()
+}
+
+func TestRequestLogFollow(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	project := env.createProject(team.org, team.members[RoleEditor])
+	viewer := team.members[RoleViewer].ID
+
+	record := func(path string) {
+		env.recordRequest(project, &contracts.MockRequest{Method: "GET", Path: path, ResponseStatus: 200})
+	}
+	paths := func(page *contracts.RequestLogPage) []string {
+		var paths []string
+		for _, entry := range page.Entries {
+			paths = append(paths, entry.Path)
+		}
+		return paths
+	}
+
+	page, err := env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{}, viewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 0 || page.Cursor != "0" {
+		t.Fatalf("empty log = %+v, want no entries and cursor 0", page)
+	}
+	cursor := page.Cursor
+
+	record("/a")
+	record("/b")
+	record("/c")
+
+	page, err = env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{Limit: 2}, viewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := paths(page), []string{"/b", "/c"}; !slices.Equal(got, want) {
+		t.Errorf("newest requests = %v, want %v", got, want)
+	}
+
+	// Following from the empty log's cursor returns every request since
+	page, err = env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{After: cursor}, viewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := paths(page), []string{"/a", "/b", "/c"}; !slices.Equal(got, want) {
+		t.Errorf("requests after %s = %v, want %v", cursor, got, want)
+	}
+	cursor = page.Cursor
+
+	page, err = env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{After: cursor}, viewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 0 || page.Cursor != cursor {
+		t.Errorf("caught-up log = %+v, want no entries and cursor %s", page, cursor)
+	}
+
+	record("/d")
+	page, err = env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{After: cursor}, viewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := paths(page), []string{"/d"}; !slices.Equal(got, want) {
+		t.Errorf("requests after %s = %v, want %v", cursor, got, want)
+	}
+
+	for _, after := range []string{"-1", "abc"} {
+		_, err := env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{After: after}, viewer)
+		if !errors.Is(err, ErrInvalidRequestLogCursor) {
+			t.Errorf("GetRequestLog(after %q) error = %v, want ErrInvalidRequestLogCursor", after, err)
+		}
+	}
+}
+
+func TestRequestLogAccess(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	project := env.createProject(team.org, team.members[RoleEditor])
+	env.recordRequest(project, &contracts.MockRequest{Method: "GET", Path: "/users", Version: "v1", Matched: true, ResponseStatus: 200})
+
+	for _, role := range testRoles {
+		page, err := env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{}, team.members[role].ID)
+		if err != nil {
+			t.Errorf("GetRequestLog() as %s error = %v", role, err)
+			continue
+		}
+		wantIP := ""
+		if role == RoleAdmin || role == RoleOwner {
+			wantIP = testClient.IPAddress
+		}
+		if len(page.Entries) != 1 || !page.Entries[0].Matched || page.Entries[0].IPAddress != wantIP {
+			t.Errorf("GetRequestLog() as %s = %+v, want one matched entry with IP address %q", role, page.Entries, wantIP)
+		}
+	}
+
+	// Projects of other organisations are not disclosed
+	outsider := env.createUser("outsider@example.com")
+	if _, err := env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{}, outsider.ID); err == nil || err.Error() != "project not found" {
+		t.Errorf("GetRequestLog() as outsider error = %v, want project not found", err)
+	}
+}
+
+func TestRequestLogCleansClientValues(t *testing.T) {
+	env := newTestEnv(t)
+	team := env.createTeam()
+	project := env.createProject(team.org, team.members[RoleEditor])
+
+	env.recordRequest(project, &contracts.MockRequest{
+		Method:         "GET",
+		Path:           "/\xff",
+		Query:          strings.Repeat("q", 3*maxRequestLogText),
+		ResponseStatus: 200,
+	})
+
+	page, err := env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{}, team.members[RoleViewer].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := page.Entries[0]
+	if entry.Path != "/�" {
+		t.Errorf("path = %q, want the invalid byte replaced", entry.Path)
+	}
+	if len(entry.Query) != maxRequestLogText {
+		t.Errorf("query is %d bytes, want it cut to %d", len(entry.Query), maxRequestLogText)
+	}
+}
+
+// blockingRequestLog holds every write until release is closed.
+type blockingRequestLog struct {
+	repository.RequestLogRepository
+	release chan struct{}
+}
+
+func (r *blockingRequestLog) Create(entry *models.RequestLog) error {
+	<-r.release
+	return r.RequestLogRepository.Create(entry)
+}
+
+func TestRecordRequestDoesNotWaitForTheDatabase(t *testing.T) {
+	repos := memory.NewRepositories()
+	blocking := &blockingRequestLog{RequestLogRepository: repos.RequestLog, release: make(chan struct{})}
+	repos.RequestLog = blocking
+	env := newTestEnvWithRepos(t, repos, NewMockHosts(""))
+	team := env.createTeam()
+	project := env.createProject(team.org, team.members[RoleEditor])
+
+	// Twice the queue: the writer holds one, the queue fills, the rest are dropped
+	for range 2 * requestLogQueueSize {
+		env.services.Project.RecordRequest(project.ID, &contracts.MockRequest{Method: "GET", Path: "/users", ResponseStatus: 200}, testClient)
+	}
+	close(blocking.release)
+	env.services.Project.(*projectService).requests.wait()
+
+	page, err := env.services.Project.GetRequestLog(project.UUID, &contracts.RequestLogQuery{Limit: 1}, team.members[RoleViewer].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := page.Entries[0].ID; got < requestLogQueueSize || got > requestLogQueueSize+1 {
+		t.Errorf("%d requests recorded, want the %d queued and the one being written", got, requestLogQueueSize)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+)
+
+// sessionIssuer creates login sessions and signs their access tokens. It is
+// shared by every way of logging in so they all issue the same tokens.
+type sessionIssuer struct {
+	sessionRepo repository.UserSessionRepository
+	authConfig  AuthConfig
+}
+
+// startSession creates a login session for user and issues its first token pair.
+func (s sessionIssuer) startSession(user *models.User, client contracts.ClientInfo) (*contracts.AuthTokens, error) {
+	refreshToken, refreshTokenHash, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: refreshTokenHash,
+		ExpiresAt:        now.Add(s.authConfig.RefreshTokenTTL),
+		LastUsedAt:       &now,
+		IPAddress:        sql.NullString{String: client.IPAddress, Valid: client.IPAddress != ""},
+		UserAgent:        sql.NullString{String: client.UserAgent, Valid: client.UserAgent != ""},
+		Base: models.Base{
+			CreatedAt: &now,
+			UpdatedAt: &now,
+			CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+			UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+		},
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session, refreshToken)
+}
+
+// issueTokens signs an access token bound to session and pairs it with the
+// session's current refresh token.
+func (s sessionIssuer) issueTokens(user *models.User, session *models.UserSession, refreshToken string) (*contracts.AuthTokens, error) {
+	now := time.Now()
+	accessToken, err := s.authConfig.Keys.Sign(jwt.MapClaims{
+		"user_id":   user.ID,
+		"user_uuid": user.UUID,
+		"sid":       session.UUID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.authConfig.AccessTokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.AuthTokens{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.authConfig.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. Refresh tokens
+// rotate on every use; presenting one that was already exchanged means it has
+// leaked, so the whole session is revoked.
+func (s *userService) RefreshToken(req *contracts.RefreshTokenRequest, client contracts.ClientInfo) (*contracts.AuthTokens, error) {
+	tokenHash := hashToken(req.RefreshToken)
+
+	session, err := s.sessionRepo.GetByRefreshTokenHash(tokenHash)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if reused, err := s.sessionRepo.GetByPreviousRefreshTokenHash(tokenHash); err == nil {
+			if err := s.sessionRepo.Revoke(reused.ID, "system"); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.GetByID(session.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	refreshToken, refreshTokenHash, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Another refresh with the same token rotated the session first. Only one
+	// of them can be the legitimate client, so treat it like reuse.
+	if err := s.sessionRepo.Rotate(session.ID, tokenHash, refreshTokenHash, time.Now().Add(s.authConfig.RefreshTokenTTL)); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if err := s.sessionRepo.Revoke(session.ID, "system"); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.sessions.issueTokens(user, session, refreshToken)
+}
+
+func (s *userService) Logout(sessionUUID string, userID int) error {
+	session, err := s.sessionRepo.GetByUUID(sessionUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("session not found")
+		}
+		return err
+	}
+
+	if session.UserID != userID {
+		return errors.New("session not found")
+	}
+
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionRepo.Revoke(session.ID, user.UUID)
+}
+
+func (s *userService) LogoutAll(userID int) error {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeByUserID(userID, user.UUID)
+}
+
+// ValidateSession reports ErrSessionRevoked unless sessionUUID is a live session
+// of userID. The auth middleware calls it for every access token.
+func (s *userService) ValidateSession(sessionUUID string, userID int) error {
+	session, err := s.sessionRepo.GetByUUID(sessionUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	if session.UserID != userID || !session.ExpiresAt.After(time.Now()) {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+	"github.com/crudboxin/crudbox/internal/repository/memory"
+)
+
+func (env *testEnv) login(email string) *contracts.AuthTokens {
+	env.t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		env.t.Fatal(err)
+	}
+	if err := env.repos.User.Create(&models.User{Email: email, Password: string(hash)}); err != nil {
+		env.t.Fatal(err)
+	}
+	tokens, err := env.services.User.Login(&contracts.LoginRequest{Email: email, Password: "password"}, testClient)
+	if err != nil {
+		env.t.Fatal(err)
+	}
+	return tokens
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	env := newTestEnv(t)
+	tokens := env.login("user@example.com")
+
+	rotated, err := env.services.User.RefreshToken(&contracts.RefreshTokenRequest{RefreshToken: tokens.RefreshToken}, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.services.User.RefreshToken(&contracts.RefreshTokenRequest{RefreshToken: tokens.RefreshToken}, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("RefreshToken(reused) error = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := env.services.User.RefreshToken(&contracts.RefreshTokenRequest{RefreshToken: rotated.RefreshToken}, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken(rotated) after reuse error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+// racingSessions lets another refresh rotate the session between the lookup
+// of the refresh token and its rotation, as a concurrent request could.
+type racingSessions struct {
+	repository.UserSessionRepository
+	race func()
+}
+
+func (r *racingSessions) GetByRefreshTokenHash(tokenHash string) (*models.UserSession, error) {
+	session, err := r.UserSessionRepository.GetByRefreshTokenHash(tokenHash)
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+	return session, err
+}
+
+func TestConcurrentRefreshCountsAsReuse(t *testing.T) {
+	repos := memory.NewRepositories()
+	sessions := &racingSessions{UserSessionRepository: repos.Session}
+	repos.Session = sessions
+	env := newTestEnvWithRepos(t, repos, NewMockHosts(""))
+	tokens := env.login("user@example.com")
+	refresh := &contracts.RefreshTokenRequest{RefreshToken: tokens.RefreshToken}
+
+	var winner *contracts.AuthTokens
+	sessions.race = func() {
+		var err error
+		if winner, err = env.services.User.RefreshToken(refresh, testClient); err != nil {
+			t.Fatalf("RefreshToken(first) error = %v", err)
+		}
+	}
+
+	if _, err := env.services.User.RefreshToken(refresh, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("RefreshToken(second) error = %v, want ErrInvalidRefreshToken", err)
+	}
+	// Only one of the two can be the legitimate client, so the session is gone
+	if _, err := env.services.User.RefreshToken(&contracts.RefreshTokenRequest{RefreshToken: winner.RefreshToken}, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken(first's new token) error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/jwtkeys"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+	"github.com/crudboxin/crudbox/internal/repository/memory"
+)
+
+var testClient = contracts.ClientInfo{IPAddress: "192.0.2.1", UserAgent: "service-test"}
+
+// testEnv is a set of services on top of empty in-memory repositories.
+type testEnv struct {
+	t        *testing.T
+	repos    *repository.Repositories
+	services *Services
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	return newTestEnvWithHosts(t, NewMockHosts("mock.example.com", "api.example.com"))
+}
+
+func newTestEnvWithHosts(t *testing.T, hosts MockHosts) *testEnv {
+	t.Helper()
+	return newTestEnvWithRepos(t, memory.NewRepositories(), hosts)
+}
+
+// newTestEnvWithRepos builds the services on repos, which tests can wrap to
+// interfere with individual repository calls.
+func newTestEnvWithRepos(t *testing.T, repos *repository.Repositories, hosts MockHosts) *testEnv {
+	t.Helper()
+	services := NewServices(repos, AuthConfig{
+		Keys:            jwtkeys.NewHMACKeySet([]byte("test-secret")),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+		AppBaseURL:      "http://localhost:3000",
+	}, hosts, nil, nil)
+	return &testEnv{t: t, repos: repos, services: services}
+}
+
+func (env *testEnv) createUser(email string) *models.User {
+	env.t.Helper()
+	user := &models.User{Email: email, Password: "unused"}
+	if err := env.repos.User.Create(user); err != nil {
+		env.t.Fatalf("create user %s: %v", email, err)
+	}
+	return user
+}
+
+func (env *testEnv) createOrganisation(owner *models.User) *contracts.Organisation {
+	env.t.Helper()
+	org, err := env.services.Organisation.CreateOrganisation(&contracts.CreateOrganisationRequest{Name: "Acme"}, owner.ID, testClient)
+	if err != nil {
+		env.t.Fatalf("create organisation: %v", err)
+	}
+	return org
+}
+
+// addMember invites a new user with role and accepts the invitation as them.
+func (env *testEnv) addMember(org *contracts.Organisation, owner *models.User, email, role string) *models.User {
+	env.t.Helper()
+	member := env.createUser(email)
+	invitation, err := env.services.Organisation.CreateInvitation(org.UUID, &contracts.CreateInvitationRequest{Email: email, Role: role}, owner.ID)
+	if err != nil {
+		env.t.Fatalf("invite %s: %v", email, err)
+	}
+	if _, err := env.services.Organisation.AcceptInvitation(&contracts.AcceptInvitationRequest{Token: invitation.Token}, member.ID, testClient); err != nil {
+		env.t.Fatalf("accept invitation for %s: %v", email, err)
+	}
+	return member
+}
+
+func (env *testEnv) createProject(org *contracts.Organisation, user *models.User) *contracts.Project {
+	env.t.Helper()
+	project, err := env.services.Project.CreateProject(&contracts.CreateProjectRequest{Name: "Shop", OrganisationUUID: org.UUID}, user.ID, testClient)
+	if err != nil {
+		env.t.Fatalf("create project: %v", err)
+	}
+	return project
+}
+
+func (env *testEnv) createEndpoint(project *contracts.Project, user *models.User, method, path, body string) *contracts.Endpoint {
+	env.t.Helper()
+	endpoint, err := env.services.Endpoint.CreateEndpoint(&contracts.CreateEndpointRequest{
+		Method:          method,
+		Path:            path,
+		ResponseBody:    body,
+		ResponseStatus:  200,
+		ResponseHeaders: "{}",
+	}, project.UUID, user.ID, testClient)
+	if err != nil {
+		env.t.Fatalf("create endpoint %s %s: %v", method, path, err)
+	}
+	return endpoint
+}
+
+// team is an organisation with one member of every role.
+type team struct {
+	org     *contracts.Organisation
+	members map[string]*models.User
+}
+
+func (env *testEnv) createTeam() *team {
+	env.t.Helper()
+	owner := env.createUser("owner@example.com")
+	org := env.createOrganisation(owner)
+	members := map[string]*models.User{RoleOwner: owner}
+	for _, role := range []string{RoleAdmin, RoleEditor, RoleViewer} {
+		members[role] = env.addMember(org, owner, role+"@example.com", role)
+	}
+	return &team{org: org, members: members}
+}
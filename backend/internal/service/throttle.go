@@ -0,0 +1,118 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+// throttlePolicy allows a number of failures within a window and then locks the
+// key out for a period that doubles with every further failure.
+type throttlePolicy struct {
+	prefix       string
+	freeAttempts int
+	window       time.Duration
+	baseLockout  time.Duration
+	maxLockout   time.Duration
+}
+
+var (
+	// loginAccountPolicy protects a single account from password guessing.
+	loginAccountPolicy = throttlePolicy{prefix: "login:account:", freeAttempts: 5, window: 15 * time.Minute, baseLockout: 30 * time.Second, maxLockout: time.Hour}
+	// loginIPPolicy slows down one client trying many accounts.
+	loginIPPolicy = throttlePolicy{prefix: "login:ip:", freeAttempts: 20, window: 15 * time.Minute, baseLockout: 30 * time.Second, maxLockout: time.Hour}
+	// signupIPPolicy counts every signup attempt, successful or not.
+	signupIPPolicy = throttlePolicy{prefix: "signup:ip:", freeAttempts: 10, window: time.Hour, baseLockout: time.Minute, maxLockout: 24 * time.Hour}
+)
+
+// staleThrottleCleanupChance is the share of recorded failures that also delete
+// counters untouched for a day. No lockout lasts longer, so they are unused.
+const staleThrottleCleanupChance = 0.01
+
+// lockout returns how long a key is locked out after excess failures beyond the
+// free attempts. Doubling stops at maxLockout, so the duration cannot overflow.
+func (p throttlePolicy) lockout(excess int) time.Duration {
+	lockout := p.baseLockout
+	for i := 1; i < excess && lockout < p.maxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, p.maxLockout)
+}
+
+// ThrottledError is returned while a login or signup is locked out.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return "too many attempts, please try again later"
+}
+
+// throttle tracks failures per key in the database so that limits hold across
+// instances and Lambda invocations.
+type throttle struct {
+	repo repository.AuthThrottleRepository
+}
+
+func throttleKey(policy throttlePolicy, subject string) string {
+	return policy.prefix + strings.ToLower(strings.TrimSpace(subject))
+}
+
+// check returns a ThrottledError if any of the keys is locked out.
+func (t throttle) check(keys map[string]throttlePolicy) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for key := range keys {
+		current, err := t.repo.Get(key)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return err
+		}
+		if current.LockedUntil != nil && current.LockedUntil.After(now) {
+			retryAfter = max(retryAfter, current.LockedUntil.Sub(now))
+		}
+	}
+
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// fail records a failure for every key and locks out those over their limit.
+func (t throttle) fail(keys map[string]throttlePolicy) error {
+	now := time.Now()
+	for key, policy := range keys {
+		current, err := t.repo.RecordFailure(key, now, now.Add(-policy.window))
+		if err != nil {
+			return err
+		}
+
+		excess := current.Failures - policy.freeAttempts
+		if excess <= 0 {
+			continue
+		}
+		if err := t.repo.Lock(key, now.Add(policy.lockout(excess))); err != nil {
+			return err
+		}
+	}
+
+	if rand.Float64() < staleThrottleCleanupChance {
+		if err := t.repo.DeleteStale(now.Add(-24 * time.Hour)); err != nil {
+			log.Printf("failed to delete stale throttle counters: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (t throttle) reset(key string) error {
+	return t.repo.Reset(key)
+}
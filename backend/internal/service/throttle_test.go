@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/repository/memory"
+)
+
+func TestThrottlePolicyLockout(t *testing.T) {
+	policy := throttlePolicy{baseLockout: time.Minute, maxLockout: time.Hour}
+
+	tests := []struct {
+		excess int
+		want   time.Duration
+	}{
+		{excess: 1, want: time.Minute},
+		{excess: 2, want: 2 * time.Minute},
+		{excess: 6, want: 32 * time.Minute},
+		{excess: 7, want: time.Hour},
+		// 1m<<29 overflows int64 nanoseconds
+		{excess: 30, want: time.Hour},
+		{excess: 64, want: time.Hour},
+		{excess: 1 << 20, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := policy.lockout(tt.excess); got != tt.want {
+			t.Errorf("lockout(%d) = %s, want %s", tt.excess, got, tt.want)
+		}
+	}
+}
+
+func TestThrottlePolicyLockoutIsNeverNegative(t *testing.T) {
+	for _, policy := range []throttlePolicy{loginAccountPolicy, loginIPPolicy, signupIPPolicy} {
+		for excess := 1; excess <= 200; excess++ {
+			lockout := policy.lockout(excess)
+			if lockout <= 0 || lockout > policy.maxLockout {
+				t.Fatalf("%slockout(%d) = %s, want within (0, %s]", policy.prefix, excess, lockout, policy.maxLockout)
+			}
+		}
+	}
+}
+
+func TestThrottleLocksOutAfterFreeAttempts(t *testing.T) {
+	th := throttle{repo: memory.NewRepositories().AuthThrottle}
+	key := throttleKey(loginAccountPolicy, " Someone@Example.com ")
+	keys := map[string]throttlePolicy{key: loginAccountPolicy}
+
+	if key != throttleKey(loginAccountPolicy, "someone@example.com") {
+		t.Errorf("throttleKey() = %q, want it to ignore case and surrounding space", key)
+	}
+
+	for i := 0; i < loginAccountPolicy.freeAttempts; i++ {
+		if err := th.fail(keys); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := th.check(keys); err != nil {
+		t.Fatalf("check() after %d failures error = %v, want nil", loginAccountPolicy.freeAttempts, err)
+	}
+
+	if err := th.fail(keys); err != nil {
+		t.Fatal(err)
+	}
+	var throttled *ThrottledError
+	if err := th.check(keys); !errors.As(err, &throttled) {
+		t.Fatalf("check() error = %v, want ThrottledError", err)
+	}
+	if throttled.RetryAfter <= 0 || throttled.RetryAfter > loginAccountPolicy.baseLockout {
+		t.Errorf("RetryAfter = %s, want within (0, %s]", throttled.RetryAfter, loginAccountPolicy.baseLockout)
+	}
+
+	if err := th.reset(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := th.check(keys); err != nil {
+		t.Errorf("check() after reset error = %v, want nil", err)
+	}
+}
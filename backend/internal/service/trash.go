@@ -0,0 +1,281 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+func deletedProjectContract(project *models.Project, orgUUID, ownerUUID string) *contracts.Project {
+	return &contracts.Project{
+		ID:               project.ID,
+		UUID:             project.UUID,
+		Name:             project.Name,
+		Code:             project.Code,
+		UserUUID:         ownerUUID,
+		OrganisationUUID: orgUUID,
+		CreatedAt:        project.CreatedAt,
+		UpdatedAt:        project.UpdatedAt,
+		DeletedAt:        project.DeletedAt,
+		DeletedBy:        project.DeletedBy.String,
+	}
+}
+
+func deletedEndpointContract(endpoint *models.Endpoint, projectUUID string) *contracts.Endpoint {
+	return &contracts.Endpoint{
+		ID:              endpoint.ID,
+		UUID:            endpoint.UUID,
+		Method:          endpoint.Method,
+		Path:            endpoint.Path,
+		ResponseBody:    endpoint.ResponseBody,
+		ResponseStatus:  endpoint.ResponseStatus,
+		ResponseHeaders: endpoint.ResponseHeaders,
+		ProjectUUID:     projectUUID,
+		CreatedAt:       endpoint.CreatedAt,
+		UpdatedAt:       endpoint.UpdatedAt,
+		CreatedBy:       endpoint.CreatedBy.String,
+		UpdatedBy:       endpoint.UpdatedBy.String,
+		DeletedAt:       endpoint.DeletedAt,
+		DeletedBy:       endpoint.DeletedBy.String,
+	}
+}
+
+// GetTrash lists the deleted projects of an organisation and the deleted
+// endpoints of its remaining projects.
+func (s *projectService) GetTrash(orgUUID string, userID int) (*contracts.OrganisationTrash, error) {
+	org, err := s.orgRepo.GetByUUID(orgUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("organisation not found")
+		}
+		return nil, err
+	}
+
+	if err := s.auth.authorize(userID, org.ID, ActionView); err != nil {
+		return nil, err
+	}
+
+	projects, err := s.repo.GetDeletedByOrganisationID(org.ID)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := s.endpointRepo.GetDeletedByOrganisationID(org.ID)
+	if err != nil {
+		return nil, err
+	}
+	liveProjects, err := s.repo.GetByOrganisationID(org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectUUIDs := make(map[int]string, len(liveProjects))
+	for _, project := range liveProjects {
+		projectUUIDs[project.ID] = project.UUID
+	}
+
+	owners := make(map[int]string)
+	trash := &contracts.OrganisationTrash{
+		Projects:  make([]*contracts.Project, 0, len(projects)),
+		Endpoints: make([]*contracts.Endpoint, 0, len(endpoints)),
+	}
+	for _, project := range projects {
+		if _, ok := owners[project.UserID]; !ok {
+			owner, err := s.userRepo.GetByID(project.UserID)
+			if err != nil {
+				return nil, err
+			}
+			owners[project.UserID] = owner.UUID
+		}
+		trash.Projects = append(trash.Projects, deletedProjectContract(project, org.UUID, owners[project.UserID]))
+	}
+	for _, endpoint := range endpoints {
+		trash.Endpoints = append(trash.Endpoints, deletedEndpointContract(endpoint, projectUUIDs[endpoint.ProjectID]))
+	}
+
+	return trash, nil
+}
+
+// RestoreProject brings back a deleted project together with the endpoints that
+// were deleted along with it. Endpoints deleted before the project stay in the
+// trash. The project keeps its code but not its custom hostname.
+func (s *projectService) RestoreProject(projectUUID string, userID int, client contracts.ClientInfo) (*contracts.Project, error) {
+	project, err := s.repo.GetDeletedByUUID(projectUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+
+	if err := s.auth.authorizeProject(project, userID, ActionManage); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(project.OrganisationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("organisation not found")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(project.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedAt := *project.DeletedAt
+	now := time.Now()
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Project.Restore(project.ID, user.UUID, now); err != nil {
+			return err
+		}
+		if err := repos.Endpoint.RestoreByProjectID(project.ID, deletedAt, user.UUID, now); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: org.ID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionRestore,
+			EntityType:     AuditEntityProject,
+			EntityUUID:     project.UUID,
+			After:          projectAuditFields(project, org.UUID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.Project{
+		ID:               project.ID,
+		UUID:             project.UUID,
+		Name:             project.Name,
+		Code:             project.Code,
+		UserUUID:         owner.UUID,
+		OrganisationUUID: org.UUID,
+		CreatedAt:        project.CreatedAt,
+		UpdatedAt:        &now,
+	}, nil
+}
+
+// PurgeTrash permanently removes projects and endpoints that have been deleted
+// for longer than retention. It reports how many of each were removed.
+func (s *projectService) PurgeTrash(retention time.Duration) (projects, endpoints int64, err error) {
+	cutoff := time.Now().Add(-retention)
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		var err error
+		if endpoints, err = repos.Endpoint.PurgeDeletedBefore(cutoff); err != nil {
+			return err
+		}
+		projects, err = repos.Project.PurgeDeletedBefore(cutoff)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return projects, endpoints, nil
+}
+
+// GetTrash lists the deleted endpoints of a project.
+func (s *endpointService) GetTrash(projectUUID string, userID int) ([]*contracts.Endpoint, error) {
+	project, err := s.getProjectForUser(projectUUID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := s.repo.GetDeletedByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*contracts.Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result = append(result, deletedEndpointContract(endpoint, project.UUID))
+	}
+
+	return result, nil
+}
+
+// RestoreEndpoint brings back a deleted endpoint of a live project, unless
+// another endpoint has taken its method and path in the meantime.
+func (s *endpointService) RestoreEndpoint(endpointUUID string, userID int, client contracts.ClientInfo) (*contracts.Endpoint, error) {
+	endpoint, err := s.repo.GetDeletedByUUID(endpointUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("endpoint not found")
+		}
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(endpoint.ProjectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+
+	if err := s.auth.authorizeProject(project, userID, ActionEdit); err != nil {
+		if err.Error() == "project not found" {
+			return nil, errors.New("endpoint not found")
+		}
+		return nil, err
+	}
+
+	existingEndpoint, err := s.repo.GetByProjectIDAndPath(project.ID, endpoint.Path, endpoint.Method)
+	if err == nil && existingEndpoint != nil {
+		return nil, errors.New("endpoint with same method and path already exists")
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.Endpoint.Restore(endpoint.ID, user.UUID, now); err != nil {
+			return err
+		}
+		return auditor{repo: repos.AuditLog}.record(user, client, auditEvent{
+			OrganisationID: project.OrganisationID,
+			ProjectUUID:    project.UUID,
+			Action:         AuditActionRestore,
+			EntityType:     AuditEntityEndpoint,
+			EntityUUID:     endpoint.UUID,
+			After:          endpointAuditFields(endpoint),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.UpdatedAt = &now
+	endpoint.UpdatedBy = sql.NullString{String: user.UUID, Valid: true}
+	return &contracts.Endpoint{
+		ID:              endpoint.ID,
+		UUID:            endpoint.UUID,
+		Method:          endpoint.Method,
+		Path:            endpoint.Path,
+		ResponseBody:    endpoint.ResponseBody,
+		ResponseStatus:  endpoint.ResponseStatus,
+		ResponseHeaders: endpoint.ResponseHeaders,
+		ProjectUUID:     project.UUID,
+		CreatedAt:       endpoint.CreatedAt,
+		UpdatedAt:       endpoint.UpdatedAt,
+		CreatedBy:       endpoint.CreatedBy.String,
+		UpdatedBy:       endpoint.UpdatedBy.String,
+	}, nil
+}
@@ -4,12 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
-	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/mail"
 	"github.com/crudboxin/crudbox/internal/models"
 	"github.com/crudboxin/crudbox/internal/repository"
 )
@@ -18,19 +19,42 @@ type userService struct {
 	repo        repository.UserRepository
 	orgRepo     repository.OrganisationRepository
 	userOrgRepo repository.UserOrganisationMappingRepository
-	jwtSecret   []byte
+	sessionRepo repository.UserSessionRepository
+	tokenRepo   repository.UserTokenRepository
+	transactor  repository.Transactor
+	mailer      mail.Sender
+	authConfig  AuthConfig
+	sessions    sessionIssuer
+	throttle    throttle
 }
 
-func NewUserService(repo repository.UserRepository, orgRepo repository.OrganisationRepository, userOrgRepo repository.UserOrganisationMappingRepository, jwtSecret []byte) UserService {
+func NewUserService(repo repository.UserRepository, orgRepo repository.OrganisationRepository, userOrgRepo repository.UserOrganisationMappingRepository, sessionRepo repository.UserSessionRepository, tokenRepo repository.UserTokenRepository, throttleRepo repository.AuthThrottleRepository, transactor repository.Transactor, mailer mail.Sender, authConfig AuthConfig) UserService {
 	return &userService{
 		repo:        repo,
 		orgRepo:     orgRepo,
 		userOrgRepo: userOrgRepo,
-		jwtSecret:   jwtSecret,
+		sessionRepo: sessionRepo,
+		tokenRepo:   tokenRepo,
+		transactor:  transactor,
+		mailer:      mailer,
+		authConfig:  authConfig,
+		sessions:    sessionIssuer{sessionRepo: sessionRepo, authConfig: authConfig},
+		throttle:    throttle{repo: throttleRepo},
 	}
 }
 
-func (s *userService) SignUp(req *contracts.SignUpRequest) error {
+func (s *userService) SignUp(req *contracts.SignUpRequest, client contracts.ClientInfo) error {
+	// Every attempt counts towards the per-IP limit, successful or not
+	if client.IPAddress != "" {
+		keys := map[string]throttlePolicy{throttleKey(signupIPPolicy, client.IPAddress): signupIPPolicy}
+		if err := s.throttle.check(keys); err != nil {
+			return err
+		}
+		if err := s.throttle.fail(keys); err != nil {
+			return err
+		}
+	}
+
 	// Check if user exists
 	existingUser, err := s.repo.GetByEmail(req.Email)
 	if err == nil && existingUser != nil {
@@ -54,26 +78,48 @@ func (s *userService) SignUp(req *contracts.SignUpRequest) error {
 		},
 	}
 
-	return s.repo.Create(user)
+	if err := s.repo.Create(user); err != nil {
+		return err
+	}
+
+	// The account exists either way; a failed mail can be resent by the user
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Printf("failed to send verification email to %s: %v", user.Email, err)
+	}
+
+	return nil
 }
 
-func (s *userService) Login(req *contracts.LoginRequest) (string, error) {
+func (s *userService) Login(req *contracts.LoginRequest, client contracts.ClientInfo) (*contracts.AuthTokens, error) {
+	accountKey := throttleKey(loginAccountPolicy, req.Email)
+	keys := map[string]throttlePolicy{accountKey: loginAccountPolicy}
+	if client.IPAddress != "" {
+		keys[throttleKey(loginIPPolicy, client.IPAddress)] = loginIPPolicy
+	}
+	if err := s.throttle.check(keys); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.GetByEmail(req.Email)
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	}
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		if err := s.throttle.fail(keys); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid credentials")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return "", errors.New("invalid credentials")
+	if err := s.throttle.reset(accountKey); err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":   user.ID,
-		"user_uuid": user.UUID,
-		"exp":       time.Now().Add(time.Hour * 24).Unix(),
-	})
+	if s.authConfig.RequireEmailVerification && user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
 
-	return token.SignedString(s.jwtSecret)
+	return s.sessions.startSession(user, client)
 }
 
 func (s *userService) GetByID(id int) (*contracts.User, error) {
@@ -102,6 +148,7 @@ func (s *userService) GetByID(id int) (*contracts.User, error) {
 			UUID:      org.UUID,
 			Name:      org.Name,
 			UserUUID:  owner.UUID,
+			Role:      mapping.Role,
 			CreatedAt: org.CreatedAt,
 			UpdatedAt: org.UpdatedAt,
 		})
@@ -111,6 +158,7 @@ func (s *userService) GetByID(id int) (*contracts.User, error) {
 		ID:            dbUser.ID,
 		UUID:          dbUser.UUID,
 		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt != nil,
 		Organisations: organisations,
 		CreatedAt:     dbUser.CreatedAt,
 		UpdatedAt:     dbUser.UpdatedAt,
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/mail"
+	"github.com/crudboxin/crudbox/internal/models"
+	"github.com/crudboxin/crudbox/internal/repository"
+)
+
+// Purposes of the single-use tokens mailed to users.
+const (
+	tokenPurposeEmailVerification = "email_verification"
+	tokenPurposePasswordReset     = "password_reset"
+)
+
+const (
+	emailVerificationTTL = 48 * time.Hour
+	passwordResetTTL     = time.Hour
+)
+
+var (
+	ErrEmailNotVerified = errors.New("email address has not been verified")
+	ErrInvalidUserToken = errors.New("invalid or expired token")
+)
+
+// RequestPasswordReset mails a reset link if an account exists for the email.
+// It succeeds either way so that it cannot be used to discover accounts.
+func (s *userService) RequestPasswordReset(req *contracts.ForgotPasswordRequest) error {
+	user, err := s.repo.GetByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.createUserToken(user, tokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Reset your crudbox password",
+		Body: fmt.Sprintf(
+			"Someone asked to reset the password of your crudbox account.\n\nChoose a new password here within the next hour:\n%s\n\nIf this wasn't you, you can ignore this email.\n",
+			s.appLink("/reset-password", token),
+		),
+	})
+}
+
+// ResetPassword sets a new password using a mailed reset token and ends every
+// session of the user. The token is only used up if all of that succeeds.
+func (s *userService) ResetPassword(req *contracts.ResetPasswordRequest) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		userToken, user, err := consumeUserToken(repos, req.Token, tokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.User.UpdatePassword(user.ID, string(hashedPassword), user.UUID); err != nil {
+			return err
+		}
+
+		// Receiving the reset link proves ownership of the address
+		if user.EmailVerifiedAt == nil {
+			if err := repos.User.MarkEmailVerified(user.ID, *userToken.UsedAt); err != nil {
+				return err
+			}
+		}
+
+		return repos.Session.RevokeByUserID(user.ID, user.UUID)
+	})
+}
+
+func (s *userService) VerifyEmail(req *contracts.VerifyEmailRequest) error {
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		userToken, user, err := consumeUserToken(repos, req.Token, tokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+
+		return repos.User.MarkEmailVerified(user.ID, *userToken.UsedAt)
+	})
+}
+
+// ResendVerificationEmail mails a fresh verification link to an unverified
+// account. Like RequestPasswordReset it does not reveal whether one exists.
+func (s *userService) ResendVerificationEmail(req *contracts.ResendVerificationRequest) error {
+	user, err := s.repo.GetByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+func (s *userService) sendVerificationEmail(user *models.User) error {
+	token, err := s.createUserToken(user, tokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Verify your crudbox email address",
+		Body: fmt.Sprintf(
+			"Welcome to crudbox!\n\nPlease confirm your email address by opening this link:\n%s\n",
+			s.appLink("/verify-email", token),
+		),
+	})
+}
+
+// createUserToken issues a single-use token for purpose, retiring any earlier
+// token of the same purpose so only the latest link works.
+func (s *userService) createUserToken(user *models.User, purpose string, ttl time.Duration) (string, error) {
+	if err := s.tokenRepo.InvalidateByUserID(user.ID, purpose); err != nil {
+		return "", err
+	}
+
+	token, tokenHash, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	userToken := &models.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		Base: models.Base{
+			CreatedAt: &now,
+			UpdatedAt: &now,
+			CreatedBy: sql.NullString{String: user.UUID, Valid: true},
+			UpdatedBy: sql.NullString{String: user.UUID, Valid: true},
+		},
+	}
+
+	if err := s.tokenRepo.Create(userToken); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeUserToken validates a mailed token and marks it used. It runs inside
+// the caller's transaction, so the token is given back if the caller fails.
+func consumeUserToken(repos *repository.Repositories, token, purpose string) (*models.UserToken, *models.User, error) {
+	userToken, err := repos.UserToken.GetByTokenHash(hashToken(token), purpose)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrInvalidUserToken
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if !userToken.ExpiresAt.After(now) {
+		return nil, nil, ErrInvalidUserToken
+	}
+
+	user, err := repos.User.GetByID(userToken.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrInvalidUserToken
+		}
+		return nil, nil, err
+	}
+
+	if err := repos.UserToken.MarkUsed(userToken.ID, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrInvalidUserToken
+		}
+		return nil, nil, err
+	}
+	userToken.UsedAt = &now
+
+	return userToken, user, nil
+}
+
+func (s *userService) appLink(path, token string) string {
+	return strings.TrimSuffix(s.authConfig.AppBaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crudboxin/crudbox/internal/contracts"
+	"github.com/crudboxin/crudbox/internal/repository"
+	"github.com/crudboxin/crudbox/internal/repository/memory"
+)
+
+var errRevokeFailed = errors.New("revoke failed")
+
+// failingRevokeTransactor runs transactions whose session repository cannot
+// revoke sessions, the last step of a password reset.
+type failingRevokeTransactor struct {
+	repository.Transactor
+}
+
+func (t failingRevokeTransactor) WithinTransaction(fn func(repos *repository.Repositories) error) error {
+	return t.Transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		repos.Session = failingRevokeSessions{repos.Session}
+		return fn(repos)
+	})
+}
+
+type failingRevokeSessions struct {
+	repository.UserSessionRepository
+}
+
+func (failingRevokeSessions) RevokeByUserID(userID int, revokedBy string) error {
+	return errRevokeFailed
+}
+
+func TestResetPassword(t *testing.T) {
+	env := newTestEnv(t)
+	tokens := env.login("user@example.com")
+	user, err := env.repos.User.GetByEmail("user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := env.services.User.(*userService).createUserToken(user, tokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.services.User.ResetPassword(&contracts.ResetPasswordRequest{Token: token, Password: "new-password"}); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if _, err := env.services.User.Login(&contracts.LoginRequest{Email: user.Email, Password: "new-password"}, testClient); err != nil {
+		t.Errorf("Login() with the new password error = %v", err)
+	}
+	if _, err := env.services.User.RefreshToken(&contracts.RefreshTokenRequest{RefreshToken: tokens.RefreshToken}, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken() of a session from before the reset error = %v, want ErrInvalidRefreshToken", err)
+	}
+	if err := env.services.User.ResetPassword(&contracts.ResetPasswordRequest{Token: token, Password: "other-password"}); !errors.Is(err, ErrInvalidUserToken) {
+		t.Errorf("ResetPassword() with a used token error = %v, want ErrInvalidUserToken", err)
+	}
+}
+
+func TestFailedResetPasswordKeepsToken(t *testing.T) {
+	repos := memory.NewRepositories()
+	transactor := repos.Transactor
+	env := newTestEnvWithRepos(t, repos, NewMockHosts(""))
+	env.login("user@example.com")
+	user, err := env.repos.User.GetByEmail("user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := env.services.User.(*userService).createUserToken(user, tokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.services.User.(*userService).transactor = failingRevokeTransactor{transactor}
+	if err := env.services.User.ResetPassword(&contracts.ResetPasswordRequest{Token: token, Password: "new-password"}); !errors.Is(err, errRevokeFailed) {
+		t.Fatalf("ResetPassword() error = %v, want errRevokeFailed", err)
+	}
+	if _, err := env.services.User.Login(&contracts.LoginRequest{Email: user.Email, Password: "password"}, testClient); err != nil {
+		t.Errorf("Login() with the old password after a failed reset error = %v", err)
+	}
+
+	// The link still works once the failure is gone
+	env.services.User.(*userService).transactor = transactor
+	if err := env.services.User.ResetPassword(&contracts.ResetPasswordRequest{Token: token, Password: "new-password"}); err != nil {
+		t.Errorf("ResetPassword() retry error = %v", err)
+	}
+}
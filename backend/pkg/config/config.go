@@ -1,18 +1,76 @@
 package config
 
 import (
+	"errors"
 	"log"
+	"net/url"
 	"os"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/joho/godotenv"
 )
 
+// DefaultJWTSecret is only acceptable in development; see Config.Validate.
+const DefaultJWTSecret = "your-secret-key"
+
+// DefaultAPIBaseURL is where the API runs locally. Elsewhere API_BASE_URL must
+// name the public URL, or the API's host would not be kept from mocks.
+const DefaultAPIBaseURL = "http://localhost:8080"
+
 type Config struct {
-	JWTSecret string
-	DB        DatabaseConfig
+	// Environment is "development" for local work; anything else is treated as production.
+	Environment              string
+	JWTSecret                string
+	JWTKeys                  string
+	JWTKeysFile              string
+	AccessTokenTTL           time.Duration
+	RefreshTokenTTL          time.Duration
+	RequireEmailVerification bool
+	AppBaseURL               string
+	// APIBaseURL is where this API is served. Its host, like the host of
+	// AppBaseURL, is never routed to mocks.
+	APIBaseURL     string
+	MockBaseDomain string
+	// CustomHostnames lets project admins serve mocks on hostnames of their own.
+	CustomHostnames bool
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For header names
+	// the client; TrustedPlatform is a header the hosting platform sets instead.
+	TrustedProxies  []string
+	TrustedPlatform string
+	// TrashRetention is how long deleted projects and endpoints can be restored
+	// before they are purged.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+	DB                 DatabaseConfig
+	Mail               MailConfig
+	OIDC               OIDCConfig
+}
+
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       string
+}
+
+type MailConfig struct {
+	Driver   string
+	From     string
+	SMTPHost string
+	SMTPPort string
+	Username string
+	Password string
+	FileDir  string
 }
 
 type DatabaseConfig struct {
+	// Driver is "postgres", "sqlite" or "memory". SQLite keeps everything in the
+	// file at Path and ignores the connection settings; memory keeps nothing.
+	Driver   string
+	Path     string
 	Host     string
 	Port     string
 	User     string
@@ -28,8 +86,24 @@ func Load() *Config {
 	}
 
 	return &Config{
-		JWTSecret: getEnv("JWT_SECRET", "your-secret-key"),
+		Environment:              getEnv("APP_ENV", "production"),
+		JWTSecret:                getEnv("JWT_SECRET", DefaultJWTSecret),
+		JWTKeys:                  getEnv("JWT_KEYS", ""),
+		JWTKeysFile:              getEnv("JWT_KEYS_FILE", ""),
+		AccessTokenTTL:           getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:          getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		RequireEmailVerification: getEnv("REQUIRE_EMAIL_VERIFICATION", "false") == "true",
+		AppBaseURL:               getEnv("APP_BASE_URL", "http://localhost:3000"),
+		APIBaseURL:               getEnv("API_BASE_URL", DefaultAPIBaseURL),
+		MockBaseDomain:           getEnv("MOCK_BASE_DOMAIN", ""),
+		CustomHostnames:          getEnv("CUSTOM_HOSTNAMES", "false") == "true",
+		TrustedProxies:           strings.FieldsFunc(getEnv("TRUSTED_PROXIES", ""), isListSeparator),
+		TrustedPlatform:          getEnv("TRUSTED_PLATFORM", ""),
+		TrashRetention:           getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval:       getDurationEnv("TRASH_PURGE_INTERVAL", time.Hour),
 		DB: DatabaseConfig{
+			Driver:   getEnv("DB_DRIVER", "postgres"),
+			Path:     getEnv("DB_PATH", "crudbox.db"),
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
 			User:     getEnv("DB_USER", "postgres"),
@@ -37,12 +111,75 @@ func Load() *Config {
 			DBName:   getEnv("DB_NAME", "crudbox_core"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
+		Mail: MailConfig{
+			Driver:   getEnv("MAIL_DRIVER", "log"),
+			From:     getEnv("MAIL_FROM", "crudbox <no-reply@localhost>"),
+			SMTPHost: getEnv("SMTP_HOST", ""),
+			SMTPPort: getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			FileDir:  getEnv("MAIL_FILE_DIR", "mail"),
+		},
+		OIDC: OIDCConfig{
+			IssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/auth/oidc/callback"),
+			Scopes:       getEnv("OIDC_SCOPES", "openid email profile"),
+		},
 	}
 }
 
+func (c *Config) IsDevelopment() bool {
+	return c.Environment == "development"
+}
+
+// Validate rejects configurations that are unsafe to run outside development.
+func (c *Config) Validate() error {
+	if c.IsDevelopment() {
+		return nil
+	}
+	if c.JWTSecret == DefaultJWTSecret && c.JWTKeys == "" && c.JWTKeysFile == "" {
+		return errors.New("JWT_SECRET is set to the default value; configure JWT_KEYS_FILE, JWT_KEYS or a strong JWT_SECRET, or set APP_ENV=development")
+	}
+	if c.APIBaseURL == DefaultAPIBaseURL {
+		return errors.New("API_BASE_URL is not set; set it to the public URL of the API so its host is never routed to mocks, or set APP_ENV=development")
+	}
+	return nil
+}
+
+// ReservedHosts returns the hosts of the API and the dashboard, which projects
+// cannot register as custom hostnames.
+func (c *Config) ReservedHosts() []string {
+	var hosts []string
+	for _, base := range []string{c.APIBaseURL, c.AppBaseURL} {
+		if parsed, err := url.Parse(base); err == nil && parsed.Hostname() != "" {
+			hosts = append(hosts, parsed.Hostname())
+		}
+	}
+	return hosts
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid duration %q for %s, using %s", value, key, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	production := func() *Config {
+		return &Config{Environment: "production", JWTSecret: "a-strong-secret", APIBaseURL: "https://api.crudbox.example"}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{name: "production", modify: func(c *Config) {}},
+		{name: "default JWT secret", modify: func(c *Config) { c.JWTSecret = DefaultJWTSecret }, want: "JWT_SECRET"},
+		{name: "default JWT secret with keys", modify: func(c *Config) { c.JWTSecret, c.JWTKeysFile = DefaultJWTSecret, "keys.json" }},
+		{name: "default API base URL", modify: func(c *Config) { c.APIBaseURL = DefaultAPIBaseURL }, want: "API_BASE_URL"},
+		{name: "default API base URL with keys", modify: func(c *Config) { c.APIBaseURL, c.JWTKeys = DefaultAPIBaseURL, "{}" }, want: "API_BASE_URL"},
+		{name: "development", modify: func(c *Config) {
+			c.Environment, c.JWTSecret, c.APIBaseURL = "development", DefaultJWTSecret, DefaultAPIBaseURL
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := production()
+			tt.modify(c)
+			err := c.Validate()
+			if tt.want == "" && err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("Validate() error = %v, want it to mention %s", err, tt.want)
+			}
+		})
+	}
+}
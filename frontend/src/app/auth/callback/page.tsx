@@ -0,0 +1,56 @@
+'use client';
+
+import { useEffect, useRef, useState } from 'react';
+import Link from 'next/link';
+import { useRouter } from 'next/navigation';
+import { useAuth } from '@/contexts/AuthContext';
+
+// Single sign-on lands here with the tokens (or an error) in the URL fragment.
+export default function AuthCallback() {
+  const [error, setError] = useState('');
+  const { loginWithTokens, user } = useAuth();
+  const router = useRouter();
+  const handled = useRef(false);
+
+  useEffect(() => {
+    if (user) {
+      router.push((user.organisations || []).length > 0 ? '/dashboard' : '/organisation');
+    }
+  }, [user, router]);
+
+  useEffect(() => {
+    if (handled.current) return;
+    handled.current = true;
+
+    const params = new URLSearchParams(window.location.hash.slice(1));
+    window.history.replaceState(null, '', window.location.pathname);
+
+    const token = params.get('token');
+    const refreshToken = params.get('refresh_token');
+    if (!token || !refreshToken) {
+      setError(params.get('error') || 'Single sign-on failed');
+      return;
+    }
+
+    loginWithTokens(token, refreshToken).catch(() => setError('Single sign-on failed'));
+  }, [loginWithTokens]);
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-white py-12 px-4 sm:px-6 lg:px-8 text-black">
+      <div className="max-w-md w-full space-y-8">
+        {error ? (
+          <div className="space-y-4">
+            <div className="bg-red-50 border border-red-200 text-red-600 px-4 py-3 rounded">
+              {error}
+            </div>
+            <Link href="/login" className="block text-center font-medium text-black underline-offset-4 hover:underline">
+              Back to sign in
+            </Link>
+          </div>
+        ) : (
+          <p className="text-center text-gray-600">Signing you in...</p>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -4,6 +4,7 @@ import { useState, useEffect } from 'react';
 import { useRouter } from 'next/navigation';
 import Link from 'next/link';
 import { useAuth } from '@/contexts/AuthContext';
+import { API_BASE_URL } from '@/lib/api';
 import { Mail, Lock, Eye, EyeOff } from 'lucide-react';
 
 export default function Login() {
@@ -124,6 +125,12 @@ export default function Login() {
             </div>
           </div>
 
+          <div className="text-sm text-right">
+            <Link href="/forgot-password" className="font-medium text-black underline-offset-4 hover:underline">
+              Forgot your password?
+            </Link>
+          </div>
+
           <div>
             <button
               type="submit"
@@ -133,6 +140,17 @@ export default function Login() {
               {isLoading ? 'Signing in...' : 'Sign in'}
             </button>
           </div>
+
+          {process.env.NEXT_PUBLIC_OIDC_ENABLED === 'true' && (
+            <div>
+              <a
+                href={`${API_BASE_URL}/auth/oidc/login`}
+                className="w-full flex justify-center py-2 px-4 text-sm font-medium rounded-md text-black border border-gray-300 hover:bg-gray-50"
+              >
+                Sign in with SSO
+              </a>
+            </div>
+          )}
         </form>
       </div>
     </div>
@@ -0,0 +1,86 @@
+'use client';
+
+import { Suspense, useState } from 'react';
+import { useRouter, useSearchParams } from 'next/navigation';
+import { Lock } from 'lucide-react';
+import { authAPI } from '@/lib/api';
+
+function ResetPasswordForm() {
+  const [password, setPassword] = useState('');
+  const [error, setError] = useState('');
+  const [isLoading, setIsLoading] = useState(false);
+
+  const token = useSearchParams().get('token') || '';
+  const router = useRouter();
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError('');
+    setIsLoading(true);
+
+    try {
+      await authAPI.resetPassword(token, password);
+      router.push('/login');
+    } catch (err: unknown) {
+      setError((err as Error & { response?: { data?: { error?: string } } })?.response?.data?.error || 'Password reset failed');
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  return (
+    <form className="mt-8 space-y-6" onSubmit={handleSubmit}>
+      {error && (
+        <div className="bg-red-50 border border-red-200 text-red-600 px-4 py-3 rounded">
+          {error}
+        </div>
+      )}
+
+      <div>
+        <label htmlFor="password" className="block text-sm font-medium text-gray-700">
+          New password
+        </label>
+        <div className="mt-1 relative">
+          <div className="absolute inset-y-0 left-0 pl-3 flex items-center pointer-events-none">
+            <Lock className="h-5 w-5 text-gray-400" />
+          </div>
+          <input
+            id="password"
+            name="password"
+            type="password"
+            autoComplete="new-password"
+            required
+            minLength={6}
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            className="appearance-none block w-full pl-10 pr-3 py-2 border border-gray-300 rounded-md placeholder-gray-400 text-black bg-white focus:outline-none focus:ring-black focus:border-black"
+            placeholder="Choose a new password"
+          />
+        </div>
+      </div>
+
+      <button
+        type="submit"
+        disabled={isLoading || !token}
+        className="group relative w-full flex justify-center py-2 px-4 text-sm font-medium rounded-md text-white bg-black hover:bg-neutral-900 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-black disabled:opacity-50 disabled:cursor-not-allowed"
+      >
+        {isLoading ? 'Saving...' : 'Set new password'}
+      </button>
+    </form>
+  );
+}
+
+export default function ResetPassword() {
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-white py-12 px-4 sm:px-6 lg:px-8 text-black">
+      <div className="max-w-md w-full space-y-8">
+        <h2 className="mt-6 text-center text-3xl font-extrabold text-black">
+          Choose a new password
+        </h2>
+        <Suspense>
+          <ResetPasswordForm />
+        </Suspense>
+      </div>
+    </div>
+  );
+}
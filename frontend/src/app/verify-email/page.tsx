@@ -0,0 +1,63 @@
+'use client';
+
+import { Suspense, useEffect, useRef, useState } from 'react';
+import Link from 'next/link';
+import { useSearchParams } from 'next/navigation';
+import { authAPI } from '@/lib/api';
+
+function VerifyEmailStatus() {
+  const [status, setStatus] = useState<'verifying' | 'verified' | 'failed'>('verifying');
+  const [error, setError] = useState('');
+
+  const token = useSearchParams().get('token') || '';
+  // Tokens are single-use, so never submit one twice
+  const submitted = useRef(false);
+
+  useEffect(() => {
+    if (submitted.current) return;
+    submitted.current = true;
+
+    authAPI.verifyEmail(token)
+      .then(() => setStatus('verified'))
+      .catch((err: Error & { response?: { data?: { error?: string } } }) => {
+        setError(err.response?.data?.error || 'Verification failed');
+        setStatus('failed');
+      });
+  }, [token]);
+
+  if (status === 'verifying') {
+    return <p className="text-center text-gray-600">Verifying your email address...</p>;
+  }
+
+  if (status === 'failed') {
+    return (
+      <div className="bg-red-50 border border-red-200 text-red-600 px-4 py-3 rounded">
+        {error}
+      </div>
+    );
+  }
+
+  return (
+    <p className="text-center text-gray-600">
+      Your email address is verified.{' '}
+      <Link href="/login" className="font-medium text-black underline-offset-4 hover:underline">
+        Continue to sign in
+      </Link>
+    </p>
+  );
+}
+
+export default function VerifyEmail() {
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-white py-12 px-4 sm:px-6 lg:px-8 text-black">
+      <div className="max-w-md w-full space-y-8">
+        <h2 className="mt-6 text-center text-3xl font-extrabold text-black">
+          Email verification
+        </h2>
+        <Suspense>
+          <VerifyEmailStatus />
+        </Suspense>
+      </div>
+    </div>
+  );
+}
@@ -17,7 +17,7 @@ export default function RouteGuard({ children }: RouteGuardProps) {
     if (isLoading) return; // Still loading, don't redirect yet
 
     // Public routes that don't require authentication
-    const publicRoutes = ['/login', '/signup'];
+    const publicRoutes = ['/login', '/signup', '/forgot-password', '/reset-password', '/verify-email', '/auth/callback'];
     const isPublicRoute = publicRoutes.includes(pathname);
 
     // Organization creation route - allowed for authenticated users without organizations
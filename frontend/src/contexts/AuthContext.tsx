@@ -23,6 +23,7 @@ interface AuthContextType {
   token: string | null;
   login: (email: string, password: string) => Promise<void>;
   signup: (email: string, password: string) => Promise<void>;
+  loginWithTokens: (token: string, refreshToken: string) => Promise<void>;
   logout: () => void;
   refreshUser: () => Promise<void>;
   isLoading: boolean;
@@ -59,6 +60,7 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
         } catch {
           // Token might be invalid, clear it
           localStorage.removeItem('token');
+          localStorage.removeItem('refresh_token');
           setToken(null);
         }
       }
@@ -72,10 +74,11 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
     try {
       const { authAPI, userAPI } = await import('@/lib/api');
       const response = await authAPI.login(email, password);
-      const { token: newToken } = response.data;
+      const { token: newToken, refresh_token: refreshToken } = response.data;
 
       setToken(newToken);
       localStorage.setItem('token', newToken);
+      localStorage.setItem('refresh_token', refreshToken);
 
       // Get user info from backend
       const userResponse = await userAPI.getCurrentUser();
@@ -85,6 +88,17 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
     }
   };
 
+  // Used by single sign-on, where the backend hands over tokens after the redirect
+  const loginWithTokens = async (newToken: string, refreshToken: string) => {
+    setToken(newToken);
+    localStorage.setItem('token', newToken);
+    localStorage.setItem('refresh_token', refreshToken);
+
+    const { userAPI } = await import('@/lib/api');
+    const userResponse = await userAPI.getCurrentUser();
+    setUser(userResponse.data.user);
+  };
+
   const signup = async (email: string, password: string) => {
     try {
       const { authAPI } = await import('@/lib/api');
@@ -103,6 +117,7 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
       } catch {
         // Token might be invalid, clear it
         localStorage.removeItem('token');
+        localStorage.removeItem('refresh_token');
         setToken(null);
         setUser(null);
       }
@@ -110,15 +125,21 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
   };
 
   const logout = () => {
+    import('@/lib/api').then(({ authAPI }) => authAPI.logout()).catch(() => {
+      // The session may already be gone; clearing local state is enough
+    }).finally(() => {
+      localStorage.removeItem('token');
+      localStorage.removeItem('refresh_token');
+    });
     setUser(null);
     setToken(null);
-    localStorage.removeItem('token');
   };
 
   const value: AuthContextType = {
     user,
     token,
     login,
+    loginWithTokens,
     signup,
     logout,
     refreshUser,
@@ -1,6 +1,6 @@
 import axios from 'axios';
 
-const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080';
+export const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080';
 
 const api = axios.create({
   baseURL: API_BASE_URL,
@@ -18,6 +18,45 @@ api.interceptors.request.use((config) => {
   return config;
 });
 
+// Access tokens are short-lived; on a 401 exchange the refresh token for a new
+// pair once and retry the request. Concurrent failures share one refresh.
+let refreshRequest: Promise<string> | null = null;
+
+const refreshAccessToken = async (): Promise<string> => {
+  const refreshToken = localStorage.getItem('refresh_token');
+  if (!refreshToken) {
+    throw new Error('No refresh token');
+  }
+  const response = await axios.post(`${API_BASE_URL}/token/refresh`, { refresh_token: refreshToken });
+  localStorage.setItem('token', response.data.token);
+  localStorage.setItem('refresh_token', response.data.refresh_token);
+  return response.data.token;
+};
+
+api.interceptors.response.use(
+  (response) => response,
+  async (error) => {
+    const original = error.config;
+    if (error.response?.status !== 401 || !original || original._retried || !localStorage.getItem('refresh_token')) {
+      return Promise.reject(error);
+    }
+    original._retried = true;
+
+    try {
+      refreshRequest = refreshRequest || refreshAccessToken().finally(() => {
+        refreshRequest = null;
+      });
+      const token = await refreshRequest;
+      original.headers.Authorization = `Bearer ${token}`;
+      return api(original);
+    } catch {
+      localStorage.removeItem('token');
+      localStorage.removeItem('refresh_token');
+      return Promise.reject(error);
+    }
+  }
+);
+
 // Auth APIs
 export const authAPI = {
   signup: (email: string, password: string) =>
@@ -25,6 +64,21 @@ export const authAPI = {
 
   login: (email: string, password: string) =>
     api.post('/login', { email, password }),
+
+  forgotPassword: (email: string) =>
+    api.post('/password/forgot', { email }),
+
+  resetPassword: (token: string, password: string) =>
+    api.post('/password/reset', { token, password }),
+
+  verifyEmail: (token: string) =>
+    api.post('/email/verify', { token }),
+
+  logout: () =>
+    api.post('/logout'),
+
+  logoutAll: () =>
+    api.post('/logout/all'),
 };
 
 // User APIs